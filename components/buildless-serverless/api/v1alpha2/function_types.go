@@ -20,9 +20,12 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // Runtime specifies the name of the Function's runtime.
@@ -65,6 +68,11 @@ type FunctionSpec struct {
 	// +kubebuilder:validation:XValidation:message="Following envs are reserved and cannot be used: ['FUNC_RUNTIME','FUNC_HANDLER','FUNC_PORT','FUNC_HANDLER_SOURCE','FUNC_HANDLER_DEPENDENCIES','MOD_NAME','NODE_PATH','PYTHONPATH']",rule="(self.all(e, !(e.name in ['FUNC_RUNTIME','FUNC_HANDLER','FUNC_PORT','FUNC_HANDLER_SOURCE','FUNC_HANDLER_DEPENDENCIES','MOD_NAME','NODE_PATH','PYTHONPATH'])))"
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
+	// Specifies ConfigMaps and Secrets whose keys are used as environment variables for the Function.
+	// Variables defined in **Env** and variables set by Serverless take precedence over them.
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
 	// Specifies resources requested by the Function and the build Job.
 	// +optional
 	ResourceConfiguration *ResourceConfiguration `json:"resourceConfiguration,omitempty"`
@@ -82,14 +90,146 @@ type FunctionSpec struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Defines an event source used to scale the Function's Pods based on the number of pending events.
+	// The Function Controller creates a KEDA ScaledObject that targets the Function's scale subresource.
+	// +optional
+	// +kubebuilder:validation:XValidation:message="Use natsJetStream or kafka",rule="has(self.natsJetStream) && !has(self.kafka) || !has(self.natsJetStream) && has(self.kafka)"
+	// +kubebuilder:validation:XValidation:message="MinReplicas cannot be greater than maxReplicas",rule="!has(self.minReplicas) || !has(self.maxReplicas) || self.minReplicas <= self.maxReplicas"
+	EventScaling *EventScaling `json:"eventScaling,omitempty"`
+
+	// Configures the PodDisruptionBudget of the Function's Pods.
+	// When not set, a PodDisruptionBudget with **MaxUnavailable** set to `1` is created for Functions running more than one replica.
+	// +optional
+	// +kubebuilder:validation:XValidation:message="Use minAvailable or maxUnavailable",rule="!(has(self.minAvailable) && has(self.maxUnavailable))"
+	PodDisruptionBudget *PodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// Specifies how the Function's Pods are spread across the cluster topology domains.
+	// When not set, Pods of Functions running more than one replica are spread across zones and nodes.
+	// For configuration details, see the [official Kubernetes documentation](https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/).
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// Specifies labels that a node must have for the Function's Pods to be scheduled on it.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Specifies tolerations which allow the Function's Pods to be scheduled on nodes with matching taints.
+	// For configuration details, see the [official Kubernetes documentation](https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/).
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Specifies node affinity, Pod affinity, and Pod anti-affinity rules of the Function's Pods.
+	// For configuration details, see the [official Kubernetes documentation](https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#affinity-and-anti-affinity).
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// Specifies the name of the PriorityClass assigned to the Function's Pods.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Specifies the name of the RuntimeClass used to run the Function's Pods.
+	// +optional
+	RuntimeClassName *string `json:"runtimeClassName,omitempty"`
+
+	// Overrides timing and thresholds of the default startup, readiness, and liveness probes of the Function's container.
+	// +optional
+	Probes *Probes `json:"probes,omitempty"`
+
+	// Specifies the duration in seconds the Function's Pod needs to terminate gracefully. Defaults to `30`.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
+
+	// Specifies the handler called immediately before the Function's container is terminated.
+	// For configuration details, see the [official Kubernetes documentation](https://kubernetes.io/docs/concepts/containers/container-lifecycle-hooks/).
+	// +optional
+	PreStop *corev1.LifecycleHandler `json:"preStop,omitempty"`
+
+	// Specifies the maximum time in seconds the Function has to handle a single request. Defaults to `180`.
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// Specifies the maximum size of the request body accepted by the Function, for example `5Mi`.
+	// The value must be a multiple of `1Mi`. Defaults to `1Mi` for Node.js runtimes, and no limit for Python runtimes.
+	// +optional
+	MaxRequestBodySize *resource.Quantity `json:"maxRequestBodySize,omitempty"`
+
 	// Deprecated: Use **Labels** and **Annotations** to label and/or annotate Function's Pods.
 	// +optional
 	// +kubebuilder:validation:XValidation:message="Not supported: Use spec.labels and spec.annotations to label and/or annotate Function's Pods.",rule="!has(self.labels) && !has(self.annotations)"
 	Template *Template `json:"template,omitempty"`
 
+	// Configures a NetworkPolicy isolating the Function's Pods. When not set, no NetworkPolicy is created.
+	// The policy always allows outgoing traffic to DNS, the package registry, istiod, and the configured trace collector and event publisher endpoints.
+	// +optional
+	NetworkPolicy *FunctionNetworkPolicy `json:"networkPolicy,omitempty"`
+
+	// Configures the Service exposing the Function. When not set, a `ClusterIP` Service exposing port `80` is created.
+	// +optional
+	Service *FunctionService `json:"service,omitempty"`
+
+	// Exposes the Function outside the cluster. The Function is exposed with an APIRule when the APIRule CustomResourceDefinition is installed,
+	// and with a Gateway API HTTPRoute otherwise. The URL of an HTTPRoute is published after its gateway accepts it.
+	// When not set, the Function is not exposed.
+	// +optional
+	Expose *FunctionExpose `json:"expose,omitempty"`
+
+	// Defines schedules invoking the Function periodically. Each schedule creates a CronJob sending an HTTP `POST` request to the Function's Service.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Schedules []FunctionSchedule `json:"schedules,omitempty"`
+
+	// Defines CloudEvents consumed by the Function. Each entry creates a Subscription delivering the events to the Function's Service.
+	// Requires the Kyma Eventing module.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Events []FunctionEventSubscription `json:"events,omitempty"`
+
+	// Specifies the name of an existing ServiceAccount used to run the Function's Pods.
+	// When neither **ServiceAccountName** nor **ServiceAccount** is set, the Pods run under the namespace's `default` ServiceAccount.
+	// Can't be used together with **ServiceAccount**.
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// Configures a dedicated ServiceAccount created for the Function and named after it.
+	// Can't be used together with **ServiceAccountName**.
+	// +optional
+	ServiceAccount *FunctionServiceAccount `json:"serviceAccount,omitempty"`
+
+	// Specifies whether the ServiceAccount token is automatically mounted into the Function's Pods.
+	// When not set, the ServiceAccount's setting is used.
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+
 	// Specifies Secrets to mount into the Function's container filesystem.
 	SecretMounts []SecretMount `json:"secretMounts,omitempty"`
 
+	// Specifies ConfigMaps to mount into the Function's container filesystem.
+	// +optional
+	ConfigMapMounts []ConfigMapMount `json:"configMapMounts,omitempty"`
+
+	// Specifies projected ServiceAccount tokens to mount into the Function's container filesystem.
+	// Use them to authenticate the Function to external services supporting workload identity.
+	// +optional
+	ServiceAccountTokenMounts []ServiceAccountTokenMount `json:"serviceAccountTokenMounts,omitempty"`
+
+	// Specifies additional writable volumes mounted into the Function's container filesystem.
+	// Use them for Functions that process files exceeding the size of the `/tmp` directory.
+	// +optional
+	Volumes []FunctionVolume `json:"volumes,omitempty"`
+
+	// Specifies additional containers running next to the Function's container, for example a local cache or a log shipper.
+	// The containers can mount only the volumes defined in **Volumes**, referenced by their names.
+	// +optional
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// Specifies containers run before the Function's container starts. They run after the container fetching the Git sources.
+	// The containers can mount only the volumes defined in **Volumes**, referenced by their names.
+	// +optional
+	InitContainers []corev1.Container `json:"initContainers,omitempty"`
+
 	// Defines labels used in Deployment's PodTemplate and applied on the Function's runtime Pod.
 	// +optional
 	// +kubebuilder:validation:XValidation:message="Labels has key starting with serverless.kyma-project.io/ which is not allowed",rule="!(self.exists(e, e.startsWith('serverless.kyma-project.io/')))"
@@ -214,8 +354,318 @@ type ScaleConfig struct {
 	MaxReplicas *int32 `json:"maxReplicas"`
 }
 
+type EventScaling struct {
+	// Defines the minimum number of Function's Pods to run at a time.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Defines the maximum number of Function's Pods to run at a time.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+
+	// Defines the period in seconds to wait after the last active trigger before scaling the Function down.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	CooldownPeriod *int32 `json:"cooldownPeriod,omitempty"`
+
+	// Specifies the name of the KEDA TriggerAuthentication in the Function's Namespace used to authenticate to the event source.
+	// +optional
+	AuthenticationRef string `json:"authenticationRef,omitempty"`
+
+	// Scales the Function based on the number of pending messages of a NATS JetStream consumer.
+	// Can't be used together with **Kafka**.
+	// +optional
+	NatsJetStream *NatsJetStreamEventSource `json:"natsJetStream,omitempty"`
+
+	// Scales the Function based on the consumer group lag of a Kafka topic.
+	// Can't be used together with **NatsJetStream**.
+	// +optional
+	Kafka *KafkaEventSource `json:"kafka,omitempty"`
+}
+
+type NatsJetStreamEventSource struct {
+	// Specifies the address of the NATS server monitoring endpoint, for example `eventing-nats.kyma-system.svc.cluster.local:8222`.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	MonitoringEndpoint string `json:"monitoringEndpoint"`
+
+	// Specifies the NATS account. Defaults to `$G`.
+	// +optional
+	Account string `json:"account,omitempty"`
+
+	// Specifies the name of the JetStream stream which stores the consumed subjects.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Stream string `json:"stream"`
+
+	// Specifies the name of the JetStream consumer used by the Function.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Consumer string `json:"consumer"`
+
+	// Specifies the number of pending messages per replica above which the Function is scaled out.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LagThreshold *int32 `json:"lagThreshold,omitempty"`
+}
+
+type KafkaEventSource struct {
+	// Specifies the list of Kafka brokers used to bootstrap the connection.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	BootstrapServers []string `json:"bootstrapServers"`
+
+	// Specifies the name of the consumer group used by the Function.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ConsumerGroup string `json:"consumerGroup"`
+
+	// Specifies the name of the consumed topic.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Topic string `json:"topic"`
+
+	// Specifies the consumer group lag per replica above which the Function is scaled out.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	LagThreshold *int32 `json:"lagThreshold,omitempty"`
+}
+
+type PodDisruptionBudget struct {
+	// Enables or disables the Function's PodDisruptionBudget.
+	// Defaults to `true` for Functions running more than one replica and `false` otherwise.
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Specifies the number or percentage of the Function's Pods that must remain available during a voluntary disruption.
+	// Can't be used together with **MaxUnavailable**.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// Specifies the number or percentage of the Function's Pods that can be unavailable during a voluntary disruption.
+	// Can't be used together with **MinAvailable**. Defaults to `1`.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+type FunctionNetworkPolicy struct {
+	// Specifies the sources allowed to reach the Function's Pods in addition to the Serverless controller and, for an exposed Function, the gateway. Other traffic, such as Prometheus scraping, must be allowed explicitly.
+	// For configuration details, see the [official Kubernetes documentation](https://kubernetes.io/docs/concepts/services-networking/network-policies/).
+	// +optional
+	Ingress []networkingv1.NetworkPolicyIngressRule `json:"ingress,omitempty"`
+
+	// Specifies destinations the Function's Pods can reach in addition to the default ones.
+	// For configuration details, see the [official Kubernetes documentation](https://kubernetes.io/docs/concepts/services-networking/network-policies/).
+	// +optional
+	Egress []networkingv1.NetworkPolicyEgressRule `json:"egress,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:message="Headless Service must be of the ClusterIP type",rule="!has(self.headless) || !self.headless || !has(self.type) || self.type == 'ClusterIP'"
+type FunctionService struct {
+	// Specifies the type of the Service. Defaults to `ClusterIP`.
+	// +optional
+	// +kubebuilder:validation:Enum=ClusterIP;NodePort;LoadBalancer
+	Type corev1.ServiceType `json:"type,omitempty"`
+
+	// Creates a headless Service without a cluster IP, which serves the Function on port 8080 instead of 80. Changing this field recreates the Service.
+	// +optional
+	Headless bool `json:"headless,omitempty"`
+
+	// Specifies ports exposed in addition to the default `http` port `80`.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Ports []FunctionServicePort `json:"ports,omitempty"`
+
+	// Defines annotations added to the Service.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Defines labels added to the Service.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Specifies the session affinity of the Service. Defaults to `None`.
+	// +optional
+	// +kubebuilder:validation:Enum=None;ClientIP
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+}
+
+type FunctionServicePort struct {
+	// Specifies the name of the port. Must be unique and can't be `http`.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Specifies the port exposed by the Service.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Specifies the number or name of the port on the Function's Pods. Defaults to **Port**.
+	// +optional
+	TargetPort *intstr.IntOrString `json:"targetPort,omitempty"`
+
+	// Specifies the protocol of the port. Defaults to `TCP`.
+	// +optional
+	// +kubebuilder:validation:Enum=TCP;UDP;SCTP
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
+}
+
+type ExposeAuthStrategy string
+
+const (
+	ExposeAuthNoAuth ExposeAuthStrategy = "NoAuth"
+	ExposeAuthJWT    ExposeAuthStrategy = "JWT"
+)
+
+type FunctionExpose struct {
+	// Specifies the host the Function is exposed on. A short host name is completed with the domain configured in the Serverless configuration.
+	// +kubebuilder:validation:MinLength=1
+	Host string `json:"host"`
+
+	// Specifies the path prefix the Function is exposed on. Defaults to `/`.
+	// +optional
+	Path string `json:"path,omitempty"`
+
+	// Specifies the HTTP methods allowed for the Function. When empty, all methods are allowed.
+	// +optional
+	Methods []string `json:"methods,omitempty"`
+
+	// Specifies the gateway used to expose the Function in the `{NAMESPACE}/{NAME}` format. Defaults to the APIRule or HTTPRoute gateway configured in the Serverless configuration.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// Specifies how requests to the Function are authenticated. Defaults to no authentication.
+	// +optional
+	Auth *ExposeAuth `json:"auth,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:message="JWT strategy requires jwt configuration",rule="self.strategy != 'JWT' || has(self.jwt)"
+type ExposeAuth struct {
+	// Specifies the authentication strategy. The `JWT` strategy is supported only with APIRules.
+	// +kubebuilder:validation:Enum=NoAuth;JWT
+	Strategy ExposeAuthStrategy `json:"strategy"`
+
+	// Configures validation of the JSON Web Tokens sent to the Function.
+	// +optional
+	JWT *ExposeJWT `json:"jwt,omitempty"`
+}
+
+type ExposeJWT struct {
+	// Specifies the issuer of the tokens.
+	// +kubebuilder:validation:MinLength=1
+	Issuer string `json:"issuer"`
+
+	// Specifies the URL of the JSON Web Key Set used to verify the tokens.
+	// +kubebuilder:validation:MinLength=1
+	JwksUri string `json:"jwksUri"`
+}
+
+// +kubebuilder:validation:Enum=standard;exact
+type EventTypeMatching string
+
+const (
+	EventTypeMatchingStandard EventTypeMatching = "standard"
+	EventTypeMatchingExact    EventTypeMatching = "exact"
+)
+
+type FunctionEventSubscription struct {
+	// Specifies the name of the subscription. The Subscription is named `{FUNCTION_NAME}-{SUBSCRIPTION_NAME}`.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Specifies the source of the consumed events, for example the name of the application publishing them.
+	// +kubebuilder:validation:MinLength=1
+	Source string `json:"source"`
+
+	// Specifies the types of the consumed events, for example `order.created.v1`.
+	// +kubebuilder:validation:MinItems=1
+	Types []string `json:"types"`
+
+	// Specifies how the event types are matched. With `standard`, the types are prefixed by Eventing and with `exact`, they are used as they are. Defaults to `standard`.
+	// +optional
+	TypeMatching EventTypeMatching `json:"typeMatching,omitempty"`
+}
+
+type FunctionSchedule struct {
+	// Specifies the name of the schedule. The CronJob is named `{FUNCTION_NAME}-{SCHEDULE_NAME}`.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Specifies the schedule in the [cron format](https://kubernetes.io/docs/concepts/workloads/controllers/cron-jobs/#schedule-syntax).
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Specifies the time zone of the schedule, for example `Europe/Warsaw`. Defaults to the time zone of the cluster.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// Specifies the JSON payload sent to the Function.
+	// +optional
+	Payload string `json:"payload,omitempty"`
+
+	// Suspends subsequent invocations.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+type FunctionServiceAccount struct {
+	// Specifies the Role or ClusterRole bound to the Function's ServiceAccount with a RoleBinding named after the Function.
+	// Only Roles and ClusterRoles allowed by the Serverless configuration can be bound. When not set, no RoleBinding is created.
+	// +optional
+	RoleRef *ServiceAccountRoleRef `json:"roleRef,omitempty"`
+}
+
+type ServiceAccountRoleRef struct {
+	// Specifies the kind of the bound role. The available values are `Role` and `ClusterRole`.
+	// +kubebuilder:validation:Enum=Role;ClusterRole
+	Kind string `json:"kind"`
+
+	// Specifies the name of the bound role.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+type Probes struct {
+	// Overrides the startup probe. By default, the Function's container has 150 seconds to start.
+	// +optional
+	Startup *ProbeOverride `json:"startup,omitempty"`
+
+	// Overrides the readiness probe.
+	// +optional
+	Readiness *ProbeOverride `json:"readiness,omitempty"`
+
+	// Overrides the liveness probe.
+	// +optional
+	Liveness *ProbeOverride `json:"liveness,omitempty"`
+}
+
+type ProbeOverride struct {
+	// Specifies the number of seconds after the container has started before the probe is initiated.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// Specifies how often, in seconds, to perform the probe.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// Specifies the number of seconds after which the probe times out.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// Specifies the minimum consecutive failures for the probe to be considered failed.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
 type SecretMount struct {
-	// Specifies the name of the Secret in the Function's Namespace.
+	// Specifies the name of the Secret in the Function's Namespace. Must not start with `fn-`, which is reserved for volumes generated by Serverless.
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MaxLength=253
 	// +kubebuilder:validation:MinLength=1
@@ -225,6 +675,102 @@ type SecretMount struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	MountPath string `json:"mountPath"`
+
+	// Specifies the Secret keys to project into files. If not set, all keys are mounted.
+	// +optional
+	Items []corev1.KeyToPath `json:"items,omitempty"`
+}
+
+type ConfigMapMount struct {
+	// Specifies the name of the ConfigMap in the Function's Namespace.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:MinLength=1
+	ConfigMapName string `json:"configMapName"`
+
+	// Specifies the path within the container where the ConfigMap should be mounted.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	MountPath string `json:"mountPath"`
+
+	// Specifies the ConfigMap keys to project into files. If not set, all keys are mounted.
+	// +optional
+	Items []corev1.KeyToPath `json:"items,omitempty"`
+
+	// Specifies the mode bits used to set permissions on the created files. Defaults to 0644.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=511
+	// +optional
+	DefaultMode *int32 `json:"defaultMode,omitempty"`
+
+	// Specifies whether the Function can start when the ConfigMap or its keys don't exist.
+	// +optional
+	Optional *bool `json:"optional,omitempty"`
+}
+
+type ServiceAccountTokenMount struct {
+	// Specifies the path within the container where the token should be mounted.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	MountPath string `json:"mountPath"`
+
+	// Specifies the intended audience of the token. Defaults to the audience of the API server.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// Specifies the requested duration of validity of the token. Defaults to 1 hour.
+	// +kubebuilder:validation:Minimum=600
+	// +optional
+	ExpirationSeconds *int64 `json:"expirationSeconds,omitempty"`
+
+	// Specifies the name of the token file within the mount path. Defaults to `token`.
+	// +kubebuilder:validation:Pattern=`^[^/]+$`
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:message="Use exactly one of emptyDir or persistentVolumeClaim",rule="has(self.emptyDir) && !has(self.persistentVolumeClaim) || !has(self.emptyDir) && has(self.persistentVolumeClaim)"
+type FunctionVolume struct {
+	// Specifies the name of the volume. Must be unique within the Function and must not start with `fn-`, which is reserved for volumes generated by Serverless.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=53
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Specifies the path within the container where the volume should be mounted.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	MountPath string `json:"mountPath"`
+
+	// Defines an ephemeral volume which shares the Pod's lifetime.
+	// +optional
+	EmptyDir *EmptyDirVolume `json:"emptyDir,omitempty"`
+
+	// Defines a reference to an existing PersistentVolumeClaim in the Function's Namespace.
+	// +optional
+	PersistentVolumeClaim *PersistentVolumeClaimVolume `json:"persistentVolumeClaim,omitempty"`
+}
+
+type EmptyDirVolume struct {
+	// Specifies the maximum size of the volume. The Pod is evicted when the limit is exceeded.
+	// +optional
+	SizeLimit *resource.Quantity `json:"sizeLimit,omitempty"`
+
+	// Specifies whether the volume is backed by memory (tmpfs) instead of the node's disk.
+	// The content of a memory-backed volume counts against the Function's memory limit.
+	// +optional
+	InMemory bool `json:"inMemory,omitempty"`
+}
+
+type PersistentVolumeClaimVolume struct {
+	// Specifies the name of the PersistentVolumeClaim.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ClaimName string `json:"claimName"`
+
+	// Specifies whether the volume is mounted as read-only.
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
 }
 
 type Template struct {
@@ -252,6 +798,12 @@ type FunctionStatus struct {
 	FunctionResourceProfile string `json:"functionResourceProfile,omitempty"`
 	// Specifies the last used annotations the Function's Pod template
 	FunctionAnnotations map[string]string `json:"functionAnnotations,omitempty"`
+	// Specifies the last used annotations of the Function's Service
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+	// Specifies the public URL of the Function exposed with **expose**
+	URL string `json:"url,omitempty"`
+	// Specifies the results of the Function's scheduled invocations.
+	Schedules []ScheduleStatus `json:"schedules,omitempty"`
 	// Specifies an array of conditions describing the status of the parser.
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 	// Deprecated: Specifies the commit hash used to build the Function.
@@ -266,6 +818,91 @@ type FunctionStatus struct {
 	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
 	// PodSecurityContext used by the Function's Pod
 	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// Specifies the environment variables set in the Function's container and their sources. Values are not reported.
+	Env []EnvStatus `json:"env,omitempty"`
+	// Specifies the failures found in the Function's Pods when the Deployment is not ready. The Pods are inspected at most every 30 seconds.
+	Diagnostics []FunctionDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type EnvSource string
+
+const (
+	// EnvSourceSystem marks variables set by Serverless
+	EnvSourceSystem EnvSource = "System"
+	// EnvSourceUser marks variables defined in the Function's spec.env
+	EnvSourceUser EnvSource = "User"
+	// EnvSourceOverride marks variables set by Serverless and replaced by the Function's spec.env
+	EnvSourceOverride EnvSource = "Override"
+	// EnvSourceConfigMap marks variables taken from all keys of a ConfigMap
+	EnvSourceConfigMap EnvSource = "ConfigMap"
+	// EnvSourceSecret marks variables taken from all keys of a Secret
+	EnvSourceSecret EnvSource = "Secret"
+)
+
+type EnvStatus struct {
+	// Specifies the name of the variable. For variables taken from a ConfigMap or a Secret,
+	// it is the prefix of their names followed by `*`.
+	Name string `json:"name"`
+	// Specifies where the value of the variable comes from.
+	Source EnvSource `json:"source"`
+	// Specifies the name of the ConfigMap or Secret the variables are taken from.
+	// +optional
+	Reference string `json:"reference,omitempty"`
+}
+
+type ScheduleResult string
+
+const (
+	ScheduleResultRunning   ScheduleResult = "Running"
+	ScheduleResultSucceeded ScheduleResult = "Succeeded"
+	ScheduleResultFailed    ScheduleResult = "Failed"
+)
+
+type ScheduleStatus struct {
+	// Specifies the name of the schedule.
+	Name string `json:"name"`
+	// Specifies when the Function was invoked by the schedule for the last time.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Specifies when the last successful invocation finished.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// Specifies the result of the last invocation.
+	// +optional
+	LastResult ScheduleResult `json:"lastResult,omitempty"`
+}
+
+type DiagnosticReason string
+
+const (
+	// DiagnosticReasonCrashLoopBackOff marks a container which keeps failing after it starts
+	DiagnosticReasonCrashLoopBackOff DiagnosticReason = "CrashLoopBackOff"
+	// DiagnosticReasonOOMKilled marks a container killed for exceeding its memory limit
+	DiagnosticReasonOOMKilled DiagnosticReason = "OOMKilled"
+	// DiagnosticReasonImagePullFailed marks a container whose image can't be pulled
+	DiagnosticReasonImagePullFailed DiagnosticReason = "ImagePullFailed"
+	// DiagnosticReasonGitSourceFetchFailed marks the init container which failed to fetch the git source
+	DiagnosticReasonGitSourceFetchFailed DiagnosticReason = "GitSourceFetchFailed"
+	// DiagnosticReasonDependencyInstallFailed marks a container which failed while installing the Function's dependencies
+	DiagnosticReasonDependencyInstallFailed DiagnosticReason = "DependencyInstallFailed"
+)
+
+type FunctionDiagnostic struct {
+	// Specifies the name of the failing Pod.
+	Pod string `json:"pod"`
+	// Specifies the name of the failing container.
+	Container string `json:"container"`
+	// Specifies the reason of the failure.
+	Reason DiagnosticReason `json:"reason"`
+	// Specifies the exit code of the last terminated instance of the container.
+	// +optional
+	ExitCode *int32 `json:"exitCode,omitempty"`
+	// Specifies the message reported by Kubernetes for the container.
+	// +optional
+	Message string `json:"message,omitempty"`
+	// Specifies the last lines of the failed container's logs.
+	// +optional
+	LogExcerpt string `json:"logExcerpt,omitempty"`
 }
 
 type GitRepositoryStatus struct {
@@ -279,26 +916,65 @@ type ConditionType string
 const (
 	ConditionRunning            ConditionType = "Running"
 	ConditionConfigurationReady ConditionType = "ConfigurationReady"
+	ConditionSubscriptionsReady ConditionType = "SubscriptionsReady"
 )
 
 type ConditionReason string
 
 const (
-	ConditionReasonInvalidFunctionSpec      ConditionReason = "InvalidFunctionSpec"
-	ConditionReasonFunctionSpecValidated    ConditionReason = "FunctionSpecValidated"
-	ConditionReasonSourceUpdated            ConditionReason = "SourceUpdated"
-	ConditionReasonSourceUpdateFailed       ConditionReason = "SourceUpdateFailed"
-	ConditionReasonDeploymentCreated        ConditionReason = "DeploymentCreated"
-	ConditionReasonDeploymentUpdated        ConditionReason = "DeploymentUpdated"
-	ConditionReasonDeploymentFailed         ConditionReason = "DeploymentFailed"
-	ConditionReasonDeploymentDeleted        ConditionReason = "DeploymentDeleted"
-	ConditionReasonDeploymentDeletionFailed ConditionReason = "DeploymentDeletionFailed"
-	ConditionReasonDeploymentWaiting        ConditionReason = "DeploymentWaiting"
-	ConditionReasonDeploymentReady          ConditionReason = "DeploymentReady"
-	ConditionReasonServiceCreated           ConditionReason = "ServiceCreated"
-	ConditionReasonServiceUpdated           ConditionReason = "ServiceUpdated"
-	ConditionReasonServiceFailed            ConditionReason = "ServiceFailed"
-	ConditionReasonMinReplicasNotAvailable  ConditionReason = "MinReplicasNotAvailable"
+	ConditionReasonInvalidFunctionSpec        ConditionReason = "InvalidFunctionSpec"
+	ConditionReasonFunctionSpecValidated      ConditionReason = "FunctionSpecValidated"
+	ConditionReasonRuntimeDeprecated          ConditionReason = "RuntimeDeprecated"
+	ConditionReasonEnvOverrideDeprecated      ConditionReason = "EnvOverrideDeprecated"
+	ConditionReasonSourceUpdated              ConditionReason = "SourceUpdated"
+	ConditionReasonSourceUpdateFailed         ConditionReason = "SourceUpdateFailed"
+	ConditionReasonDeploymentCreated          ConditionReason = "DeploymentCreated"
+	ConditionReasonDeploymentUpdated          ConditionReason = "DeploymentUpdated"
+	ConditionReasonDeploymentFailed           ConditionReason = "DeploymentFailed"
+	ConditionReasonDeploymentDeleted          ConditionReason = "DeploymentDeleted"
+	ConditionReasonDeploymentDeletionFailed   ConditionReason = "DeploymentDeletionFailed"
+	ConditionReasonDeploymentWaiting          ConditionReason = "DeploymentWaiting"
+	ConditionReasonDeploymentReady            ConditionReason = "DeploymentReady"
+	ConditionReasonDependencyInstallFailed    ConditionReason = "DependencyInstallFailed"
+	ConditionReasonServiceCreated             ConditionReason = "ServiceCreated"
+	ConditionReasonServiceUpdated             ConditionReason = "ServiceUpdated"
+	ConditionReasonServiceDeleted             ConditionReason = "ServiceDeleted"
+	ConditionReasonServiceFailed              ConditionReason = "ServiceFailed"
+	ConditionReasonMinReplicasNotAvailable    ConditionReason = "MinReplicasNotAvailable"
+	ConditionReasonScaledObjectCreated        ConditionReason = "ScaledObjectCreated"
+	ConditionReasonScaledObjectUpdated        ConditionReason = "ScaledObjectUpdated"
+	ConditionReasonScaledObjectDeleted        ConditionReason = "ScaledObjectDeleted"
+	ConditionReasonScaledObjectFailed         ConditionReason = "ScaledObjectFailed"
+	ConditionReasonPodDisruptionBudgetCreated ConditionReason = "PodDisruptionBudgetCreated"
+	ConditionReasonPodDisruptionBudgetUpdated ConditionReason = "PodDisruptionBudgetUpdated"
+	ConditionReasonPodDisruptionBudgetDeleted ConditionReason = "PodDisruptionBudgetDeleted"
+	ConditionReasonPodDisruptionBudgetFailed  ConditionReason = "PodDisruptionBudgetFailed"
+	ConditionReasonServiceAccountCreated      ConditionReason = "ServiceAccountCreated"
+	ConditionReasonServiceAccountUpdated      ConditionReason = "ServiceAccountUpdated"
+	ConditionReasonServiceAccountDeleted      ConditionReason = "ServiceAccountDeleted"
+	ConditionReasonServiceAccountFailed       ConditionReason = "ServiceAccountFailed"
+	ConditionReasonRoleBindingCreated         ConditionReason = "RoleBindingCreated"
+	ConditionReasonRoleBindingUpdated         ConditionReason = "RoleBindingUpdated"
+	ConditionReasonRoleBindingDeleted         ConditionReason = "RoleBindingDeleted"
+	ConditionReasonRoleBindingFailed          ConditionReason = "RoleBindingFailed"
+	ConditionReasonNetworkPolicyCreated       ConditionReason = "NetworkPolicyCreated"
+	ConditionReasonNetworkPolicyUpdated       ConditionReason = "NetworkPolicyUpdated"
+	ConditionReasonNetworkPolicyDeleted       ConditionReason = "NetworkPolicyDeleted"
+	ConditionReasonNetworkPolicyFailed        ConditionReason = "NetworkPolicyFailed"
+	ConditionReasonExposeCreated              ConditionReason = "ExposeCreated"
+	ConditionReasonExposeUpdated              ConditionReason = "ExposeUpdated"
+	ConditionReasonExposeDeleted              ConditionReason = "ExposeDeleted"
+	ConditionReasonExposeFailed               ConditionReason = "ExposeFailed"
+	ConditionReasonScheduleCreated            ConditionReason = "ScheduleCreated"
+	ConditionReasonScheduleUpdated            ConditionReason = "ScheduleUpdated"
+	ConditionReasonScheduleDeleted            ConditionReason = "ScheduleDeleted"
+	ConditionReasonScheduleFailed             ConditionReason = "ScheduleFailed"
+	ConditionReasonSubscriptionCreated        ConditionReason = "SubscriptionCreated"
+	ConditionReasonSubscriptionUpdated        ConditionReason = "SubscriptionUpdated"
+	ConditionReasonSubscriptionDeleted        ConditionReason = "SubscriptionDeleted"
+	ConditionReasonSubscriptionFailed         ConditionReason = "SubscriptionFailed"
+	ConditionReasonSubscriptionsReady         ConditionReason = "SubscriptionsReady"
+	ConditionReasonSubscriptionsNotReady      ConditionReason = "SubscriptionsNotReady"
 )
 
 // +kubebuilder:object:root=true
@@ -310,6 +986,7 @@ const (
 // +kubebuilder:printcolumn:name="Running",type="string",JSONPath=".status.conditions[?(@.type=='Running')].status"
 // +kubebuilder:printcolumn:name="Runtime",type="string",JSONPath=".spec.runtime"
 // +kubebuilder:printcolumn:name="Version",type="integer",JSONPath=".metadata.generation"
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=".status.url",priority=1
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // Function is the Schema for the functions API.
@@ -317,6 +994,7 @@ type Function struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata"`
 
+	// +kubebuilder:validation:XValidation:message="Use serviceAccountName or serviceAccount",rule="!(has(self.serviceAccountName) && has(self.serviceAccount))"
 	Spec   FunctionSpec   `json:"spec"`
 	Status FunctionStatus `json:"status,omitempty"`
 }
@@ -364,15 +1042,42 @@ func (s *FunctionStatus) Condition(c ConditionType) *metav1.Condition {
 }
 
 const (
-	FunctionNameLabel                    = "serverless.kyma-project.io/function-name"
-	FunctionManagedByLabel               = "serverless.kyma-project.io/managed-by"
-	FunctionControllerValue              = "function-controller"
-	FunctionUUIDLabel                    = "serverless.kyma-project.io/uuid"
-	FunctionResourceLabel                = "serverless.kyma-project.io/resource"
-	FunctionResourceLabelDeploymentValue = "deployment"
-	PodAppNameLabel                      = "app.kubernetes.io/name"
+	FunctionNameLabel                      = "serverless.kyma-project.io/function-name"
+	FunctionManagedByLabel                 = "serverless.kyma-project.io/managed-by"
+	FunctionControllerValue                = "function-controller"
+	FunctionUUIDLabel                      = "serverless.kyma-project.io/uuid"
+	FunctionResourceLabel                  = "serverless.kyma-project.io/resource"
+	FunctionScheduleLabel                  = "serverless.kyma-project.io/schedule"
+	FunctionResourceLabelDeploymentValue   = "deployment"
+	FunctionResourceLabelScheduleValue     = "schedule"
+	FunctionResourceLabelSubscriptionValue = "subscription"
+	PodAppNameLabel                        = "app.kubernetes.io/name"
+	FunctionEventVerbosityAnnotation       = "serverless.kyma-project.io/event-verbosity"
+	FunctionShardLabel                     = "serverless.kyma-project.io/shard"
 )
 
+// EventVerbosity controls which events are emitted for the Function
+type EventVerbosity string
+
+const (
+	// EventVerbosityWarning emits only Warning events
+	EventVerbosityWarning EventVerbosity = "warning"
+	// EventVerbosityNormal emits events when conditions change and aggregates repeated failures
+	EventVerbosityNormal EventVerbosity = "normal"
+	// EventVerbosityDebug emits an event for every repeated failure
+	EventVerbosityDebug EventVerbosity = "debug"
+)
+
+// EventVerbosity returns the verbosity set in the Function's annotation, unknown values fall back to normal
+func (f *Function) EventVerbosity() EventVerbosity {
+	switch v := EventVerbosity(f.GetAnnotations()[FunctionEventVerbosityAnnotation]); v {
+	case EventVerbosityWarning, EventVerbosityDebug:
+		return v
+	default:
+		return EventVerbosityNormal
+	}
+}
+
 func (f *Function) InternalFunctionLabels() map[string]string {
 	intLabels := make(map[string]string, 3)
 
@@ -427,6 +1132,19 @@ func (f *Function) HasInlineSources() bool {
 	return f.Spec.Source.Inline != nil
 }
 
+func (f *Function) HasEventScaling() bool {
+	return f.Spec.EventScaling != nil
+}
+
+// MayRunMultipleReplicas returns true if the Function runs, or can be scaled to, more than one replica
+func (f *Function) MayRunMultipleReplicas() bool {
+	if f.HasEventScaling() {
+		maxReplicas := f.Spec.EventScaling.MaxReplicas
+		return maxReplicas == nil || *maxReplicas > 1
+	}
+	return f.Spec.Replicas != nil && *f.Spec.Replicas > 1
+}
+
 func (f *Function) HasPythonRuntime() bool {
 	return f.Spec.Runtime.IsRuntimePython()
 }
@@ -439,6 +1157,13 @@ func (f *Function) CopyAnnotationsToStatus() {
 	f.Status.FunctionAnnotations = f.Spec.Annotations
 }
 
+func (f *Function) CopyServiceAnnotationsToStatus() {
+	f.Status.ServiceAnnotations = nil
+	if f.Spec.Service != nil {
+		f.Status.ServiceAnnotations = f.Spec.Service.Annotations
+	}
+}
+
 // runtime helper functions
 // almost all functions that check for supported runtime versions should be here, for simpler bumps
 
@@ -12,6 +12,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 func Test_XKubernetesValidations_Valid(t *testing.T) {
@@ -400,6 +402,25 @@ func Test_XKubernetesValidations_Valid(t *testing.T) {
 				},
 			},
 		},
+		"eventScaling": {
+			fn: &serverlessv1alpha2.Function{
+				ObjectMeta: fixMetadata,
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{Source: "abc"}},
+					EventScaling: &serverlessv1alpha2.EventScaling{
+						MinReplicas: ptr.To[int32](0),
+						MaxReplicas: ptr.To[int32](3),
+						Kafka: &serverlessv1alpha2.KafkaEventSource{
+							BootstrapServers: []string{"kafka:9092"},
+							ConsumerGroup:    "group",
+							Topic:            "topic",
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -823,6 +844,133 @@ func Test_XKubernetesValidations_Invalid(t *testing.T) {
 			fieldPath:      "spec.source.gitRepository.auth.secretName",
 			expectedCause:  metav1.CauseTypeFieldValueInvalid,
 		},
+		"EventScaling without event source": {
+			fn: &serverlessv1alpha2.Function{
+				ObjectMeta: fixMetadata,
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{Source: "abc"}},
+					EventScaling: &serverlessv1alpha2.EventScaling{},
+				},
+			},
+			expectedErrMsg: "Invalid value: Use natsJetStream or kafka",
+			fieldPath:      "spec.eventScaling",
+			expectedCause:  metav1.CauseTypeFieldValueInvalid,
+		},
+		"PodDisruptionBudget with minAvailable and maxUnavailable": {
+			fn: &serverlessv1alpha2.Function{
+				ObjectMeta: fixMetadata,
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{Source: "abc"}},
+					PodDisruptionBudget: &serverlessv1alpha2.PodDisruptionBudget{
+						MinAvailable:   ptr.To(intstr.FromInt32(1)),
+						MaxUnavailable: ptr.To(intstr.FromInt32(1)),
+					},
+				},
+			},
+			expectedErrMsg: "Invalid value: Use minAvailable or maxUnavailable",
+			fieldPath:      "spec.podDisruptionBudget",
+			expectedCause:  metav1.CauseTypeFieldValueInvalid,
+		},
+		"Volume with emptyDir and persistentVolumeClaim": {
+			fn: &serverlessv1alpha2.Function{
+				ObjectMeta: fixMetadata,
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{Source: "abc"}},
+					Volumes: []serverlessv1alpha2.FunctionVolume{
+						{
+							Name:      "scratch",
+							MountPath: "/scratch",
+							EmptyDir:  &serverlessv1alpha2.EmptyDirVolume{},
+							PersistentVolumeClaim: &serverlessv1alpha2.PersistentVolumeClaimVolume{
+								ClaimName: "claim",
+							},
+						},
+					},
+				},
+			},
+			expectedErrMsg: "Invalid value: Use exactly one of emptyDir or persistentVolumeClaim",
+			fieldPath:      "spec.volumes[0]",
+			expectedCause:  metav1.CauseTypeFieldValueInvalid,
+		},
+		"ServiceAccountName with ServiceAccount": {
+			fn: &serverlessv1alpha2.Function{
+				ObjectMeta: fixMetadata,
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{Source: "abc"}},
+					ServiceAccountName: "existing",
+					ServiceAccount:     &serverlessv1alpha2.FunctionServiceAccount{},
+				},
+			},
+			expectedErrMsg: "Invalid value: Use serviceAccountName or serviceAccount",
+			fieldPath:      "spec",
+			expectedCause:  metav1.CauseTypeFieldValueInvalid,
+		},
+		"Headless Service with LoadBalancer type": {
+			fn: &serverlessv1alpha2.Function{
+				ObjectMeta: fixMetadata,
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{Source: "abc"}},
+					Service: &serverlessv1alpha2.FunctionService{
+						Type:     corev1.ServiceTypeLoadBalancer,
+						Headless: true,
+					},
+				},
+			},
+			expectedErrMsg: "Invalid value: Headless Service must be of the ClusterIP type",
+			fieldPath:      "spec.service",
+			expectedCause:  metav1.CauseTypeFieldValueInvalid,
+		},
+		"Expose JWT strategy without jwt configuration": {
+			fn: &serverlessv1alpha2.Function{
+				ObjectMeta: fixMetadata,
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{Source: "abc"}},
+					Expose: &serverlessv1alpha2.FunctionExpose{
+						Host: "test-host",
+						Auth: &serverlessv1alpha2.ExposeAuth{
+							Strategy: serverlessv1alpha2.ExposeAuthJWT,
+						},
+					},
+				},
+			},
+			expectedErrMsg: "Invalid value: JWT strategy requires jwt configuration",
+			fieldPath:      "spec.expose.auth",
+			expectedCause:  metav1.CauseTypeFieldValueInvalid,
+		},
+		"EventScaling with minReplicas greater than maxReplicas": {
+			fn: &serverlessv1alpha2.Function{
+				ObjectMeta: fixMetadata,
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{Source: "abc"}},
+					EventScaling: &serverlessv1alpha2.EventScaling{
+						MinReplicas: ptr.To[int32](3),
+						MaxReplicas: ptr.To[int32](1),
+						NatsJetStream: &serverlessv1alpha2.NatsJetStreamEventSource{
+							MonitoringEndpoint: "nats:8222",
+							Stream:             "stream",
+							Consumer:           "consumer",
+						},
+					},
+				},
+			},
+			expectedErrMsg: "Invalid value: MinReplicas cannot be greater than maxReplicas",
+			fieldPath:      "spec.eventScaling",
+			expectedCause:  metav1.CauseTypeFieldValueInvalid,
+		},
 	}
 
 	for name, tc := range testCases {
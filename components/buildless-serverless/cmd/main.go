@@ -35,22 +35,29 @@ import (
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/diagnostics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/git"
 	serverlessmetrics "github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/sharding"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/tracing"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/logging"
 	"github.com/kyma-project/serverless/components/common/fips"
 	"github.com/vrischmann/envconfig"
 	uberzap "go.uber.org/zap"
 	uberzapcore "go.uber.org/zap/zapcore"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	ctrlzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	// +kubebuilder:scaffold:imports
@@ -123,6 +130,17 @@ func main() {
 	ctrl.SetLogger(zapr.NewLogger(logWithCtx.Desugar()))
 	setupLog = ctrl.Log.WithName("setup")
 
+	tracer, shutdownTracing, err := tracing.NewTracer(ctx, cfg.FunctionTraceCollectorEndpoint)
+	if err != nil {
+		setupLog.Error(err, "unable to configure tracing")
+		os.Exit(1)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			setupLog.Error(err, "unable to flush traces")
+		}
+	}()
+
 	logWithCtx.Info("Generating Kubernetes client config")
 	restConfig := ctrl.GetConfigOrDie()
 
@@ -144,6 +162,7 @@ func main() {
 					&serverlessv1alpha2.Function{},
 					&corev1.Secret{},
 					&corev1.ConfigMap{},
+					&coordinationv1.Lease{},
 				},
 			},
 		},
@@ -154,7 +173,8 @@ func main() {
 		os.Exit(1)
 	}
 
-	serverlessmetrics.Register()
+	metricsCollector := serverlessmetrics.NewCollector(cfg.PerFunctionMetricsEnabled)
+	metricsCollector.MustRegister(metrics.Registry)
 
 	healthHandler, healthEventsCh, healthResponseCh := controller.NewHealthChecker(cfg.Healthz.LivenessTimeout, logWithCtx.Named("healthz"))
 	if err := mgr.AddHealthzCheck("healthz", healthHandler.Checker); err != nil {
@@ -166,6 +186,28 @@ func main() {
 		os.Exit(1)
 	}
 
+	coreClient, err := corev1client.NewForConfig(restConfig)
+	if err != nil {
+		setupLog.Error(err, "unable to create core client")
+		os.Exit(1)
+	}
+
+	var shards *sharding.Coordinator
+	if cfg.Sharding.Enabled() {
+		// pod name is unique among controller replicas
+		identity, err := os.Hostname()
+		if err != nil {
+			setupLog.Error(err, "unable to get replica identity")
+			os.Exit(1)
+		}
+		shards = sharding.NewCoordinator(mgr.GetClient(), cfg.Sharding, identity, logWithCtx.Named("sharding"))
+		if err := mgr.Add(shards); err != nil {
+			setupLog.Error(err, "unable to set up shard coordinator")
+			os.Exit(1)
+		}
+		metricsCollector.ReportOwnedFunctions(shards.OwnsFunction)
+	}
+
 	fnCtrl, err := (&controller.FunctionReconciler{
 		Client:                mgr.GetClient(),
 		Scheme:                mgr.GetScheme(),
@@ -173,6 +215,11 @@ func main() {
 		Config:                cfg,
 		EventRecorder:         mgr.GetEventRecorderFor(serverlessv1alpha2.FunctionControllerValue),
 		GitChecker:            git.NewAsyncLatestCommitChecker(ctx, logWithCtx),
+		PodInspector:          diagnostics.NewRateLimitedPodInspector(diagnostics.NewPodInspector(coreClient), diagnostics.DefaultInspectionInterval),
+		Metrics:               metricsCollector,
+		Tracer:                tracer,
+		Failures:              fsm.NewFailureCounter(),
+		Shards:                shards,
 		HealthCh:              healthResponseCh,
 		IsKymaFipsModeEnabled: envCfg.KymaFipsModeEnabled,
 	}).SetupWithManager(mgr)
@@ -188,10 +235,18 @@ func main() {
 		os.Exit(1)
 	}
 
+	if shards != nil {
+		err = fnCtrl.Watch(source.Channel(shards.Events(), &handler.EnqueueRequestForObject{}))
+		if err != nil {
+			setupLog.Error(err, "unable to watch shard events channel")
+			os.Exit(1)
+		}
+	}
+
 	// disable default log to prevent http server from logging returned status codes
 	log.SetOutput(io.Discard)
 
-	internalServer := endpoint.NewInternalServer(ctx, logWithCtx, mgr.GetClient(), cfg, envCfg.KymaFipsModeEnabled)
+	internalServer := endpoint.NewInternalServer(ctx, logWithCtx, mgr.GetClient(), coreClient, cfg, envCfg.KymaFipsModeEnabled)
 	go func() {
 		err := internalServer.ListenAndServe(cfg.InternalEndpointPort)
 		if err != nil {
@@ -18,15 +18,55 @@ type FunctionConfig struct {
 	LeaderElectionID                string `yaml:"leaderElectionID"`
 	SecretMutatingWebhookPort       int    `yaml:"secretMutatingWebhookPort"`
 	Healthz                         healthzConfig
-	Images                          ImagesConfig   `yaml:"images"`
-	RequeueDuration                 time.Duration  `yaml:"requeueDuration"`
-	FunctionReadyRequeueDuration    time.Duration  `yaml:"functionReadyRequeueDuration"`
-	PackageRegistryConfigSecretName string         `yaml:"packageRegistryConfigSecretName"`
-	FunctionTraceCollectorEndpoint  string         `yaml:"functionTraceCollectorEndpoint"`
-	FunctionPublisherProxyAddress   string         `yaml:"functionPublisherProxyAddress"`
-	ResourceConfig                  ResourceConfig `yaml:"resourcesConfiguration"`
-	InternalEndpointPort            string         `yaml:"internalEndpointPort"`
+	Images                          ImagesConfig      `yaml:"images"`
+	RequeueDuration                 time.Duration     `yaml:"requeueDuration"`
+	FunctionReadyRequeueDuration    time.Duration     `yaml:"functionReadyRequeueDuration"`
+	PackageRegistryConfigSecretName string            `yaml:"packageRegistryConfigSecretName"`
+	FunctionTraceCollectorEndpoint  string            `yaml:"functionTraceCollectorEndpoint"`
+	FunctionPublisherProxyAddress   string            `yaml:"functionPublisherProxyAddress"`
+	ResourceConfig                  ResourceConfig    `yaml:"resourcesConfiguration"`
+	InternalEndpointPort            string            `yaml:"internalEndpointPort"`
+	BindableClusterRoles            []string          `yaml:"bindableClusterRoles"`
+	BindableRoles                   []string          `yaml:"bindableRoles"`
+	PackageRegistryEgressCIDRs      []string          `yaml:"packageRegistryEgressCIDRs"`
+	ExposeGateway                   string            `yaml:"exposeGateway"`
+	ExposeHTTPRouteGateway          string            `yaml:"exposeHTTPRouteGateway"`
+	ExposeDomain                    string            `yaml:"exposeDomain"`
+	ControllerNamespace             string            `yaml:"controllerNamespace"`
+	ControllerReplicas              int               `yaml:"controllerReplicas"`
+	MeshNamespace                   string            `yaml:"meshNamespace"`
+	PerFunctionMetricsEnabled       bool              `yaml:"perFunctionMetricsEnabled"`
+	MaxConcurrentReconciles         int               `yaml:"maxConcurrentReconciles"`
+	RateLimiter                     RateLimiterConfig `yaml:"rateLimiter"`
+	Sharding                        ShardingConfig    `yaml:"sharding"`
 }
+
+// RateLimiterConfig configures how fast functions are requeued,
+// the slower of the per-function exponential backoff and the overall token bucket wins
+type RateLimiterConfig struct {
+	BaseDelay time.Duration `yaml:"baseDelay"`
+	MaxDelay  time.Duration `yaml:"maxDelay"`
+	Frequency int           `yaml:"frequency"`
+	Burst     int           `yaml:"burst"`
+}
+
+// ShardingConfig splits functions between controller replicas,
+// each replica reconciles only functions from shards it holds Leases for
+type ShardingConfig struct {
+	// Shards is the number of shards, sharding is disabled for less than two shards
+	Shards int `yaml:"shards"`
+	// Key is the function's namespace or the value of its shard label hashed to choose the shard
+	Key            string        `yaml:"key"`
+	LeaseNamespace string        `yaml:"leaseNamespace"`
+	LeasePrefix    string        `yaml:"leasePrefix"`
+	LeaseDuration  time.Duration `yaml:"leaseDuration"`
+	RenewPeriod    time.Duration `yaml:"renewPeriod"`
+}
+
+func (c ShardingConfig) Enabled() bool {
+	return c.Shards > 1
+}
+
 type healthzConfig struct {
 	Port            string        `yaml:"healthzPort"`
 	LivenessTimeout time.Duration `yaml:"healthzLivenessTimeout"`
@@ -47,6 +87,27 @@ func defaultFunctionConfig() FunctionConfig {
 		PackageRegistryConfigSecretName: "serverless-package-registry-config",
 		FunctionPublisherProxyAddress:   "http://eventing-publisher-proxy.kyma-system.svc.cluster.local/publish",
 		InternalEndpointPort:            ":12137",
+		BindableClusterRoles:            []string{"view"},
+		PackageRegistryEgressCIDRs:      []string{"0.0.0.0/0"},
+		ExposeGateway:                   "kyma-system/kyma-gateway",
+		ControllerNamespace:             "kyma-system",
+		ControllerReplicas:              1,
+		MeshNamespace:                   "istio-system",
+		MaxConcurrentReconciles:         1,
+		RateLimiter: RateLimiterConfig{
+			BaseDelay: 250 * time.Millisecond,
+			MaxDelay:  5 * time.Minute,
+			Frequency: 10,
+			Burst:     100,
+		},
+		Sharding: ShardingConfig{
+			Shards:         1,
+			Key:            "namespace",
+			LeaseNamespace: "kyma-system",
+			LeasePrefix:    "serverless-controller-shard",
+			LeaseDuration:  15 * time.Second,
+			RenewPeriod:    5 * time.Second,
+		},
 	}
 }
 
@@ -0,0 +1,209 @@
+package diagnostics
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// MaxDiagnostics limits the number of diagnostics reported in the Function's status
+	MaxDiagnostics = 10
+	// logExcerptLines is the number of the last log lines kept in the diagnostic
+	logExcerptLines = 20
+	// maxLogExcerptSize limits the size of the log excerpt kept in the diagnostic
+	maxLogExcerptSize = 2048
+)
+
+var (
+	imagePullReasons = map[string]bool{
+		"ErrImagePull":     true,
+		"ImagePullBackOff": true,
+		"InvalidImageName": true,
+	}
+
+	// dependencyInstallErrorRegex matches errors printed by npm and pip when dependencies can't be installed
+	dependencyInstallErrorRegex = regexp.MustCompile(`(?m)^(npm ERR!|npm error|ERROR: (Could not find a version|No matching distribution|Could not install|Failed building|Invalid requirement|Cannot install))`)
+)
+
+//go:generate mockery --name=PodInspector --output=automock --outpkg=automock --case=underscore
+type PodInspector interface {
+	Inspect(context.Context, *serverlessv1alpha2.Function) ([]serverlessv1alpha2.FunctionDiagnostic, error)
+}
+
+type podInspector struct {
+	pods corev1client.PodsGetter
+}
+
+// NewPodInspector returns the inspector which reads Pods and their logs directly from the API server,
+// to not start a cluster-wide Pod informer in the manager's cache
+func NewPodInspector(pods corev1client.PodsGetter) PodInspector {
+	return &podInspector{
+		pods: pods,
+	}
+}
+
+// Inspect looks for failing containers in the Function's Pods
+func (i *podInspector) Inspect(ctx context.Context, f *serverlessv1alpha2.Function) ([]serverlessv1alpha2.FunctionDiagnostic, error) {
+	pods, err := i.pods.Pods(f.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(f.SelectorLabels()).String(),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "while listing pods")
+	}
+	sort.Slice(pods.Items, func(a, b int) bool {
+		return pods.Items[a].Name < pods.Items[b].Name
+	})
+
+	result := []serverlessv1alpha2.FunctionDiagnostic{}
+	for _, pod := range pods.Items {
+		for _, diagnostic := range diagnosePod(&pod) {
+			if len(result) >= MaxDiagnostics {
+				return result, nil
+			}
+			result = append(result, i.withLogExcerpt(ctx, &pod, diagnostic))
+		}
+	}
+	return result, nil
+}
+
+// withLogExcerpt attaches the last lines of the failed container's logs to the diagnostic,
+// logs containing npm or pip errors change the reason to DependencyInstallFailed
+func (i *podInspector) withLogExcerpt(ctx context.Context, pod *corev1.Pod, d diagnostic) serverlessv1alpha2.FunctionDiagnostic {
+	result := d.FunctionDiagnostic
+	if !d.hasLogs {
+		return result
+	}
+
+	excerpt, err := i.readLogs(ctx, pod, d.Container, d.previous)
+	if err != nil {
+		// logs are best effort, the container may be already gone
+		return result
+	}
+	result.LogExcerpt = excerpt
+	if result.Reason == serverlessv1alpha2.DiagnosticReasonCrashLoopBackOff && dependencyInstallErrorRegex.MatchString(excerpt) {
+		result.Reason = serverlessv1alpha2.DiagnosticReasonDependencyInstallFailed
+	}
+	return result
+}
+
+func (i *podInspector) readLogs(ctx context.Context, pod *corev1.Pod, container string, previous bool) (string, error) {
+	stream, err := i.pods.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: ptr.To[int64](logExcerptLines),
+	}).Stream(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	logs, err := io.ReadAll(stream)
+	if err != nil {
+		return "", err
+	}
+	return truncateLogs(string(logs)), nil
+}
+
+// truncateLogs keeps the end of the logs, where the error is usually printed
+func truncateLogs(logs string) string {
+	logs = strings.TrimRight(logs, "\n")
+	if len(logs) <= maxLogExcerptSize {
+		return logs
+	}
+	logs = logs[len(logs)-maxLogExcerptSize:]
+	if i := strings.Index(logs, "\n"); i >= 0 {
+		logs = logs[i+1:]
+	}
+	return logs
+}
+
+type diagnostic struct {
+	serverlessv1alpha2.FunctionDiagnostic
+	// hasLogs is true when the container run and its logs may explain the failure
+	hasLogs bool
+	// previous is true when the logs of the failure belong to the previous instance of the container
+	previous bool
+}
+
+func diagnosePod(pod *corev1.Pod) []diagnostic {
+	result := []diagnostic{}
+	for _, status := range pod.Status.InitContainerStatuses {
+		if d := diagnoseContainer(pod.Name, status, initContainerFailureReason(status.Name)); d != nil {
+			result = append(result, *d)
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if d := diagnoseContainer(pod.Name, status, serverlessv1alpha2.DiagnosticReasonCrashLoopBackOff); d != nil {
+			result = append(result, *d)
+		}
+	}
+	return result
+}
+
+// initContainerFailureReason returns the reason reported when the init container exits with an error
+func initContainerFailureReason(name string) serverlessv1alpha2.DiagnosticReason {
+	switch name {
+	case resources.GitInitContainerName:
+		return serverlessv1alpha2.DiagnosticReasonGitSourceFetchFailed
+	case resources.InstallInitContainerName:
+		return serverlessv1alpha2.DiagnosticReasonDependencyInstallFailed
+	default:
+		return serverlessv1alpha2.DiagnosticReasonCrashLoopBackOff
+	}
+}
+
+func diagnoseContainer(podName string, status corev1.ContainerStatus, failureReason serverlessv1alpha2.DiagnosticReason) *diagnostic {
+	d := &diagnostic{
+		FunctionDiagnostic: serverlessv1alpha2.FunctionDiagnostic{
+			Pod:       podName,
+			Container: status.Name,
+		},
+	}
+
+	waiting := status.State.Waiting
+	if waiting != nil && imagePullReasons[waiting.Reason] {
+		d.Reason = serverlessv1alpha2.DiagnosticReasonImagePullFailed
+		d.Message = waiting.Message
+		return d
+	}
+
+	// the failure is either the current state of the container or the last one before the restart
+	terminated := status.State.Terminated
+	if terminated != nil && terminated.ExitCode == 0 {
+		return nil
+	}
+	crashLooping := waiting != nil && waiting.Reason == "CrashLoopBackOff"
+	if terminated == nil && crashLooping {
+		terminated = status.LastTerminationState.Terminated
+		d.previous = terminated != nil
+	}
+	if terminated == nil {
+		if !crashLooping {
+			return nil
+		}
+		d.Reason = serverlessv1alpha2.DiagnosticReasonCrashLoopBackOff
+		d.Message = waiting.Message
+		return d
+	}
+
+	d.ExitCode = ptr.To(terminated.ExitCode)
+	d.Message = terminated.Message
+	d.hasLogs = true
+	d.Reason = failureReason
+	if terminated.Reason == "OOMKilled" {
+		d.Reason = serverlessv1alpha2.DiagnosticReasonOOMKilled
+	}
+	return d
+}
@@ -0,0 +1,277 @@
+package diagnostics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func Test_podInspector_Inspect(t *testing.T) {
+	t.Run("report failing containers of function pods", func(t *testing.T) {
+		// Arrange
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "brave-babbage-name",
+				Namespace: "witty-wozniak-ns",
+			},
+		}
+		pullingPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "brave-babbage-2",
+				Namespace: "witty-wozniak-ns",
+				Labels:    f.SelectorLabels(),
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "function",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason:  "ImagePullBackOff",
+						Message: "Back-off pulling image",
+					}},
+				}},
+			},
+		}
+		crashingPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "brave-babbage-1",
+				Namespace: "witty-wozniak-ns",
+				Labels:    f.SelectorLabels(),
+			},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name: "function",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+						Reason: "CrashLoopBackOff",
+					}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 1,
+						Reason:   "Error",
+					}},
+				}},
+			},
+		}
+		otherPod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "other-pod",
+				Namespace: "witty-wozniak-ns",
+			},
+			Status: crashingPod.Status,
+		}
+		inspector := NewPodInspector(k8sfake.NewClientset(pullingPod, crashingPod, otherPod).CoreV1())
+
+		// Act
+		diagnostics, err := inspector.Inspect(context.Background(), f)
+
+		// Assert
+		require.NoError(t, err)
+		require.Equal(t, []serverlessv1alpha2.FunctionDiagnostic{
+			{
+				Pod:        "brave-babbage-1",
+				Container:  "function",
+				Reason:     serverlessv1alpha2.DiagnosticReasonCrashLoopBackOff,
+				ExitCode:   ptr.To[int32](1),
+				LogExcerpt: "fake logs",
+			},
+			{
+				Pod:       "brave-babbage-2",
+				Container: "function",
+				Reason:    serverlessv1alpha2.DiagnosticReasonImagePullFailed,
+				Message:   "Back-off pulling image",
+			},
+		}, diagnostics)
+	})
+}
+
+func Test_diagnoseContainer(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        corev1.ContainerStatus
+		failureReason serverlessv1alpha2.DiagnosticReason
+		want          *diagnostic
+	}{
+		{
+			name: "running container",
+			status: corev1.ContainerStatus{
+				Name:  "function",
+				State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			},
+			want: nil,
+		},
+		{
+			name: "succeeded init container",
+			status: corev1.ContainerStatus{
+				Name:  "init",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}},
+			},
+			failureReason: serverlessv1alpha2.DiagnosticReasonGitSourceFetchFailed,
+			want:          nil,
+		},
+		{
+			name: "failed git init container",
+			status: corev1.ContainerStatus{
+				Name:  "init",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 128, Reason: "Error"}},
+			},
+			failureReason: serverlessv1alpha2.DiagnosticReasonGitSourceFetchFailed,
+			want: &diagnostic{
+				FunctionDiagnostic: serverlessv1alpha2.FunctionDiagnostic{
+					Pod:       "modest-meitner",
+					Container: "init",
+					Reason:    serverlessv1alpha2.DiagnosticReasonGitSourceFetchFailed,
+					ExitCode:  ptr.To[int32](128),
+				},
+				hasLogs: true,
+			},
+		},
+		{
+			name: "restarted git init container",
+			status: corev1.ContainerStatus{
+				Name:                 "init",
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+			},
+			failureReason: serverlessv1alpha2.DiagnosticReasonGitSourceFetchFailed,
+			want: &diagnostic{
+				FunctionDiagnostic: serverlessv1alpha2.FunctionDiagnostic{
+					Pod:       "modest-meitner",
+					Container: "init",
+					Reason:    serverlessv1alpha2.DiagnosticReasonGitSourceFetchFailed,
+					ExitCode:  ptr.To[int32](1),
+				},
+				hasLogs:  true,
+				previous: true,
+			},
+		},
+		{
+			name: "failed install init container",
+			status: corev1.ContainerStatus{
+				Name:                 "install",
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1}},
+			},
+			failureReason: serverlessv1alpha2.DiagnosticReasonDependencyInstallFailed,
+			want: &diagnostic{
+				FunctionDiagnostic: serverlessv1alpha2.FunctionDiagnostic{
+					Pod:       "modest-meitner",
+					Container: "install",
+					Reason:    serverlessv1alpha2.DiagnosticReasonDependencyInstallFailed,
+					ExitCode:  ptr.To[int32](1),
+				},
+				hasLogs:  true,
+				previous: true,
+			},
+		},
+		{
+			name: "out of memory container",
+			status: corev1.ContainerStatus{
+				Name:                 "function",
+				State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}},
+			},
+			want: &diagnostic{
+				FunctionDiagnostic: serverlessv1alpha2.FunctionDiagnostic{
+					Pod:       "modest-meitner",
+					Container: "function",
+					Reason:    serverlessv1alpha2.DiagnosticReasonOOMKilled,
+					ExitCode:  ptr.To[int32](137),
+				},
+				hasLogs:  true,
+				previous: true,
+			},
+		},
+		{
+			name: "crash loop without last termination state",
+			status: corev1.ContainerStatus{
+				Name:  "function",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff", Message: "back-off 5m0s"}},
+			},
+			want: &diagnostic{
+				FunctionDiagnostic: serverlessv1alpha2.FunctionDiagnostic{
+					Pod:       "modest-meitner",
+					Container: "function",
+					Reason:    serverlessv1alpha2.DiagnosticReasonCrashLoopBackOff,
+					Message:   "back-off 5m0s",
+				},
+			},
+		},
+		{
+			name: "invalid image",
+			status: corev1.ContainerStatus{
+				Name:  "function",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "InvalidImageName", Message: "invalid reference format"}},
+			},
+			want: &diagnostic{
+				FunctionDiagnostic: serverlessv1alpha2.FunctionDiagnostic{
+					Pod:       "modest-meitner",
+					Container: "function",
+					Reason:    serverlessv1alpha2.DiagnosticReasonImagePullFailed,
+					Message:   "invalid reference format",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failureReason := tt.failureReason
+			if failureReason == "" {
+				failureReason = serverlessv1alpha2.DiagnosticReasonCrashLoopBackOff
+			}
+
+			got := diagnoseContainer("modest-meitner", tt.status, failureReason)
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_dependencyInstallErrorRegex(t *testing.T) {
+	tests := []struct {
+		name string
+		logs string
+		want bool
+	}{
+		{
+			name: "npm error",
+			logs: "npm error code E404\nnpm error 404 Not Found - GET https://registry.npmjs.org/not-existing",
+			want: true,
+		},
+		{
+			name: "legacy npm error",
+			logs: "npm ERR! code ETARGET",
+			want: true,
+		},
+		{
+			name: "pip error",
+			logs: "Collecting requests==99.0\nERROR: Could not find a version that satisfies the requirement requests==99.0",
+			want: true,
+		},
+		{
+			name: "function error",
+			logs: "TypeError: Cannot read properties of undefined (reading 'data')",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, dependencyInstallErrorRegex.MatchString(tt.logs))
+		})
+	}
+}
+
+func Test_truncateLogs(t *testing.T) {
+	t.Run("keep short logs", func(t *testing.T) {
+		require.Equal(t, "first\nsecond", truncateLogs("first\nsecond\n"))
+	})
+	t.Run("keep whole lines from the end of long logs", func(t *testing.T) {
+		logs := strings.Repeat("a", maxLogExcerptSize) + "\nlast line\n"
+
+		require.Equal(t, "last line", truncateLogs(logs))
+	})
+}
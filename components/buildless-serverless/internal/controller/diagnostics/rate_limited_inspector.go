@@ -0,0 +1,66 @@
+package diagnostics
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// DefaultInspectionInterval is how often Pods of a not ready Function are inspected
+const DefaultInspectionInterval = 30 * time.Second
+
+type inspection struct {
+	at          time.Time
+	diagnostics []serverlessv1alpha2.FunctionDiagnostic
+}
+
+// rateLimitedInspector inspects Pods of each Function at most once per interval, because not ready Functions are requeued
+// much more often and every inspection lists Pods and streams logs from the API server.
+// Diagnostics found by the last inspection are returned in between
+type rateLimitedInspector struct {
+	inspector   PodInspector
+	interval    time.Duration
+	now         func() time.Time
+	mu          sync.Mutex
+	inspections map[types.UID]inspection
+}
+
+func NewRateLimitedPodInspector(inspector PodInspector, interval time.Duration) PodInspector {
+	return &rateLimitedInspector{
+		inspector:   inspector,
+		interval:    interval,
+		now:         time.Now,
+		inspections: map[types.UID]inspection{},
+	}
+}
+
+func (i *rateLimitedInspector) Inspect(ctx context.Context, f *serverlessv1alpha2.Function) ([]serverlessv1alpha2.FunctionDiagnostic, error) {
+	now := i.now()
+	i.mu.Lock()
+	for uid, last := range i.inspections {
+		// drop outdated inspections, also of Functions which became ready or were deleted
+		if now.Sub(last.at) >= i.interval {
+			delete(i.inspections, uid)
+		}
+	}
+	last, ok := i.inspections[f.GetUID()]
+	i.mu.Unlock()
+
+	if ok {
+		return slices.Clone(last.diagnostics), nil
+	}
+
+	diagnostics, err := i.inspector.Inspect(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	i.mu.Lock()
+	i.inspections[f.GetUID()] = inspection{at: now, diagnostics: diagnostics}
+	i.mu.Unlock()
+	return slices.Clone(diagnostics), nil
+}
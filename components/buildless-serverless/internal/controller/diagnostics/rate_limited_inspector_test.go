@@ -0,0 +1,78 @@
+package diagnostics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/diagnostics/automock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_rateLimitedInspector_Inspect(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	f := &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{Name: "gallant-goldberg-name", Namespace: "hopeful-hopper-ns", UID: "gallant-goldberg-uid"},
+	}
+	diagnostics := []serverlessv1alpha2.FunctionDiagnostic{
+		{Pod: "gallant-goldberg-1", Container: "function", Reason: serverlessv1alpha2.DiagnosticReasonCrashLoopBackOff},
+	}
+	fixInspector := func(inner PodInspector) *rateLimitedInspector {
+		i := NewRateLimitedPodInspector(inner, 30*time.Second).(*rateLimitedInspector)
+		i.now = func() time.Time { return now }
+		return i
+	}
+
+	t.Run("reuse diagnostics within interval", func(t *testing.T) {
+		// Arrange
+		inner := automock.NewPodInspector(t)
+		inner.On("Inspect", mock.Anything, f).Return(diagnostics, nil).Once()
+		i := fixInspector(inner)
+		_, err := i.Inspect(context.Background(), f)
+		require.NoError(t, err)
+		now = now.Add(10 * time.Second)
+
+		// Act
+		r, err := i.Inspect(context.Background(), f)
+
+		// Assert
+		require.NoError(t, err)
+		require.Equal(t, diagnostics, r)
+	})
+	t.Run("inspect again after interval", func(t *testing.T) {
+		// Arrange
+		inner := automock.NewPodInspector(t)
+		inner.On("Inspect", mock.Anything, f).Return(diagnostics, nil).Once()
+		inner.On("Inspect", mock.Anything, f).Return(nil, nil).Once()
+		i := fixInspector(inner)
+		_, err := i.Inspect(context.Background(), f)
+		require.NoError(t, err)
+		now = now.Add(30 * time.Second)
+
+		// Act
+		r, err := i.Inspect(context.Background(), f)
+
+		// Assert
+		require.NoError(t, err)
+		require.Empty(t, r)
+	})
+	t.Run("inspect again after failure", func(t *testing.T) {
+		// Arrange
+		inner := automock.NewPodInspector(t)
+		inner.On("Inspect", mock.Anything, f).Return(nil, context.DeadlineExceeded).Once()
+		inner.On("Inspect", mock.Anything, f).Return(diagnostics, nil).Once()
+		i := fixInspector(inner)
+		_, err := i.Inspect(context.Background(), f)
+		require.Error(t, err)
+
+		// Act
+		r, err := i.Inspect(context.Background(), f)
+
+		// Assert
+		require.NoError(t, err)
+		require.Equal(t, diagnostics, r)
+	})
+}
@@ -1,17 +1,89 @@
 package fsm
 
 import (
-	"strings"
+	"fmt"
+	"math/bits"
 
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-const (
-	warningMessagePrefix = "Warning"
-)
+// eventTypes assigns the event type to condition reasons set by the states,
+// conditions with the False status are always reported as Warning events
+var eventTypes = map[serverlessv1alpha2.ConditionReason]string{
+	// sFnValidateFunction, sFnConfigurationReady
+	serverlessv1alpha2.ConditionReasonInvalidFunctionSpec:   corev1.EventTypeWarning,
+	serverlessv1alpha2.ConditionReasonFunctionSpecValidated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonRuntimeDeprecated:     corev1.EventTypeWarning,
+	serverlessv1alpha2.ConditionReasonEnvOverrideDeprecated: corev1.EventTypeWarning,
+	// sFnHandleGitSources
+	serverlessv1alpha2.ConditionReasonSourceUpdated:      corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonSourceUpdateFailed: corev1.EventTypeWarning,
+	// sFnHandleServiceAccount
+	serverlessv1alpha2.ConditionReasonServiceAccountCreated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonServiceAccountUpdated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonServiceAccountDeleted: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonServiceAccountFailed:  corev1.EventTypeWarning,
+	// sFnHandleRoleBinding
+	serverlessv1alpha2.ConditionReasonRoleBindingCreated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonRoleBindingUpdated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonRoleBindingDeleted: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonRoleBindingFailed:  corev1.EventTypeWarning,
+	// sFnHandleDeployment
+	serverlessv1alpha2.ConditionReasonDeploymentCreated:        corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonDeploymentUpdated:        corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonDeploymentDeleted:        corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonDeploymentDeletionFailed: corev1.EventTypeWarning,
+	// sFnHandleService
+	serverlessv1alpha2.ConditionReasonServiceCreated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonServiceUpdated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonServiceDeleted: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonServiceFailed:  corev1.EventTypeWarning,
+	// sFnHandlePodDisruptionBudget
+	serverlessv1alpha2.ConditionReasonPodDisruptionBudgetCreated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonPodDisruptionBudgetUpdated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonPodDisruptionBudgetDeleted: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonPodDisruptionBudgetFailed:  corev1.EventTypeWarning,
+	// sFnHandleNetworkPolicy
+	serverlessv1alpha2.ConditionReasonNetworkPolicyCreated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonNetworkPolicyUpdated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonNetworkPolicyDeleted: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonNetworkPolicyFailed:  corev1.EventTypeWarning,
+	// sFnHandleExpose
+	serverlessv1alpha2.ConditionReasonExposeCreated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonExposeUpdated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonExposeDeleted: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonExposeFailed:  corev1.EventTypeWarning,
+	// sFnHandleSchedules
+	serverlessv1alpha2.ConditionReasonScheduleCreated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonScheduleUpdated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonScheduleDeleted: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonScheduleFailed:  corev1.EventTypeWarning,
+	// sFnHandleSubscriptions
+	serverlessv1alpha2.ConditionReasonSubscriptionCreated:   corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonSubscriptionUpdated:   corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonSubscriptionDeleted:   corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonSubscriptionFailed:    corev1.EventTypeWarning,
+	serverlessv1alpha2.ConditionReasonSubscriptionsReady:    corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonSubscriptionsNotReady: corev1.EventTypeWarning,
+	// sFnHandleScaledObject
+	serverlessv1alpha2.ConditionReasonScaledObjectCreated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonScaledObjectUpdated: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonScaledObjectDeleted: corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonScaledObjectFailed:  corev1.EventTypeWarning,
+	// sFnDeploymentStatus
+	serverlessv1alpha2.ConditionReasonDeploymentWaiting:       corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonDeploymentReady:         corev1.EventTypeNormal,
+	serverlessv1alpha2.ConditionReasonDeploymentFailed:        corev1.EventTypeWarning,
+	serverlessv1alpha2.ConditionReasonDependencyInstallFailed: corev1.EventTypeWarning,
+	serverlessv1alpha2.ConditionReasonMinReplicasNotAvailable: corev1.EventTypeWarning,
+}
 
+// emitEvent emits events for conditions changed by the current state
 func emitEvent(m *StateMachine) {
+	verbosity := m.State.Function.EventVerbosity()
 	for _, condition := range m.State.Function.Status.Conditions {
 		memorizedCondition := meta.FindStatusCondition(m.State.statusSnapshot.Conditions, condition.Type)
 
@@ -22,20 +94,57 @@ func emitEvent(m *StateMachine) {
 			continue
 		}
 
+		eventType := eventType(condition)
+		if verbosity == serverlessv1alpha2.EventVerbosityWarning && eventType != corev1.EventTypeWarning {
+			continue
+		}
+
 		m.EventRecorder.Event(
 			&m.State.Function,
-			eventType(condition, condition.Message),
+			eventType,
 			condition.Reason,
 			condition.Message,
 		)
 	}
 }
 
-func eventType(condition metav1.Condition, message string) string {
-	eventType := "Normal"
-	if condition.Status == metav1.ConditionFalse || strings.HasPrefix(message, warningMessagePrefix) {
-		eventType = "Warning"
+// emitRepeatedFailures counts failures (Warning conditions without the True status) which didn't change since the previous reconciliation,
+// and emits one aggregated event for the 2nd, 4th, 8th... occurrence instead of an event per reconciliation
+func emitRepeatedFailures(m *StateMachine) {
+	if m.Failures == nil {
+		return
+	}
+	verbosity := m.State.Function.EventVerbosity()
+	for _, condition := range m.State.Function.Status.Conditions {
+		if condition.Status == metav1.ConditionTrue || eventType(condition) != corev1.EventTypeWarning {
+			m.Failures.reset(m.State.Function.UID, condition.Type)
+			continue
+		}
+
+		count := m.Failures.observe(m.State.Function.UID, condition)
+		if count < 2 {
+			// the first occurrence was already emitted when the condition changed
+			continue
+		}
+		if verbosity != serverlessv1alpha2.EventVerbosityDebug && bits.OnesCount(uint(count)) != 1 {
+			continue
+		}
+
+		m.EventRecorder.Event(
+			&m.State.Function,
+			corev1.EventTypeWarning,
+			condition.Reason,
+			fmt.Sprintf("%s (repeated %d times)", condition.Message, count),
+		)
 	}
+}
 
-	return eventType
+func eventType(condition metav1.Condition) string {
+	if condition.Status == metav1.ConditionFalse {
+		return corev1.EventTypeWarning
+	}
+	if eventType, ok := eventTypes[serverlessv1alpha2.ConditionReason(condition.Reason)]; ok {
+		return eventType
+	}
+	return corev1.EventTypeNormal
 }
@@ -83,4 +83,148 @@ func Test_emitEvent(t *testing.T) {
 			require.Contains(t, expectedEvents, v)
 		}
 	})
+
+	t.Run("emit only warning events", func(t *testing.T) {
+		eventRecorder := record.NewFakeRecorder(5)
+		f := testFunctionConditions2.DeepCopy()
+		f.Annotations = map[string]string{v1alpha2.FunctionEventVerbosityAnnotation: "warning"}
+		sm := &StateMachine{
+			State: SystemState{
+				Function:       *f,
+				statusSnapshot: *testFunctionConditions1.Status.DeepCopy(),
+			},
+			EventRecorder: eventRecorder,
+		}
+
+		emitEvent(sm)
+
+		require.Len(t, eventRecorder.Events, 1)
+		require.Equal(t, "Warning test-reason test message 2", <-eventRecorder.Events)
+	})
+}
+
+func Test_emitRepeatedFailures(t *testing.T) {
+	fixFunction := func(annotations map[string]string) v1alpha2.Function {
+		return v1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				UID:         "cranky-curie-uid",
+				Annotations: annotations,
+			},
+			Status: v1alpha2.FunctionStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:    string(v1alpha2.ConditionRunning),
+						Status:  metav1.ConditionUnknown,
+						Reason:  string(v1alpha2.ConditionReasonMinReplicasNotAvailable),
+						Message: "Minimum replicas not available",
+					},
+					{
+						Type:    string(v1alpha2.ConditionConfigurationReady),
+						Status:  metav1.ConditionTrue,
+						Reason:  string(v1alpha2.ConditionReasonRuntimeDeprecated),
+						Message: "runtime is deprecated",
+					},
+				},
+			},
+		}
+	}
+
+	t.Run("aggregate repeated failures", func(t *testing.T) {
+		// Arrange
+		eventRecorder := record.NewFakeRecorder(10)
+		sm := &StateMachine{
+			State:         SystemState{Function: fixFunction(nil)},
+			EventRecorder: eventRecorder,
+			Failures:      NewFailureCounter(),
+		}
+
+		// Act
+		for range 5 {
+			emitRepeatedFailures(sm)
+		}
+
+		// Assert
+		require.Len(t, eventRecorder.Events, 2)
+		require.Equal(t, "Warning MinReplicasNotAvailable Minimum replicas not available (repeated 2 times)", <-eventRecorder.Events)
+		require.Equal(t, "Warning MinReplicasNotAvailable Minimum replicas not available (repeated 4 times)", <-eventRecorder.Events)
+	})
+	t.Run("emit every repeated failure with debug verbosity", func(t *testing.T) {
+		// Arrange
+		eventRecorder := record.NewFakeRecorder(10)
+		sm := &StateMachine{
+			State: SystemState{Function: fixFunction(map[string]string{
+				v1alpha2.FunctionEventVerbosityAnnotation: "debug",
+			})},
+			EventRecorder: eventRecorder,
+			Failures:      NewFailureCounter(),
+		}
+
+		// Act
+		for range 3 {
+			emitRepeatedFailures(sm)
+		}
+
+		// Assert
+		require.Len(t, eventRecorder.Events, 2)
+	})
+	t.Run("start counting again when failure is fixed", func(t *testing.T) {
+		// Arrange
+		eventRecorder := record.NewFakeRecorder(10)
+		sm := &StateMachine{
+			State:         SystemState{Function: fixFunction(nil)},
+			EventRecorder: eventRecorder,
+			Failures:      NewFailureCounter(),
+		}
+		emitRepeatedFailures(sm)
+		sm.State.Function.Status.Conditions[0].Status = metav1.ConditionTrue
+		sm.State.Function.Status.Conditions[0].Reason = string(v1alpha2.ConditionReasonDeploymentReady)
+		emitRepeatedFailures(sm)
+		sm.State.Function = fixFunction(nil)
+
+		// Act
+		emitRepeatedFailures(sm)
+
+		// Assert
+		require.Len(t, eventRecorder.Events, 0)
+		require.Empty(t, sm.Failures.failures["cranky-curie-uid"][string(v1alpha2.ConditionConfigurationReady)])
+	})
+}
+
+func Test_eventType(t *testing.T) {
+	tests := []struct {
+		name      string
+		condition metav1.Condition
+		want      string
+	}{
+		{
+			name:      "false condition",
+			condition: metav1.Condition{Status: metav1.ConditionFalse, Reason: string(v1alpha2.ConditionReasonDeploymentCreated)},
+			want:      "Warning",
+		},
+		{
+			name:      "warning reason of true condition",
+			condition: metav1.Condition{Status: metav1.ConditionTrue, Reason: string(v1alpha2.ConditionReasonRuntimeDeprecated)},
+			want:      "Warning",
+		},
+		{
+			name:      "warning reason of unknown condition",
+			condition: metav1.Condition{Status: metav1.ConditionUnknown, Reason: string(v1alpha2.ConditionReasonMinReplicasNotAvailable)},
+			want:      "Warning",
+		},
+		{
+			name:      "normal reason",
+			condition: metav1.Condition{Status: metav1.ConditionUnknown, Reason: string(v1alpha2.ConditionReasonDeploymentWaiting)},
+			want:      "Normal",
+		},
+		{
+			name:      "unknown reason",
+			condition: metav1.Condition{Status: metav1.ConditionTrue, Reason: "sleepy-sinoussi"},
+			want:      "Normal",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, eventType(tt.condition))
+		})
+	}
 }
@@ -0,0 +1,73 @@
+package fsm
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type failure struct {
+	reason  string
+	message string
+	count   int
+}
+
+// FailureCounter counts how many reconciliations in a row ended with the same failure of the Function's condition,
+// it's shared by all state machines and safe for concurrent use
+type FailureCounter struct {
+	mu       sync.Mutex
+	failures map[types.UID]map[string]*failure
+}
+
+func NewFailureCounter() *FailureCounter {
+	return &FailureCounter{
+		failures: map[types.UID]map[string]*failure{},
+	}
+}
+
+// Forget removes failures of the deleted Function
+func (c *FailureCounter) Forget(uid types.UID) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.failures, uid)
+}
+
+// observe returns the number of reconciliations in a row which ended with the condition's failure
+func (c *FailureCounter) observe(uid types.UID, condition metav1.Condition) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conditions, ok := c.failures[uid]
+	if !ok {
+		conditions = map[string]*failure{}
+		c.failures[uid] = conditions
+	}
+
+	f, ok := conditions[condition.Type]
+	if !ok || f.reason != condition.Reason || f.message != condition.Message {
+		f = &failure{reason: condition.Reason, message: condition.Message}
+		conditions[condition.Type] = f
+	}
+	f.count++
+	return f.count
+}
+
+// reset forgets the failure of the condition which is no longer failing
+func (c *FailureCounter) reset(uid types.UID, conditionType string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	conditions, ok := c.failures[uid]
+	if !ok {
+		return
+	}
+	delete(conditions, conditionType)
+	if len(conditions) == 0 {
+		delete(c.failures, uid)
+	}
+}
@@ -10,9 +10,14 @@ import (
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/diagnostics"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/git"
 	serverlessmetrics "github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	appsv1 "k8s.io/api/apps/v1"
 	apimachineryruntime "k8s.io/apimachinery/pkg/runtime"
@@ -48,6 +53,10 @@ type StateMachine struct {
 	FunctionConfig        config.FunctionConfig
 	Scheme                *apimachineryruntime.Scheme
 	GitChecker            git.AsyncLatestCommitChecker
+	PodInspector          diagnostics.PodInspector
+	Metrics               *serverlessmetrics.Collector
+	Tracer                trace.Tracer
+	Failures              *FailureCounter
 	EventRecorder         record.EventRecorder
 	IsKymaFipsModeEnabled bool
 }
@@ -68,6 +77,10 @@ func (m *StateMachine) Reconcile(ctx context.Context) (ctrl.Result, error) {
 	var startReconciliationTime = time.Now()
 	var err error
 	var result *ctrl.Result
+
+	ctx, span := m.tracer().Start(ctx, "Reconcile",
+		trace.WithAttributes(tracing.FunctionAttributes(&m.State.Function)...))
+	defer span.End()
 loop:
 	for m.nextFn != nil && err == nil {
 		select {
@@ -76,32 +89,70 @@ loop:
 			break loop
 
 		default:
-			m.Log.Info(fmt.Sprintf("switching state: %s", m.stateFnName()))
-			m.nextFn, result, err = m.nextFn(ctx, m)
-			if updateErr := updateFunctionStatus(ctx, m); updateErr != nil {
-				err = updateErr
-			}
+			m.nextFn, result, err = m.runState(ctx)
 		}
 	}
 
 	if result == nil {
 		result = &ctrl.Result{}
 	}
+	recordResult(span, *result, err)
+	emitRepeatedFailures(m)
 
 	m.Log.
 		With("error", err).
 		With("result", result).
 		Info("reconciliation done")
-	serverlessmetrics.PublishReconciliationTime(m.State.Function, startReconciliationTime)
+	m.Metrics.PublishReconciliationTime(m.State.Function, startReconciliationTime)
+	m.Metrics.PublishFunctionHealth(m.State.Function, m.State.ClusterDeployment, m.State.Commit)
 
 	return *result, err
 }
 
+// runState runs the current state function in its own span
+func (m *StateMachine) runState(ctx context.Context) (StateFn, *ctrl.Result, error) {
+	stateName := m.stateFnName()
+	m.Log.Info(fmt.Sprintf("switching state: %s", stateName))
+
+	ctx, span := m.tracer().Start(ctx, stateName)
+	defer span.End()
+
+	next, result, err := m.nextFn(ctx, m)
+	if updateErr := updateFunctionStatus(ctx, m); updateErr != nil {
+		err = updateErr
+	}
+	recordError(span, err)
+	return next, result, err
+}
+
+func (m *StateMachine) tracer() trace.Tracer {
+	if m.Tracer == nil {
+		return tracing.NoopTracer()
+	}
+	return m.Tracer
+}
+
+func recordResult(span trace.Span, result ctrl.Result, err error) {
+	span.SetAttributes(
+		attribute.Bool("reconcile.requeue", result.Requeue || result.RequeueAfter > 0), //nolint:staticcheck
+		attribute.String("reconcile.requeue_after", result.RequeueAfter.String()),
+	)
+	recordError(span, err)
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
 type StateMachineReconciler interface {
 	Reconcile(ctx context.Context) (ctrl.Result, error)
 }
 
-func New(client client.Client, functionConfig config.FunctionConfig, instance *serverlessv1alpha2.Function, startState StateFn, recorder record.EventRecorder, gitChecker git.AsyncLatestCommitChecker, scheme *apimachineryruntime.Scheme, log *zap.SugaredLogger, isKymaFipsModeEnabled bool) StateMachineReconciler {
+func New(client client.Client, functionConfig config.FunctionConfig, instance *serverlessv1alpha2.Function, startState StateFn, recorder record.EventRecorder, gitChecker git.AsyncLatestCommitChecker, podInspector diagnostics.PodInspector, metrics *serverlessmetrics.Collector, tracer trace.Tracer, failures *FailureCounter, scheme *apimachineryruntime.Scheme, log *zap.SugaredLogger, isKymaFipsModeEnabled bool) StateMachineReconciler {
 	sm := StateMachine{
 		nextFn: startState,
 		State: SystemState{
@@ -112,6 +163,10 @@ func New(client client.Client, functionConfig config.FunctionConfig, instance *s
 		Client:                client,
 		Scheme:                scheme,
 		GitChecker:            gitChecker,
+		PodInspector:          podInspector,
+		Metrics:               metrics,
+		Tracer:                tracer,
+		Failures:              failures,
 		EventRecorder:         recorder,
 		IsKymaFipsModeEnabled: isKymaFipsModeEnabled,
 	}
@@ -0,0 +1,81 @@
+package fsm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func sFnFirstTestState(_ context.Context, _ *StateMachine) (StateFn, *ctrl.Result, error) {
+	return sFnFailingTestState, nil, nil
+}
+
+func sFnFailingTestState(_ context.Context, _ *StateMachine) (StateFn, *ctrl.Result, error) {
+	return nil, nil, errors.New("wonderful-wright error")
+}
+
+func TestStateMachine_Reconcile(t *testing.T) {
+	t.Run("trace reconciliation and each state", func(t *testing.T) {
+		// Arrange
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		m := &StateMachine{
+			nextFn: sFnFirstTestState,
+			State: SystemState{
+				Function: v1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "vibrant-volhard-name",
+						Namespace: "focused-feynman-ns",
+					},
+				},
+			},
+			Log:    zap.NewNop().Sugar(),
+			Tracer: provider.Tracer("test"),
+		}
+		m.State.saveStatusSnapshot()
+
+		// Act
+		_, err := m.Reconcile(context.Background())
+
+		// Assert
+		require.EqualError(t, err, "wonderful-wright error")
+		spans := recorder.Ended()
+		require.Len(t, spans, 3)
+
+		require.Equal(t, "sFnFirstTestState", spans[0].Name())
+		require.Equal(t, codes.Unset, spans[0].Status().Code)
+
+		require.Equal(t, "sFnFailingTestState", spans[1].Name())
+		require.Equal(t, codes.Error, spans[1].Status().Code)
+
+		reconcileSpan := spans[2]
+		require.Equal(t, "Reconcile", reconcileSpan.Name())
+		require.Equal(t, codes.Error, reconcileSpan.Status().Code)
+		require.Contains(t, reconcileSpan.Attributes(), attribute.String("function.name", "vibrant-volhard-name"))
+		require.Contains(t, reconcileSpan.Attributes(), attribute.String("function.namespace", "focused-feynman-ns"))
+		// state spans are children of the reconciliation span
+		require.Equal(t, reconcileSpan.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		require.Equal(t, reconcileSpan.SpanContext().SpanID(), spans[1].Parent().SpanID())
+	})
+	t.Run("reconcile without tracer", func(t *testing.T) {
+		m := &StateMachine{
+			nextFn: sFnFailingTestState,
+			Log:    zap.NewNop().Sugar(),
+		}
+		m.State.saveStatusSnapshot()
+
+		_, err := m.Reconcile(context.Background())
+
+		require.EqualError(t, err, "wonderful-wright error")
+	})
+}
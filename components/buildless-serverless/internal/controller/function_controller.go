@@ -23,16 +23,25 @@ import (
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/diagnostics"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/git"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/sharding"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/state"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"golang.org/x/time/rate"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -51,6 +60,11 @@ type FunctionReconciler struct {
 	Config                config.FunctionConfig
 	EventRecorder         record.EventRecorder
 	GitChecker            git.AsyncLatestCommitChecker
+	PodInspector          diagnostics.PodInspector
+	Metrics               *metrics.Collector
+	Tracer                trace.Tracer
+	Failures              *fsm.FailureCounter
+	Shards                *sharding.Coordinator
 	HealthCh              chan bool
 	IsKymaFipsModeEnabled bool
 }
@@ -60,8 +74,22 @@ type FunctionReconciler struct {
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete;deletecollection
 // +kubebuilder:rbac:groups=apps,resources=deployments/status,verbs=get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=gateway.kyma-project.io,resources=apirules,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterroles,verbs=bind,resourceNames=view
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=eventing.kyma-project.io,resources=subscriptions,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=list
+// +kubebuilder:rbac:groups="",resources=pods/log,verbs=get
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -81,8 +109,12 @@ func (fr *FunctionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	if !instance.DeletionTimestamp.IsZero() {
 		return ctrl.Result{}, nil
 	}
+	if !fr.Shards.OwnsFunction(&instance) {
+		log.Debug("function belongs to shard held by another replica")
+		return ctrl.Result{}, nil
+	}
 
-	sm := fsm.New(fr.Client, fr.Config, &instance, state.StartState(), fr.EventRecorder, fr.GitChecker, fr.Scheme, log, fr.IsKymaFipsModeEnabled)
+	sm := fsm.New(fr.Client, fr.Config, &instance, state.StartState(), fr.EventRecorder, fr.GitChecker, fr.PodInspector, fr.Metrics, fr.Tracer, fr.Failures, fr.Scheme, log, fr.IsKymaFipsModeEnabled)
 	return sm.Reconcile(ctx)
 }
 
@@ -91,24 +123,38 @@ func (fr *FunctionReconciler) SetupWithManager(mgr ctrl.Manager) (controller.Con
 	return ctrl.NewControllerManagedBy(mgr).
 		Named("function-controller").
 		For(&serverlessv1alpha2.Function{}).
-		WithEventFilter(buildPredicates()).
+		WithEventFilter(buildPredicates(fr.Metrics, fr.Failures)).
+		WithEventFilter(predicate.NewPredicateFuncs(fr.Shards.OwnsObject)).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&corev1.ServiceAccount{}).
+		Owns(&rbacv1.RoleBinding{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&batchv1.CronJob{}).
 		Named("function").
 		WithOptions(controller.Options{
-			RateLimiter: workqueue.NewTypedMaxOfRateLimiter[reconcile.Request](
-				workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](
-					250*time.Millisecond,
-					5*time.Minute,
-				),
-				&workqueue.TypedBucketRateLimiter[reconcile.Request]{
-					Limiter: rate.NewLimiter(rate.Limit(10), 100),
-				},
-			),
+			MaxConcurrentReconciles: fr.Config.MaxConcurrentReconciles,
+			RateLimiter:             newRateLimiter(fr.Config.RateLimiter),
+			// every replica reconciles functions from its own shards when sharding is enabled
+			NeedLeaderElection: ptr.To(!fr.Config.Sharding.Enabled()),
 		}).
 		Build(fr)
 }
 
+// newRateLimiter combines the per-function exponential backoff with the token bucket limiting all functions
+func newRateLimiter(cfg config.RateLimiterConfig) workqueue.TypedRateLimiter[reconcile.Request] {
+	return workqueue.NewTypedMaxOfRateLimiter[reconcile.Request](
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](
+			cfg.BaseDelay,
+			cfg.MaxDelay,
+		),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{
+			Limiter: rate.NewLimiter(rate.Limit(cfg.Frequency), cfg.Burst),
+		},
+	)
+}
+
 func (fr *FunctionReconciler) sendHealthCheck() {
 	fr.Log.Debug("health check request received")
 
@@ -120,7 +166,7 @@ func (fr *FunctionReconciler) sendHealthCheck() {
 	}
 }
 
-func buildPredicates() predicate.Funcs {
+func buildPredicates(collector *metrics.Collector, failures *fsm.FailureCounter) predicate.Funcs {
 	// Predicate to skip reconciliation when the object is being deleted
 	return predicate.Funcs{
 		// Allow update events
@@ -131,8 +177,13 @@ func buildPredicates() predicate.Funcs {
 		CreateFunc: func(e event.CreateEvent) bool {
 			return true
 		},
-		// Don't allow delete events
+		// Don't allow delete events, owned resources are removed by the garbage collector,
+		// only data kept for metrics and events of the deleted function is removed
 		DeleteFunc: func(e event.DeleteEvent) bool {
+			if f, ok := e.Object.(*serverlessv1alpha2.Function); ok {
+				collector.Forget(*f)
+				failures.Forget(f.UID)
+			}
 			return false
 		},
 		// Allow generic events (e.g., external triggers)
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_buildPredicates(t *testing.T) {
+	t.Run("forget metrics of deleted function", func(t *testing.T) {
+		// Arrange
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gallant-goldberg-name",
+				Namespace: "peaceful-pike-ns",
+				UID:       "gallant-goldberg-uid",
+			},
+		}
+		collector := metrics.NewCollector(false)
+		collector.PublishFunctionsTotal(*f)
+		predicates := buildPredicates(collector, fsm.NewFailureCounter())
+
+		// Act
+		allowed := predicates.Delete(event.DeleteEvent{Object: f})
+
+		// Assert
+		require.False(t, allowed)
+		// function with the same UID is counted again
+		collector.PublishFunctionsTotal(*f)
+		require.Equal(t, float64(2), testutil.ToFloat64(collector.FunctionsTotal))
+	})
+	t.Run("skip delete event of owned resource", func(t *testing.T) {
+		predicates := buildPredicates(metrics.NewCollector(false), fsm.NewFailureCounter())
+
+		allowed := predicates.Delete(event.DeleteEvent{Object: &appsv1.Deployment{}})
+
+		require.False(t, allowed)
+	})
+}
+
+func Test_newRateLimiter(t *testing.T) {
+	t.Run("back off failing function up to the max delay", func(t *testing.T) {
+		// Arrange
+		rl := newRateLimiter(config.RateLimiterConfig{
+			BaseDelay: time.Second,
+			MaxDelay:  3 * time.Second,
+			Frequency: 1000,
+			Burst:     1000,
+		})
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "brave-bohr-name"}}
+
+		// Act
+		delays := []time.Duration{rl.When(req), rl.When(req), rl.When(req)}
+
+		// Assert
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}, delays)
+	})
+	t.Run("delay functions over the burst", func(t *testing.T) {
+		// Arrange
+		rl := newRateLimiter(config.RateLimiterConfig{
+			BaseDelay: time.Millisecond,
+			MaxDelay:  time.Millisecond,
+			Frequency: 1,
+			Burst:     1,
+		})
+
+		// Act
+		first := rl.When(reconcile.Request{NamespacedName: types.NamespacedName{Name: "kind-kare-name"}})
+		second := rl.When(reconcile.Request{NamespacedName: types.NamespacedName{Name: "calm-curie-name"}})
+
+		// Assert
+		require.Equal(t, time.Millisecond, first)
+		require.Greater(t, second, 500*time.Millisecond)
+	})
+}
@@ -25,8 +25,10 @@ type asyncLatestCommitChecker struct {
 }
 
 type OrderResult struct {
-	Commit    string
-	Error     error
+	Commit string
+	Error  error
+	// Duration is the time taken by the git repository lookup
+	Duration  time.Duration
 	timestamp time.Time
 }
 
@@ -58,12 +60,14 @@ func (c *asyncLatestCommitChecker) PlaceOrder(orderID, repo, ref string, auth *G
 
 	go func() {
 		c.log.Debugf("starting async latest commit check for %s %s", repo, ref)
+		start := time.Now()
 		commit, err := c.getLatestCommit(repo, ref, auth)
 
 		c.log.Debugf("finished async lalatestst commit check for %s %s with commit %s", repo, ref, commit)
 		c.cache.Store(orderID, &OrderResult{
 			Commit:    commit,
 			Error:     err,
+			Duration:  time.Since(start),
 			timestamp: time.Now(),
 		})
 	}()
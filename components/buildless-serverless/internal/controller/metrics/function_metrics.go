@@ -0,0 +1,224 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	functionConditionDesc = prometheus.NewDesc(
+		"serverless_function_condition",
+		"State of the function condition (1 for the current status of the condition, 0 for other statuses)",
+		[]string{"namespace", "name", "condition", "status"}, nil,
+	)
+	functionReadyReplicasDesc = prometheus.NewDesc(
+		"serverless_function_ready_replicas",
+		"Number of ready replicas of the function deployment",
+		[]string{"namespace", "name"}, nil,
+	)
+	functionDesiredReplicasDesc = prometheus.NewDesc(
+		"serverless_function_desired_replicas",
+		"Number of desired replicas of the function deployment",
+		[]string{"namespace", "name"}, nil,
+	)
+	functionLastRolloutDesc = prometheus.NewDesc(
+		"serverless_function_last_successful_rollout_seconds",
+		"Seconds since the function deployment was last successfully rolled out",
+		[]string{"namespace", "name"}, nil,
+	)
+	functionGitCommitLagDesc = prometheus.NewDesc(
+		"serverless_function_git_commit_lag_seconds",
+		"Seconds since the latest commit of the git repository was detected without being deployed (0 when the function runs the latest commit)",
+		[]string{"namespace", "name"}, nil,
+	)
+
+	conditionStatuses = []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}
+)
+
+type functionHealthInfo struct {
+	// function keeps only the metadata needed to check if the function is still reconciled by this replica
+	function          *serverlessv1alpha2.Function
+	conditions        map[string]metav1.ConditionStatus
+	hasReplicas       bool
+	readyReplicas     int32
+	desiredReplicas   int32
+	rolloutGeneration int64
+	lastRolloutTime   *time.Time
+	hasGitSources     bool
+	pendingCommit     string
+	pendingSince      *time.Time
+}
+
+// functionHealthCollector exposes gauges labelled by the function namespace and name,
+// the time-based values are calculated while metrics are collected
+type functionHealthCollector struct {
+	mu        sync.Mutex
+	functions map[types.NamespacedName]*functionHealthInfo
+	now       func() time.Time
+	// owns reports if the function is reconciled by this replica, it is nil when all functions are
+	owns func(*serverlessv1alpha2.Function) bool
+}
+
+func newFunctionHealthCollector() *functionHealthCollector {
+	return &functionHealthCollector{
+		functions: map[types.NamespacedName]*functionHealthInfo{},
+		now:       time.Now,
+	}
+}
+
+// PublishFunctionHealth updates per-function gauges with the function status, the cluster deployment and the latest commit of the git repository
+func (c *Collector) PublishFunctionHealth(f serverlessv1alpha2.Function, deployment *appsv1.Deployment, latestCommit string) {
+	if c == nil || c.health == nil {
+		return
+	}
+	c.health.update(f, deployment, latestCommit)
+}
+
+// ReportOwnedFunctions limits per-function gauges to functions reconciled by this replica,
+// gauges of functions from shards taken over by other replicas are dropped
+func (c *Collector) ReportOwnedFunctions(owns func(*serverlessv1alpha2.Function) bool) {
+	if c == nil || c.health == nil {
+		return
+	}
+	c.health.mu.Lock()
+	defer c.health.mu.Unlock()
+
+	c.health.owns = owns
+}
+
+func (c *functionHealthCollector) update(f serverlessv1alpha2.Function, deployment *appsv1.Deployment, latestCommit string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := types.NamespacedName{Namespace: f.Namespace, Name: f.Name}
+	fi, ok := c.functions[key]
+	if !ok {
+		fi = &functionHealthInfo{}
+		c.functions[key] = fi
+	}
+	fi.function = &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{Namespace: f.Namespace, Name: f.Name, Labels: f.Labels},
+	}
+
+	fi.conditions = map[string]metav1.ConditionStatus{}
+	for _, cond := range f.Status.Conditions {
+		fi.conditions[cond.Type] = metav1.ConditionStatus(cond.Status)
+	}
+
+	// deployment is not known when the reconciliation stops before the deployment status is checked
+	if deployment != nil {
+		fi.hasReplicas = true
+		fi.readyReplicas = deployment.Status.ReadyReplicas
+		fi.desiredReplicas = 1
+		if deployment.Spec.Replicas != nil {
+			fi.desiredReplicas = *deployment.Spec.Replicas
+		}
+	}
+
+	c.updateRollout(fi, f, deployment)
+	c.updateCommitLag(fi, f, latestCommit)
+}
+
+func (c *functionHealthCollector) forget(key types.NamespacedName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.functions, key)
+}
+
+func (c *functionHealthCollector) updateRollout(fi *functionHealthInfo, f serverlessv1alpha2.Function, deployment *appsv1.Deployment) {
+	running := findCondition(f.Status.Conditions, serverlessv1alpha2.ConditionRunning)
+	if deployment == nil || running == nil || running.Status != metav1.ConditionTrue {
+		return
+	}
+	if fi.rolloutGeneration == deployment.Generation {
+		return // rollout already registered
+	}
+	rolloutTime := c.now()
+	if fi.rolloutGeneration == 0 {
+		// the rollout could happen before the controller started
+		rolloutTime = running.LastTransitionTime.Time
+	}
+	fi.rolloutGeneration = deployment.Generation
+	fi.lastRolloutTime = &rolloutTime
+}
+
+func (c *functionHealthCollector) updateCommitLag(fi *functionHealthInfo, f serverlessv1alpha2.Function, latestCommit string) {
+	fi.hasGitSources = f.HasGitSources()
+	if !fi.hasGitSources || latestCommit == "" {
+		fi.pendingCommit = ""
+		fi.pendingSince = nil
+		return
+	}
+	if f.Status.GitRepository != nil && f.Status.GitRepository.Commit == latestCommit {
+		// the latest commit is deployed
+		fi.pendingCommit = ""
+		fi.pendingSince = nil
+		return
+	}
+	if fi.pendingCommit == latestCommit {
+		return // commit already waits for the rollout
+	}
+	now := c.now()
+	fi.pendingCommit = latestCommit
+	fi.pendingSince = &now
+}
+
+func findCondition(conditions []metav1.Condition, conditionType serverlessv1alpha2.ConditionType) *metav1.Condition {
+	for i := range conditions {
+		if conditions[i].Type == string(conditionType) {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+func (c *functionHealthCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- functionConditionDesc
+	ch <- functionReadyReplicasDesc
+	ch <- functionDesiredReplicasDesc
+	ch <- functionLastRolloutDesc
+	ch <- functionGitCommitLagDesc
+}
+
+func (c *functionHealthCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, fi := range c.functions {
+		if c.owns != nil && !c.owns(fi.function) {
+			delete(c.functions, key)
+			continue
+		}
+		for condition, current := range fi.conditions {
+			for _, status := range conditionStatuses {
+				value := 0.0
+				if status == current {
+					value = 1
+				}
+				ch <- prometheus.MustNewConstMetric(functionConditionDesc, prometheus.GaugeValue, value, key.Namespace, key.Name, condition, string(status))
+			}
+		}
+		if fi.hasReplicas {
+			ch <- prometheus.MustNewConstMetric(functionReadyReplicasDesc, prometheus.GaugeValue, float64(fi.readyReplicas), key.Namespace, key.Name)
+			ch <- prometheus.MustNewConstMetric(functionDesiredReplicasDesc, prometheus.GaugeValue, float64(fi.desiredReplicas), key.Namespace, key.Name)
+		}
+		if fi.lastRolloutTime != nil {
+			ch <- prometheus.MustNewConstMetric(functionLastRolloutDesc, prometheus.GaugeValue, now.Sub(*fi.lastRolloutTime).Seconds(), key.Namespace, key.Name)
+		}
+		if fi.hasGitSources {
+			commitLag := 0.0
+			if fi.pendingSince != nil {
+				commitLag = now.Sub(*fi.pendingSince).Seconds()
+			}
+			ch <- prometheus.MustNewConstMetric(functionGitCommitLagDesc, prometheus.GaugeValue, commitLag, key.Namespace, key.Name)
+		}
+	}
+}
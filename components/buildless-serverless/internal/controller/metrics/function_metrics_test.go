@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func Test_functionHealthCollector(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fixCollector := func() *functionHealthCollector {
+		c := newFunctionHealthCollector()
+		c.now = func() time.Time { return now }
+		return c
+	}
+	fixFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "jolly-jennings-name",
+				Namespace: "quirky-quimby-ns",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Source: serverlessv1alpha2.Source{
+					GitRepository: &serverlessv1alpha2.GitRepositorySource{
+						URL: "https://github.com/kyma-project/serverless.git",
+					},
+				},
+			},
+			Status: serverlessv1alpha2.FunctionStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:               string(serverlessv1alpha2.ConditionRunning),
+						Status:             metav1.ConditionTrue,
+						LastTransitionTime: metav1.NewTime(now.Add(-time.Minute)),
+					},
+				},
+				GitRepository: &serverlessv1alpha2.GitRepositoryStatus{
+					Commit: "old-commit",
+				},
+			},
+		}
+	}
+	fixDeployment := func() *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Generation: 1},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+			Status:     appsv1.DeploymentStatus{ReadyReplicas: 2},
+		}
+	}
+
+	t.Run("publish function health", func(t *testing.T) {
+		// Arrange
+		c := fixCollector()
+
+		// Act
+		c.update(fixFunction(), fixDeployment(), "old-commit")
+
+		// Assert
+		expected := `
+# HELP serverless_function_condition State of the function condition (1 for the current status of the condition, 0 for other statuses)
+# TYPE serverless_function_condition gauge
+serverless_function_condition{condition="Running",name="jolly-jennings-name",namespace="quirky-quimby-ns",status="False"} 0
+serverless_function_condition{condition="Running",name="jolly-jennings-name",namespace="quirky-quimby-ns",status="True"} 1
+serverless_function_condition{condition="Running",name="jolly-jennings-name",namespace="quirky-quimby-ns",status="Unknown"} 0
+# HELP serverless_function_desired_replicas Number of desired replicas of the function deployment
+# TYPE serverless_function_desired_replicas gauge
+serverless_function_desired_replicas{name="jolly-jennings-name",namespace="quirky-quimby-ns"} 3
+# HELP serverless_function_git_commit_lag_seconds Seconds since the latest commit of the git repository was detected without being deployed (0 when the function runs the latest commit)
+# TYPE serverless_function_git_commit_lag_seconds gauge
+serverless_function_git_commit_lag_seconds{name="jolly-jennings-name",namespace="quirky-quimby-ns"} 0
+# HELP serverless_function_last_successful_rollout_seconds Seconds since the function deployment was last successfully rolled out
+# TYPE serverless_function_last_successful_rollout_seconds gauge
+serverless_function_last_successful_rollout_seconds{name="jolly-jennings-name",namespace="quirky-quimby-ns"} 60
+# HELP serverless_function_ready_replicas Number of ready replicas of the function deployment
+# TYPE serverless_function_ready_replicas gauge
+serverless_function_ready_replicas{name="jolly-jennings-name",namespace="quirky-quimby-ns"} 2
+`
+		require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+	})
+	t.Run("measure lag of not deployed commit", func(t *testing.T) {
+		// Arrange
+		c := fixCollector()
+		c.update(fixFunction(), fixDeployment(), "new-commit")
+		now = now.Add(30 * time.Second)
+
+		// Act
+		c.update(fixFunction(), fixDeployment(), "new-commit")
+
+		// Assert
+		expected := `
+# HELP serverless_function_git_commit_lag_seconds Seconds since the latest commit of the git repository was detected without being deployed (0 when the function runs the latest commit)
+# TYPE serverless_function_git_commit_lag_seconds gauge
+serverless_function_git_commit_lag_seconds{name="jolly-jennings-name",namespace="quirky-quimby-ns"} 30
+`
+		require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "serverless_function_git_commit_lag_seconds"))
+	})
+	t.Run("register new rollout", func(t *testing.T) {
+		// Arrange
+		c := fixCollector()
+		c.update(fixFunction(), fixDeployment(), "")
+		deployment := fixDeployment()
+		deployment.Generation = 2
+
+		// Act
+		c.update(fixFunction(), deployment, "")
+		now = now.Add(10 * time.Second)
+
+		// Assert
+		expected := `
+# HELP serverless_function_last_successful_rollout_seconds Seconds since the function deployment was last successfully rolled out
+# TYPE serverless_function_last_successful_rollout_seconds gauge
+serverless_function_last_successful_rollout_seconds{name="jolly-jennings-name",namespace="quirky-quimby-ns"} 10
+`
+		require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected), "serverless_function_last_successful_rollout_seconds"))
+	})
+	t.Run("skip replicas when deployment is unknown", func(t *testing.T) {
+		// Arrange
+		c := fixCollector()
+		f := fixFunction()
+		f.Spec.Source.GitRepository = nil
+
+		// Act
+		c.update(f, nil, "")
+
+		// Assert
+		require.Equal(t, 3, testutil.CollectAndCount(c))
+	})
+	t.Run("forget deleted function", func(t *testing.T) {
+		// Arrange
+		c := NewCollector(true)
+		c.PublishFunctionHealth(fixFunction(), fixDeployment(), "")
+
+		// Act
+		c.Forget(fixFunction())
+
+		// Assert
+		require.Equal(t, 0, testutil.CollectAndCount(c.health))
+	})
+	t.Run("drop function reconciled by other replica", func(t *testing.T) {
+		// Arrange
+		c := NewCollector(true)
+		c.PublishFunctionHealth(fixFunction(), fixDeployment(), "")
+		owned := true
+		c.ReportOwnedFunctions(func(f *serverlessv1alpha2.Function) bool {
+			require.Equal(t, "jolly-jennings-name", f.Name)
+			return owned
+		})
+		require.Equal(t, 7, testutil.CollectAndCount(c.health))
+
+		// Act
+		// the shard of the function has been taken over by another replica
+		owned = false
+
+		// Assert
+		require.Equal(t, 0, testutil.CollectAndCount(c.health))
+		require.Empty(t, c.health.functions)
+	})
+	t.Run("do nothing when per-function metrics are disabled", func(t *testing.T) {
+		c := NewCollector(false)
+
+		require.NotPanics(t, func() {
+			c.PublishFunctionHealth(fixFunction(), fixDeployment(), "")
+		})
+	})
+}
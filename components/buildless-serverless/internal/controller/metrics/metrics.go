@@ -1,12 +1,14 @@
 package metrics
 
 import (
+	"sync"
+	"time"
+
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/utils/ptr"
-	"sigs.k8s.io/controller-runtime/pkg/metrics"
-	"time"
 )
 
 type functionStateReachTimeInfo struct {
@@ -15,48 +17,91 @@ type functionStateReachTimeInfo struct {
 	registeredCondition *serverlessv1alpha2.ConditionType
 }
 
-var (
-	FunctionsTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "serverless_function_processed_total",
-			Help: "Total number of functions processed (each function is counted only once, even if it is processed multiple times)",
-		},
-		[]string{"runtime", "source"},
-	)
-	ReconciliationsTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "serverless_function_reconciliations_total",
-			Help: "Total number of reconciliations for functions (each reconciliation is counted, even if it is for the same function)",
-		},
-		[]string{"runtime", "source"},
-	)
-	ReconciliationTime = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "serverless_function_reconciliation_time_seconds",
-			Help:    "Time taken for a single reconciliation of a function (including all single reconciliation)",
-			Buckets: []float64{0.1, 0.3, 1, 3, 10, 30, 90, 300},
-		},
-		[]string{"runtime", "source"},
-	)
-	StateReachTime = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "serverless_function_state_reach_time_seconds",
-			Help:    "Time taken for a function to reach a specific state (only generation changes are counted, not every reconciliation)",
-			Buckets: []float64{0.1, 0.3, 1, 3, 10, 30, 90, 300},
-		},
-		[]string{"runtime", "source", "state"},
-	)
-	stateReachTimeInfo     = map[string]functionStateReachTimeInfo{}
-	processedFunctionsUIDs = sets.Set[string]{}
-)
+// Collector publishes metrics of processed functions.
+// It's safe for concurrent use by many reconciliations, a nil Collector publishes nothing
+type Collector struct {
+	FunctionsTotal       *prometheus.CounterVec
+	ReconciliationsTotal *prometheus.CounterVec
+	ReconciliationTime   *prometheus.HistogramVec
+	StateReachTime       *prometheus.HistogramVec
+
+	mu                     sync.Mutex
+	stateReachTimeInfo     map[types.UID]functionStateReachTimeInfo
+	processedFunctionsUIDs sets.Set[types.UID]
+	// health is nil unless per-function metrics are enabled
+	health *functionHealthCollector
+}
+
+// NewCollector returns the collector with aggregated metrics,
+// perFunction enables gauges labelled by the function namespace and name,
+// the number of their series grows with the number of functions
+func NewCollector(perFunction bool) *Collector {
+	c := &Collector{
+		FunctionsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "serverless_function_processed_total",
+				Help: "Total number of functions processed (each function is counted only once, even if it is processed multiple times)",
+			},
+			[]string{"runtime", "source"},
+		),
+		ReconciliationsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "serverless_function_reconciliations_total",
+				Help: "Total number of reconciliations for functions (each reconciliation is counted, even if it is for the same function)",
+			},
+			[]string{"runtime", "source"},
+		),
+		ReconciliationTime: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "serverless_function_reconciliation_time_seconds",
+				Help:    "Time taken for a single reconciliation of a function (including all single reconciliation)",
+				Buckets: []float64{0.1, 0.3, 1, 3, 10, 30, 90, 300},
+			},
+			[]string{"runtime", "source"},
+		),
+		StateReachTime: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    "serverless_function_state_reach_time_seconds",
+				Help:    "Time taken for a function to reach a specific state (only generation changes are counted, not every reconciliation)",
+				Buckets: []float64{0.1, 0.3, 1, 3, 10, 30, 90, 300},
+			},
+			[]string{"runtime", "source", "state"},
+		),
+		stateReachTimeInfo:     map[types.UID]functionStateReachTimeInfo{},
+		processedFunctionsUIDs: sets.Set[types.UID]{},
+	}
+	if perFunction {
+		c.health = newFunctionHealthCollector()
+	}
+	return c
+}
 
-func Register() {
-	metrics.Registry.MustRegister(
-		FunctionsTotal,
-		ReconciliationsTotal,
-		ReconciliationTime,
-		StateReachTime,
+// MustRegister registers all metrics of the collector in the registry
+func (c *Collector) MustRegister(registry prometheus.Registerer) {
+	registry.MustRegister(
+		c.FunctionsTotal,
+		c.ReconciliationsTotal,
+		c.ReconciliationTime,
+		c.StateReachTime,
 	)
+	if c.health != nil {
+		registry.MustRegister(c.health)
+	}
+}
+
+// Forget removes data kept for the deleted function
+func (c *Collector) Forget(f serverlessv1alpha2.Function) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.stateReachTimeInfo, f.UID)
+	c.processedFunctionsUIDs.Delete(f.UID)
+	c.mu.Unlock()
+
+	if c.health != nil {
+		c.health.forget(types.NamespacedName{Namespace: f.Namespace, Name: f.Name})
+	}
 }
 
 func runtimeName(f serverlessv1alpha2.Function) string {
@@ -70,27 +115,43 @@ func sourceType(f serverlessv1alpha2.Function) string {
 	return "inline"
 }
 
-func PublishFunctionsTotal(f serverlessv1alpha2.Function) {
-	uid := string(f.UID)
-	if processedFunctionsUIDs.Has(uid) {
+func (c *Collector) PublishFunctionsTotal(f serverlessv1alpha2.Function) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.processedFunctionsUIDs.Has(f.UID) {
 		return // Function already processed, no need to increment
 	}
-	processedFunctionsUIDs.Insert(uid)
-	FunctionsTotal.WithLabelValues(runtimeName(f), sourceType(f)).Inc()
+	c.processedFunctionsUIDs.Insert(f.UID)
+	c.FunctionsTotal.WithLabelValues(runtimeName(f), sourceType(f)).Inc()
 }
 
-func PublishReconciliationsTotal(f serverlessv1alpha2.Function) {
-	ReconciliationsTotal.WithLabelValues(runtimeName(f), sourceType(f)).Inc()
+func (c *Collector) PublishReconciliationsTotal(f serverlessv1alpha2.Function) {
+	if c == nil {
+		return
+	}
+	c.ReconciliationsTotal.WithLabelValues(runtimeName(f), sourceType(f)).Inc()
 }
 
-func PublishReconciliationTime(f serverlessv1alpha2.Function, start time.Time) {
+func (c *Collector) PublishReconciliationTime(f serverlessv1alpha2.Function, start time.Time) {
+	if c == nil {
+		return
+	}
 	duration := time.Since(start).Seconds()
-	ReconciliationTime.WithLabelValues(runtimeName(f), sourceType(f)).Observe(duration)
+	c.ReconciliationTime.WithLabelValues(runtimeName(f), sourceType(f)).Observe(duration)
 }
 
-func StartForStateReachTime(f serverlessv1alpha2.Function) {
-	uid := string(f.UID)
-	fi, ok := stateReachTimeInfo[uid]
+func (c *Collector) StartForStateReachTime(f serverlessv1alpha2.Function) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fi, ok := c.stateReachTimeInfo[f.UID]
 	if !ok {
 		fi = functionStateReachTimeInfo{}
 	}
@@ -103,7 +164,7 @@ func StartForStateReachTime(f serverlessv1alpha2.Function) {
 	fi.startTime = ptr.To(time.Now())
 	fi.generation = f.GetGeneration()
 	fi.registeredCondition = nil
-	stateReachTimeInfo[uid] = fi
+	c.stateReachTimeInfo[f.UID] = fi
 }
 
 // conditionIsGreaterThanRegistered returns true if cond is greater than registeredCond according to the order: nil < ConfigurationReady < Running < Ready
@@ -121,9 +182,14 @@ func conditionIsGreaterThanRegistered(cond serverlessv1alpha2.ConditionType, reg
 	return order[cond] > regVal
 }
 
-func PublishStateReachTime(f serverlessv1alpha2.Function, toState serverlessv1alpha2.ConditionType) {
-	uid := string(f.UID)
-	fi, ok := stateReachTimeInfo[uid]
+func (c *Collector) PublishStateReachTime(f serverlessv1alpha2.Function, toState serverlessv1alpha2.ConditionType) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fi, ok := c.stateReachTimeInfo[f.UID]
 	if !ok || fi.startTime == nil {
 		return // No start time recorded, nothing to publish
 	}
@@ -131,8 +197,8 @@ func PublishStateReachTime(f serverlessv1alpha2.Function, toState serverlessv1al
 		return // duplicated state publishing, nothing to publish
 	}
 	fi.registeredCondition = &toState
-	stateReachTimeInfo[uid] = fi
+	c.stateReachTimeInfo[f.UID] = fi
 
 	duration := time.Since(*fi.startTime).Seconds()
-	StateReachTime.WithLabelValues(runtimeName(f), sourceType(f), string(toState)).Observe(duration)
+	c.StateReachTime.WithLabelValues(runtimeName(f), sourceType(f), string(toState)).Observe(duration)
 }
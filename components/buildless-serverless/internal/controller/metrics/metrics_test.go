@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCollector(t *testing.T) {
+	fixFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "serene-shannon-name",
+				Namespace:  "eager-euclid-ns",
+				UID:        "serene-shannon-uid",
+				Generation: 2,
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+			},
+		}
+	}
+
+	t.Run("count function only once", func(t *testing.T) {
+		// Arrange
+		c := NewCollector(false)
+
+		// Act
+		c.PublishFunctionsTotal(fixFunction())
+		c.PublishFunctionsTotal(fixFunction())
+
+		// Assert
+		require.Equal(t, float64(1), testutil.ToFloat64(c.FunctionsTotal))
+	})
+	t.Run("forget deleted function", func(t *testing.T) {
+		// Arrange
+		c := NewCollector(true)
+		c.PublishFunctionsTotal(fixFunction())
+		c.StartForStateReachTime(fixFunction())
+
+		// Act
+		c.Forget(fixFunction())
+
+		// Assert
+		require.Empty(t, c.processedFunctionsUIDs)
+		require.Empty(t, c.stateReachTimeInfo)
+		// function with the same UID is counted again
+		c.PublishFunctionsTotal(fixFunction())
+		require.Equal(t, float64(2), testutil.ToFloat64(c.FunctionsTotal))
+	})
+	t.Run("publish state reach time once per generation", func(t *testing.T) {
+		// Arrange
+		c := NewCollector(false)
+		c.StartForStateReachTime(fixFunction())
+
+		// Act
+		c.PublishStateReachTime(fixFunction(), serverlessv1alpha2.ConditionRunning)
+		c.PublishStateReachTime(fixFunction(), serverlessv1alpha2.ConditionRunning)
+
+		// Assert
+		require.Equal(t, 1, testutil.CollectAndCount(c.StateReachTime))
+		require.Equal(t, uint64(1), histogramSampleCount(t, c))
+	})
+	t.Run("nil collector publishes nothing", func(t *testing.T) {
+		var c *Collector
+
+		require.NotPanics(t, func() {
+			c.PublishFunctionsTotal(fixFunction())
+			c.PublishReconciliationsTotal(fixFunction())
+			c.StartForStateReachTime(fixFunction())
+			c.PublishStateReachTime(fixFunction(), serverlessv1alpha2.ConditionRunning)
+			c.PublishFunctionHealth(fixFunction(), nil, "")
+			c.Forget(fixFunction())
+		})
+	})
+}
+
+func histogramSampleCount(t *testing.T, c *Collector) uint64 {
+	m := &dto.Metric{}
+	require.NoError(t, c.StateReachTime.WithLabelValues("nodejs22", "inline", "Running").(prometheus.Histogram).Write(m))
+	return m.GetHistogram().GetSampleCount()
+}
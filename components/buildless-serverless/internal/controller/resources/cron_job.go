@@ -0,0 +1,155 @@
+package resources
+
+import (
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	ScheduleContainerName = "invoker"
+	// MaxScheduleCronJobNameLength is the limit of the CronJob name, longer names can't be used by created Jobs
+	MaxScheduleCronJobNameLength = 52
+	ScheduleHeader               = "X-Function-Schedule"
+)
+
+var (
+	scheduleInvokerCPU    = resource.MustParse("10m")
+	scheduleInvokerMemory = resource.MustParse("16Mi")
+)
+
+type CronJob struct {
+	*batchv1.CronJob
+	function       *serverlessv1alpha2.Function
+	functionConfig *config.FunctionConfig
+	schedule       serverlessv1alpha2.FunctionSchedule
+}
+
+// NewCronJob builds the CronJob invoking the Function's Service according to the schedule.
+func NewCronJob(f *serverlessv1alpha2.Function, c *config.FunctionConfig, schedule serverlessv1alpha2.FunctionSchedule) *CronJob {
+	cj := &CronJob{
+		function:       f,
+		functionConfig: c,
+		schedule:       schedule,
+	}
+
+	cj.CronJob = cj.construct()
+	return cj
+}
+
+// NewCronJobs builds CronJobs for all the Function's schedules.
+func NewCronJobs(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []*batchv1.CronJob {
+	cronJobs := make([]*batchv1.CronJob, 0, len(f.Spec.Schedules))
+	for _, schedule := range f.Spec.Schedules {
+		cronJobs = append(cronJobs, NewCronJob(f, c, schedule).CronJob)
+	}
+	return cronJobs
+}
+
+// ScheduleCronJobName returns the name of the CronJob created for the schedule.
+func ScheduleCronJobName(f *serverlessv1alpha2.Function, scheduleName string) string {
+	return fmt.Sprintf("%s-%s", f.GetName(), scheduleName)
+}
+
+// ScheduleLabels returns labels of CronJobs created for the Function's schedules.
+// They don't contain the selector labels, so the Function's Service doesn't route traffic to the invoking Pods.
+func ScheduleLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.InternalFunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelScheduleValue,
+	})
+}
+
+func (cj *CronJob) construct() *batchv1.CronJob {
+	cronJobLabels := labels.Merge(ScheduleLabels(cj.function), map[string]string{
+		serverlessv1alpha2.FunctionScheduleLabel: cj.schedule.Name,
+	})
+
+	return &batchv1.CronJob{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "CronJob",
+			APIVersion: "batch/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ScheduleCronJobName(cj.function, cj.schedule.Name),
+			Namespace: cj.function.GetNamespace(),
+			Labels:    labels.Merge(cj.function.GetLabels(), cronJobLabels),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule: cj.schedule.Schedule,
+			TimeZone: cj.schedule.TimeZone,
+			Suspend:  ptr.To(cj.schedule.Suspend),
+			// invocations aren't retried or run in parallel, because the Function may not be idempotent
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To[int32](1),
+			FailedJobsHistoryLimit:     ptr.To[int32](1),
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: cronJobLabels,
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: ptr.To[int32](0),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: cronJobLabels,
+							Annotations: map[string]string{
+								// native sidecar doesn't block the Job completion
+								istioNativeSidecarLabelKey: "true",
+							},
+						},
+						Spec: cj.podSpec(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func (cj *CronJob) podSpec() corev1.PodSpec {
+	return corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		Containers: []corev1.Container{
+			{
+				Name:  ScheduleContainerName,
+				Image: cj.functionConfig.Images.RepoFetcher,
+				// the image contains busybox wget, which exits with an error when the Function responds with an error
+				Command: []string{"wget"},
+				Args: []string{
+					"-q", "-O", "-",
+					"--header", "Content-Type: application/json",
+					"--header", fmt.Sprintf("%s: %s", ScheduleHeader, cj.schedule.Name),
+					"--post-data", cj.schedule.Payload,
+					cj.functionURL(),
+				},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    scheduleInvokerCPU,
+						corev1.ResourceMemory: scheduleInvokerMemory,
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: scheduleInvokerMemory,
+					},
+				},
+				SecurityContext: mergeContainerSecurityContext(nil),
+			},
+		},
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsUser:  ptr.To[int64](1000),
+			RunAsGroup: ptr.To[int64](1000),
+			SeccompProfile: &corev1.SeccompProfile{
+				Type: corev1.SeccompProfileTypeRuntimeDefault,
+			},
+		},
+		AutomountServiceAccountToken: ptr.To(false),
+	}
+}
+
+func (cj *CronJob) functionURL() string {
+	return fmt.Sprintf("http://%s.%s.svc:%d/", cj.function.GetName(), cj.function.GetNamespace(), FunctionServicePort(cj.function))
+}
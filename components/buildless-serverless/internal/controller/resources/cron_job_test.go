@@ -0,0 +1,93 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewCronJob(t *testing.T) {
+	c := &config.FunctionConfig{
+		Images: config.ImagesConfig{
+			RepoFetcher: "test-repo-fetcher-image",
+		},
+	}
+	f := &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-function-name",
+			Namespace: "test-function-namespace",
+			UID:       "test-uid",
+			Labels: map[string]string{
+				"test-label": "test-value",
+			},
+		},
+	}
+	t.Run("create proper cron job", func(t *testing.T) {
+		schedule := serverlessv1alpha2.FunctionSchedule{
+			Name:     "nightly",
+			Schedule: "0 2 * * *",
+			TimeZone: ptr.To("Europe/Warsaw"),
+			Payload:  `{"report":"daily"}`,
+		}
+		expectedLabels := map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+			"serverless.kyma-project.io/resource":      "schedule",
+			"serverless.kyma-project.io/schedule":      "nightly",
+		}
+
+		r := NewCronJob(f, c, schedule)
+
+		require.NotNil(t, r)
+		cj := r.CronJob
+		require.Equal(t, "test-function-name-nightly", cj.GetName())
+		require.Equal(t, "test-function-namespace", cj.GetNamespace())
+		require.Equal(t, "test-value", cj.GetLabels()["test-label"])
+		require.Equal(t, "0 2 * * *", cj.Spec.Schedule)
+		require.Equal(t, ptr.To("Europe/Warsaw"), cj.Spec.TimeZone)
+		require.Equal(t, ptr.To(false), cj.Spec.Suspend)
+		require.Equal(t, batchv1.ForbidConcurrent, cj.Spec.ConcurrencyPolicy)
+		require.Equal(t, expectedLabels, cj.Spec.JobTemplate.Spec.Template.GetLabels())
+		require.Equal(t, "true", cj.Spec.JobTemplate.Spec.Template.GetAnnotations()["sidecar.istio.io/nativeSidecar"])
+		require.Len(t, cj.Spec.JobTemplate.Spec.Template.Spec.Containers, 1)
+		container := cj.Spec.JobTemplate.Spec.Template.Spec.Containers[0]
+		require.Equal(t, "test-repo-fetcher-image", container.Image)
+		require.Equal(t, []string{"wget"}, container.Command)
+		require.Equal(t, []string{
+			"-q", "-O", "-",
+			"--header", "Content-Type: application/json",
+			"--header", "X-Function-Schedule: nightly",
+			"--post-data", `{"report":"daily"}`,
+			"http://test-function-name.test-function-namespace.svc:80/",
+		}, container.Args)
+	})
+	t.Run("call the function port of headless service", func(t *testing.T) {
+		fn := f.DeepCopy()
+		fn.Spec.Service = &serverlessv1alpha2.FunctionService{Headless: true}
+
+		r := NewCronJob(fn, c, serverlessv1alpha2.FunctionSchedule{Name: "nightly", Schedule: "0 2 * * *"})
+
+		args := r.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Args
+		require.Equal(t, "http://test-function-name.test-function-namespace.svc:8080/", args[len(args)-1])
+	})
+	t.Run("create cron jobs for all schedules", func(t *testing.T) {
+		fn := f.DeepCopy()
+		fn.Spec.Schedules = []serverlessv1alpha2.FunctionSchedule{
+			{Name: "nightly", Schedule: "0 2 * * *"},
+			{Name: "hourly", Schedule: "@hourly", Suspend: true},
+		}
+
+		r := NewCronJobs(fn, c)
+
+		require.Len(t, r, 2)
+		require.Equal(t, "test-function-name-nightly", r[0].GetName())
+		require.Equal(t, "test-function-name-hourly", r[1].GetName())
+		require.Equal(t, ptr.To(true), r[1].Spec.Suspend)
+	})
+}
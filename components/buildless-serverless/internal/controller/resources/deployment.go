@@ -3,6 +3,7 @@ package resources
 import (
 	"fmt"
 	"path"
+	"slices"
 	"strings"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
@@ -18,6 +19,20 @@ import (
 )
 
 const DefaultDeploymentReplicas int32 = 1
+const (
+	FunctionContainerName          = "function"
+	GitInitContainerName           = "init"
+	InstallInitContainerName       = "install"
+	FunctionPort             int32 = 8080
+)
+const (
+	FunctionTimeoutEnvName          = "FUNC_TIMEOUT"
+	FunctionRequestBodyLimitEnvName = "REQ_MB_LIMIT"
+)
+const (
+	defaultServiceAccountTokenPath              = "token"
+	defaultServiceAccountTokenExpirationSeconds = int64(3600)
+)
 const (
 	istioConfigLabelKey                       = "proxy.istio.io/config"
 	istioEnableHoldUntilProxyStartLabelValue  = "{ \"holdApplicationUntilProxyStarts\": true }"
@@ -72,6 +87,13 @@ func DeploySetImage(image string) deployOptions {
 	}
 }
 
+// DeploySetServiceAccountName - set the name of the ServiceAccount used by the deployment's pods
+func DeploySetServiceAccountName(name string) deployOptions {
+	return func(d *Deployment) {
+		d.serviceAccountName = name
+	}
+}
+
 // DeploySetCmd - set the container command for the deployment
 func DeploySetCmd(cmd []string) deployOptions {
 	return func(d *Deployment) {
@@ -79,6 +101,14 @@ func DeploySetCmd(cmd []string) deployOptions {
 	}
 }
 
+// DeploySkipDependenciesInstall - don't install dependencies in the init container,
+// used when the image already contains the Function's sources and dependencies
+func DeploySkipDependenciesInstall() deployOptions {
+	return func(d *Deployment) {
+		d.installCmd = nil
+	}
+}
+
 type Deployment struct {
 	*appsv1.Deployment
 	functionConfig           *config.FunctionConfig
@@ -95,6 +125,8 @@ type Deployment struct {
 	podImage                 string
 	podEnvs                  []corev1.EnvVar
 	podCmd                   []string
+	installCmd               []string
+	serviceAccountName       string
 	podSecurityContext       *corev1.PodSecurityContext
 	containerSecurityContext *corev1.SecurityContext
 }
@@ -113,7 +145,8 @@ func NewDeployment(f *serverlessv1alpha2.Function, c *config.FunctionConfig, clu
 		deployName:               "",
 		deployGeneratedName:      fmt.Sprintf("%s-", f.Name),
 		podImage:                 runtimeImage(f, c),
-		podEnvs:                  append(generalEnvs(f, c), sourceEnvs(f)...),
+		podEnvs:                  append(generalEnvs(f, c), withoutEnvs(sourceEnvs(f), f.Spec.Env)...),
+		serviceAccountName:       ServiceAccountName(f),
 		podSecurityContext:       podSecurityContext(f),
 		containerSecurityContext: containerSecurityContext(f),
 		podCmd: []string{
@@ -121,6 +154,11 @@ func NewDeployment(f *serverlessv1alpha2.Function, c *config.FunctionConfig, clu
 			"-c",
 			runtimeCommand(f),
 		},
+		installCmd: []string{
+			"sh",
+			"-c",
+			installCommand(f),
+		},
 	}
 
 	if appName != "" {
@@ -175,6 +213,47 @@ func (d *Deployment) ContainerSecurityContext() *corev1.SecurityContext {
 	return d.Spec.Template.Spec.Containers[0].SecurityContext
 }
 
+// EffectiveEnvs returns envs set in the Function's container and their sources
+func (d *Deployment) EffectiveEnvs() []serverlessv1alpha2.EnvStatus {
+	container := d.Spec.Template.Spec.Containers[0]
+	result := []serverlessv1alpha2.EnvStatus{}
+	for _, envFrom := range container.EnvFrom {
+		status := serverlessv1alpha2.EnvStatus{
+			Name: fmt.Sprintf("%s*", envFrom.Prefix),
+		}
+		if envFrom.ConfigMapRef != nil {
+			status.Source = serverlessv1alpha2.EnvSourceConfigMap
+			status.Reference = envFrom.ConfigMapRef.Name
+		} else if envFrom.SecretRef != nil {
+			status.Source = serverlessv1alpha2.EnvSourceSecret
+			status.Reference = envFrom.SecretRef.Name
+		}
+		result = append(result, status)
+	}
+
+	systemEnvNames := map[string]bool{}
+	for _, name := range SystemEnvNames(d.function, d.functionConfig) {
+		systemEnvNames[name] = true
+	}
+	userEnvNames := map[string]bool{}
+	for _, env := range d.function.Spec.Env {
+		userEnvNames[env.Name] = true
+	}
+	for _, env := range container.Env {
+		source := serverlessv1alpha2.EnvSourceSystem
+		if userEnvNames[env.Name] && systemEnvNames[env.Name] {
+			source = serverlessv1alpha2.EnvSourceOverride
+		} else if userEnvNames[env.Name] {
+			source = serverlessv1alpha2.EnvSourceUser
+		}
+		result = append(result, serverlessv1alpha2.EnvStatus{
+			Name:   env.Name,
+			Source: source,
+		})
+	}
+	return result
+}
+
 func (d *Deployment) RuntimeImage() string {
 	return d.Spec.Template.Spec.Containers[0].Image
 }
@@ -232,67 +311,175 @@ func (d *Deployment) currentAnnotations() map[string]string {
 
 func (d *Deployment) podSpec() corev1.PodSpec {
 	secretVolumes, secretVolumeMounts := d.deploymentSecretVolumes()
+	configMapVolumes, configMapVolumeMounts := d.deploymentConfigMapVolumes()
+	tokenVolumes, tokenVolumeMounts := d.deploymentServiceAccountTokenVolumes()
+
+	volumes := append(d.volumes(), secretVolumes...)
+	volumes = append(volumes, configMapVolumes...)
+	volumes = append(volumes, tokenVolumes...)
+
+	volumeMounts := append(d.volumeMounts(), secretVolumeMounts...)
+	volumeMounts = append(volumeMounts, configMapVolumeMounts...)
+	volumeMounts = append(volumeMounts, tokenVolumeMounts...)
+
+	initContainers := append(d.initContainerForGitRepository(), d.initContainerForDependencies(volumeMounts)...)
+	initContainers = append(initContainers, userContainers(d.function.Spec.InitContainers)...)
 
 	return corev1.PodSpec{
-		Volumes:        append(d.volumes(), secretVolumes...),
-		InitContainers: d.initContainerForGitRepository(),
-		Containers: []corev1.Container{
+		Volumes:        volumes,
+		InitContainers: initContainers,
+		Containers: append([]corev1.Container{
 			{
-				Name:         "function",
+				Name:         FunctionContainerName,
 				Image:        d.podImage,
 				WorkingDir:   workingSourcesDir(d.function),
 				Command:      d.podCmd,
 				Resources:    d.resourceConfiguration(),
 				Env:          d.podEnvs,
-				VolumeMounts: append(d.volumeMounts(), secretVolumeMounts...),
+				EnvFrom:      d.function.Spec.EnvFrom,
+				VolumeMounts: volumeMounts,
 				Ports: []corev1.ContainerPort{
 					{
-						ContainerPort: 8080,
+						ContainerPort: FunctionPort,
 						Protocol:      "TCP",
 					},
 				},
-				StartupProbe: &corev1.Probe{
-					ProbeHandler: corev1.ProbeHandler{
-						HTTPGet: &corev1.HTTPGetAction{
-							Path: "/healthz",
-							Port: svcTargetPort,
-						},
-					},
-					InitialDelaySeconds: 0,
-					PeriodSeconds:       5,
-					SuccessThreshold:    1,
-					FailureThreshold:    30, // FailureThreshold * PeriodSeconds = 150s in this case, this should be enough for any function pod to start up
-				},
-				ReadinessProbe: &corev1.Probe{
-					ProbeHandler: corev1.ProbeHandler{
-						HTTPGet: &corev1.HTTPGetAction{
-							Path: "/healthz",
-							Port: svcTargetPort,
-						},
-					},
-					InitialDelaySeconds: 0, // startup probe exists, so delaying anything here doesn't make sense
-					FailureThreshold:    1,
-					PeriodSeconds:       5,
-					TimeoutSeconds:      2,
-				},
-				LivenessProbe: &corev1.Probe{
-					ProbeHandler: corev1.ProbeHandler{
-						HTTPGet: &corev1.HTTPGetAction{
-							Path: "/healthz",
-							Port: svcTargetPort,
-						},
-					},
-					FailureThreshold: 3,
-					PeriodSeconds:    5,
-					TimeoutSeconds:   4,
-				},
+				StartupProbe:    d.startupProbe(),
+				ReadinessProbe:  d.readinessProbe(),
+				LivenessProbe:   d.livenessProbe(),
+				Lifecycle:       d.lifecycle(),
 				SecurityContext: d.containerSecurityContext,
 			},
+		}, userContainers(d.function.Spec.Sidecars)...),
+		SecurityContext:               d.podSecurityContext,
+		TopologySpreadConstraints:     d.topologySpreadConstraints(),
+		NodeSelector:                  d.function.Spec.NodeSelector,
+		Tolerations:                   d.function.Spec.Tolerations,
+		Affinity:                      d.function.Spec.Affinity,
+		PriorityClassName:             d.function.Spec.PriorityClassName,
+		RuntimeClassName:              d.function.Spec.RuntimeClassName,
+		TerminationGracePeriodSeconds: d.function.Spec.TerminationGracePeriodSeconds,
+		ServiceAccountName:            d.serviceAccountName,
+		AutomountServiceAccountToken:  d.function.Spec.AutomountServiceAccountToken,
+	}
+}
+
+func (d *Deployment) startupProbe() *corev1.Probe {
+	probe := &corev1.Probe{
+		ProbeHandler:        healthzProbeHandler(),
+		InitialDelaySeconds: 0,
+		PeriodSeconds:       5,
+		SuccessThreshold:    1,
+		FailureThreshold:    30, // FailureThreshold * PeriodSeconds = 150s in this case, this should be enough for any function pod to start up
+	}
+	if probes := d.function.Spec.Probes; probes != nil {
+		overrideProbe(probe, probes.Startup)
+	}
+	return probe
+}
+
+func (d *Deployment) readinessProbe() *corev1.Probe {
+	probe := &corev1.Probe{
+		ProbeHandler:        healthzProbeHandler(),
+		InitialDelaySeconds: 0, // startup probe exists, so delaying anything here doesn't make sense
+		FailureThreshold:    1,
+		PeriodSeconds:       5,
+		TimeoutSeconds:      2,
+	}
+	if probes := d.function.Spec.Probes; probes != nil {
+		overrideProbe(probe, probes.Readiness)
+	}
+	return probe
+}
+
+func (d *Deployment) livenessProbe() *corev1.Probe {
+	probe := &corev1.Probe{
+		ProbeHandler:     healthzProbeHandler(),
+		FailureThreshold: 3,
+		PeriodSeconds:    5,
+		TimeoutSeconds:   4,
+	}
+	if probes := d.function.Spec.Probes; probes != nil {
+		overrideProbe(probe, probes.Liveness)
+	}
+	return probe
+}
+
+func healthzProbeHandler() corev1.ProbeHandler {
+	return corev1.ProbeHandler{
+		HTTPGet: &corev1.HTTPGetAction{
+			Path: "/healthz",
+			Port: svcTargetPort,
 		},
-		SecurityContext: d.podSecurityContext,
 	}
 }
 
+func overrideProbe(probe *corev1.Probe, override *serverlessv1alpha2.ProbeOverride) {
+	if override == nil {
+		return
+	}
+	if override.InitialDelaySeconds != nil {
+		probe.InitialDelaySeconds = *override.InitialDelaySeconds
+	}
+	if override.PeriodSeconds != nil {
+		probe.PeriodSeconds = *override.PeriodSeconds
+	}
+	if override.TimeoutSeconds != nil {
+		probe.TimeoutSeconds = *override.TimeoutSeconds
+	}
+	if override.FailureThreshold != nil {
+		probe.FailureThreshold = *override.FailureThreshold
+	}
+}
+
+func (d *Deployment) lifecycle() *corev1.Lifecycle {
+	if d.function.Spec.PreStop == nil {
+		return nil
+	}
+	return &corev1.Lifecycle{
+		PreStop: d.function.Spec.PreStop,
+	}
+}
+
+// topologySpreadConstraints returns constraints configured in the Function or,
+// when the Function may run more than one replica, spreads its Pods across zones and nodes
+func (d *Deployment) topologySpreadConstraints() []corev1.TopologySpreadConstraint {
+	if len(d.function.Spec.TopologySpreadConstraints) > 0 {
+		return d.function.Spec.TopologySpreadConstraints
+	}
+	if !d.function.MayRunMultipleReplicas() {
+		return nil
+	}
+
+	constraints := []corev1.TopologySpreadConstraint{}
+	for _, topologyKey := range []string{corev1.LabelTopologyZone, corev1.LabelHostname} {
+		constraints = append(constraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       topologyKey,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: d.selectorLabels,
+			},
+		})
+	}
+	return constraints
+}
+
+// userContainers returns sidecars or init containers defined in the Function
+// volume mounts refer to the Function's volumes and the container is hardened like the Function's container
+func userContainers(containers []corev1.Container) []corev1.Container {
+	result := []corev1.Container{}
+	for _, c := range containers {
+		container := *c.DeepCopy()
+		container.SecurityContext = mergeContainerSecurityContext(container.SecurityContext)
+		for i := range container.VolumeMounts {
+			container.VolumeMounts[i].Name = FunctionVolumeName(container.VolumeMounts[i].Name)
+		}
+		result = append(result, container)
+	}
+	return result
+}
+
 func (d *Deployment) initContainerForGitRepository() []corev1.Container {
 	if !d.function.HasGitSources() {
 		return []corev1.Container{}
@@ -300,7 +487,7 @@ func (d *Deployment) initContainerForGitRepository() []corev1.Container {
 
 	return []corev1.Container{
 		{
-			Name:       "init",
+			Name:       GitInitContainerName,
 			Image:      d.functionConfig.Images.RepoFetcher,
 			WorkingDir: workingSourcesDir(d.function),
 			Command: []string{
@@ -340,6 +527,28 @@ func (d *Deployment) initContainerForGitRepository() []corev1.Container {
 	}
 }
 
+// initContainerForDependencies writes the Function's sources and installs its dependencies before the Function starts,
+// so installation failures are reported separately from the Function's crashes
+func (d *Deployment) initContainerForDependencies(volumeMounts []corev1.VolumeMount) []corev1.Container {
+	if len(d.installCmd) == 0 {
+		return []corev1.Container{}
+	}
+
+	return []corev1.Container{
+		{
+			Name:            InstallInitContainerName,
+			Image:           d.podImage,
+			WorkingDir:      workingSourcesDir(d.function),
+			Command:         d.installCmd,
+			Resources:       d.resourceConfiguration(),
+			Env:             d.podEnvs,
+			EnvFrom:         d.function.Spec.EnvFrom,
+			VolumeMounts:    volumeMounts,
+			SecurityContext: d.containerSecurityContext,
+		},
+	}
+}
+
 func (d *Deployment) initContainerEnvs(isKymaFipsModeEnabled bool) []corev1.EnvVar {
 	envs := []corev1.EnvVar{
 		{
@@ -436,27 +645,49 @@ func (d *Deployment) volumes() []corev1.Volume {
 			},
 		})
 	}
+	for _, volume := range d.function.Spec.Volumes {
+		volumes = append(volumes, corev1.Volume{
+			Name:         FunctionVolumeName(volume.Name),
+			VolumeSource: functionVolumeSource(volume),
+		})
+	}
 	return volumes
 }
 
-func (d *Deployment) volumeMounts() []corev1.VolumeMount {
-	volumeMounts := []corev1.VolumeMount{
-		{
-			Name:      "sources",
-			MountPath: workingSourcesDir(d.function),
-		},
-		{
-			Name:      "tmp",
-			ReadOnly:  false,
-			MountPath: "/tmp",
-		},
+// GeneratedVolumeNamePrefix is reserved for names of volumes generated for the Function's mounts and volumes.
+// Secret volumes are named after the Secret, so Secret mounts and Function volumes can't use the prefix.
+const GeneratedVolumeNamePrefix = "fn-"
+
+// FunctionVolumeName returns the name of the Deployment's volume built for the Function's volume
+// the prefix prevents collisions with volumes required by the Function
+func FunctionVolumeName(name string) string {
+	return fmt.Sprintf("%svolume-%s", GeneratedVolumeNamePrefix, name)
+}
+
+func functionVolumeSource(volume serverlessv1alpha2.FunctionVolume) corev1.VolumeSource {
+	if volume.PersistentVolumeClaim != nil {
+		return corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: volume.PersistentVolumeClaim.ClaimName,
+				ReadOnly:  volume.PersistentVolumeClaim.ReadOnly,
+			},
+		}
 	}
-	if d.function.HasGitSources() {
-		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      "git-repository",
-			MountPath: "/git-repository",
-		})
+
+	emptyDir := &corev1.EmptyDirVolumeSource{}
+	if volume.EmptyDir != nil {
+		emptyDir.SizeLimit = volume.EmptyDir.SizeLimit
+		if volume.EmptyDir.InMemory {
+			emptyDir.Medium = corev1.StorageMediumMemory
+		}
+	}
+	return corev1.VolumeSource{
+		EmptyDir: emptyDir,
 	}
+}
+
+func (d *Deployment) volumeMounts() []corev1.VolumeMount {
+	volumeMounts := ReservedVolumeMounts(d.function)
 	if d.function.HasNodejsRuntime() {
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
 			Name:      "package-registry-config",
@@ -476,10 +707,45 @@ func (d *Deployment) volumeMounts() []corev1.VolumeMount {
 				SubPath:   "pip.conf",
 			})
 	}
+	for _, volume := range d.function.Spec.Volumes {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      FunctionVolumeName(volume.Name),
+			ReadOnly:  volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ReadOnly,
+			MountPath: volume.MountPath,
+		})
+	}
+	return volumeMounts
+}
+
+// ReservedVolumeMounts returns mounts required by the Function's container to run the sources.
+// User defined mounts must not collide with them.
+func ReservedVolumeMounts(f *serverlessv1alpha2.Function) []corev1.VolumeMount {
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "sources",
+			MountPath: workingSourcesDir(f),
+		},
+		{
+			Name:      "tmp",
+			ReadOnly:  false,
+			MountPath: "/tmp",
+		},
+	}
+	if f.HasGitSources() {
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "git-repository",
+			MountPath: "/git-repository",
+		})
+	}
 	return volumeMounts
 }
 
 func containerSecurityContext(f *serverlessv1alpha2.Function) *corev1.SecurityContext {
+	return mergeContainerSecurityContext(f.Spec.ContainerSecurityContext)
+}
+
+// mergeContainerSecurityContext returns the custom security context with unset fields taken from the base one
+func mergeContainerSecurityContext(custom *corev1.SecurityContext) *corev1.SecurityContext {
 	baseSecCtx := &corev1.SecurityContext{
 		Privileged: ptr.To(false),
 		Capabilities: &corev1.Capabilities{
@@ -492,12 +758,12 @@ func containerSecurityContext(f *serverlessv1alpha2.Function) *corev1.SecurityCo
 		RunAsNonRoot:             ptr.To(true),
 	}
 
-	if f.Spec.ContainerSecurityContext == nil {
+	if custom == nil {
 		// no custom security context provided, return base one
 		return baseSecCtx
 	}
 
-	customSecCtx := *f.Spec.ContainerSecurityContext
+	customSecCtx := *custom
 	customSecCtx.Privileged = getValueOrDefault(customSecCtx.Privileged, baseSecCtx.Privileged)
 	customSecCtx.Capabilities = getValueOrDefault(customSecCtx.Capabilities, baseSecCtx.Capabilities)
 	customSecCtx.ProcMount = getValueOrDefault(customSecCtx.ProcMount, baseSecCtx.ProcMount)
@@ -578,10 +844,19 @@ func workingSourcesDir(f *serverlessv1alpha2.Function) string {
 }
 
 func runtimeCommand(f *serverlessv1alpha2.Function) string {
+	result := []string{"set -e;"}
+	if envs := runtimeCommandEnvs(f); envs != "" {
+		result = append(result, envs)
+	}
+	result = append(result, runtimeCommandStart(f))
+
+	return strings.Join(result, "\n")
+}
+
+func installCommand(f *serverlessv1alpha2.Function) string {
 	result := []string{"set -e;"}
 	result = append(result, runtimeCommandSources(f))
 	result = append(result, runtimeCommandInstall(f))
-	result = append(result, runtimeCommandStart(f))
 
 	return strings.Join(result, "\n")
 }
@@ -628,8 +903,15 @@ func runtimeCommandInstall(f *serverlessv1alpha2.Function) string {
 	if f.HasNodejsRuntime() {
 		return `NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`
 	} else if f.HasPythonRuntime() {
-		return `export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;`
+		return `PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;`
+	}
+	return ""
+}
+
+func runtimeCommandEnvs(f *serverlessv1alpha2.Function) string {
+	if f.HasPythonRuntime() {
+		// dependencies are installed by the init container to the shared sources volume
+		return `export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"`
 	}
 	return ""
 }
@@ -650,7 +932,104 @@ fi`
 	return ""
 }
 
+// requestLimitsEnvs translates request limits configured in the Function into envs read by runtimes
+func requestLimitsEnvs(f *serverlessv1alpha2.Function) []corev1.EnvVar {
+	envs := []corev1.EnvVar{}
+	if f.Spec.TimeoutSeconds != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  FunctionTimeoutEnvName,
+			Value: fmt.Sprint(*f.Spec.TimeoutSeconds),
+		})
+	}
+	if f.Spec.MaxRequestBodySize != nil {
+		envs = append(envs, corev1.EnvVar{
+			Name:  FunctionRequestBodyLimitEnvName,
+			Value: fmt.Sprint(RequestBodySizeInMi(*f.Spec.MaxRequestBodySize)),
+		})
+	}
+	return envs
+}
+
+// RequestBodySizeInMi returns the size rounded up to the whole number of mebibytes
+func RequestBodySizeInMi(size resource.Quantity) int64 {
+	const mebibyte = 1024 * 1024
+	return (size.Value() + mebibyte - 1) / mebibyte
+}
+
+// OverridableEnvs lists envs set by Serverless which can be replaced by the Function's spec.env
+var OverridableEnvs = []string{
+	"TRACE_COLLECTOR_ENDPOINT",
+	"PUBLISHER_PROXY_ADDRESS",
+	"PYTHONUNBUFFERED",
+}
+
 func generalEnvs(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []corev1.EnvVar {
+	return mergeEnvs(systemGeneralEnvs(f, c), f.Spec.Env)
+}
+
+// mergeEnvs drops system envs overridden by the user, user envs are appended in the order they are defined
+// so references between them ($(VAR_NAME)) are resolved the same way regardless of overrides.
+// Envs which are not overridable are replaced as well, such overrides are deprecated and reported by the controller
+func mergeEnvs(systemEnvs, userEnvs []corev1.EnvVar) []corev1.EnvVar {
+	return append(withoutEnvs(systemEnvs, userEnvs), userEnvs...)
+}
+
+// withoutEnvs returns envs which are not redefined in overrides
+func withoutEnvs(envs, overrides []corev1.EnvVar) []corev1.EnvVar {
+	overriddenNames := map[string]bool{}
+	for _, env := range overrides {
+		overriddenNames[env.Name] = true
+	}
+
+	result := []corev1.EnvVar{}
+	for _, env := range envs {
+		if !overriddenNames[env.Name] {
+			result = append(result, env)
+		}
+	}
+	return result
+}
+
+func IsOverridableEnv(name string) bool {
+	for _, overridable := range OverridableEnvs {
+		if overridable == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DeprecatedEnvOverrides returns names of envs set by Serverless which are redefined in the Function's spec.env
+// but are not overridable, such overrides are still accepted but will be rejected in the future
+func DeprecatedEnvOverrides(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []string {
+	systemEnvNames := map[string]bool{}
+	for _, name := range SystemEnvNames(f, c) {
+		systemEnvNames[name] = true
+	}
+
+	names := []string{}
+	for _, env := range f.Spec.Env {
+		if env.Name == FunctionTimeoutEnvName || env.Name == FunctionRequestBodyLimitEnvName {
+			// request limit envs are mapped onto spec.timeoutSeconds and spec.maxRequestBodySize
+			continue
+		}
+		if systemEnvNames[env.Name] && !IsOverridableEnv(env.Name) && !slices.Contains(names, env.Name) {
+			names = append(names, env.Name)
+		}
+	}
+	return names
+}
+
+// SystemEnvNames returns names of envs set by Serverless in the Function's container
+func SystemEnvNames(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []string {
+	names := []string{}
+	for _, env := range append(systemGeneralEnvs(f, c), sourceEnvs(f)...) {
+		names = append(names, env.Name)
+	}
+	return names
+}
+
+func systemGeneralEnvs(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []corev1.EnvVar {
 	spec := &f.Spec
 	envs := []corev1.EnvVar{
 		{
@@ -691,8 +1070,7 @@ func generalEnvs(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []cor
 			},
 		}...)
 	}
-	envs = append(envs, spec.Env...) //TODO: this order is critical, should we provide option for users to override envs?
-	return envs
+	return append(envs, requestLimitsEnvs(f)...)
 }
 
 func sourceEnvs(f *serverlessv1alpha2.Function) []corev1.EnvVar {
@@ -770,6 +1148,7 @@ func (d *Deployment) deploymentSecretVolumes() (volumes []corev1.Volume, volumeM
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
 					SecretName:  secretMount.SecretName,
+					Items:       secretMount.Items,
 					DefaultMode: ptr.To[int32](0666), //read and write only for everybody
 					Optional:    ptr.To(false),
 				},
@@ -786,3 +1165,75 @@ func (d *Deployment) deploymentSecretVolumes() (volumes []corev1.Volume, volumeM
 	}
 	return volumes, volumeMounts
 }
+
+func (d *Deployment) deploymentConfigMapVolumes() (volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) {
+	volumes = []corev1.Volume{}
+	volumeMounts = []corev1.VolumeMount{}
+	for i, configMapMount := range d.function.Spec.ConfigMapMounts {
+		// ConfigMap names may collide with Secret names, so volumes are named after their position
+		volumeName := fmt.Sprintf("%sconfigmap-%d", GeneratedVolumeNamePrefix, i)
+
+		volume := corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: configMapMount.ConfigMapName,
+					},
+					Items:       configMapMount.Items,
+					DefaultMode: getValueOrDefault(configMapMount.DefaultMode, ptr.To(corev1.ConfigMapVolumeSourceDefaultMode)),
+					Optional:    getValueOrDefault(configMapMount.Optional, ptr.To(false)),
+				},
+			},
+		}
+		volumes = append(volumes, volume)
+
+		volumeMount := corev1.VolumeMount{
+			Name:      volumeName,
+			ReadOnly:  true,
+			MountPath: configMapMount.MountPath,
+		}
+		volumeMounts = append(volumeMounts, volumeMount)
+	}
+	return volumes, volumeMounts
+}
+
+func (d *Deployment) deploymentServiceAccountTokenVolumes() (volumes []corev1.Volume, volumeMounts []corev1.VolumeMount) {
+	volumes = []corev1.Volume{}
+	volumeMounts = []corev1.VolumeMount{}
+	for i, tokenMount := range d.function.Spec.ServiceAccountTokenMounts {
+		volumeName := fmt.Sprintf("%ssa-token-%d", GeneratedVolumeNamePrefix, i)
+
+		tokenPath := tokenMount.Path
+		if tokenPath == "" {
+			tokenPath = defaultServiceAccountTokenPath
+		}
+
+		volume := corev1.Volume{
+			Name: volumeName,
+			VolumeSource: corev1.VolumeSource{
+				Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+								Audience:          tokenMount.Audience,
+								ExpirationSeconds: getValueOrDefault(tokenMount.ExpirationSeconds, ptr.To(defaultServiceAccountTokenExpirationSeconds)),
+								Path:              tokenPath,
+							},
+						},
+					},
+					DefaultMode: ptr.To(corev1.ProjectedVolumeSourceDefaultMode),
+				},
+			},
+		}
+		volumes = append(volumes, volume)
+
+		volumeMount := corev1.VolumeMount{
+			Name:      volumeName,
+			ReadOnly:  true,
+			MountPath: tokenMount.MountPath,
+		}
+		volumeMounts = append(volumeMounts, volumeMount)
+	}
+	return volumes, volumeMounts
+}
@@ -1,6 +1,7 @@
 package resources
 
 import (
+	"slices"
 	"testing"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
@@ -11,6 +12,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	k8sresource "k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 )
 
@@ -79,6 +81,121 @@ func TestDeployment_construct(t *testing.T) {
 		require.NotNil(t, r)
 		require.Equal(t, int32(78), *r.Spec.Replicas)
 	})
+	t.Run("use scheduling settings from function", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.NodeSelector = map[string]string{"pool": "spot"}
+		f.Spec.Tolerations = []corev1.Toleration{{
+			Key:      "spot",
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoSchedule,
+		}}
+		f.Spec.Affinity = &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      "gpu",
+							Operator: corev1.NodeSelectorOpDoesNotExist,
+						}},
+					}},
+				},
+			},
+		}
+		f.Spec.PriorityClassName = "low-priority"
+		f.Spec.RuntimeClassName = ptr.To("gvisor")
+		d := minimalDeploymentForFunction(f)
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		podSpec := r.Spec.Template.Spec
+		require.Equal(t, f.Spec.NodeSelector, podSpec.NodeSelector)
+		require.Equal(t, f.Spec.Tolerations, podSpec.Tolerations)
+		require.Equal(t, f.Spec.Affinity, podSpec.Affinity)
+		require.Equal(t, "low-priority", podSpec.PriorityClassName)
+		require.Equal(t, ptr.To("gvisor"), podSpec.RuntimeClassName)
+	})
+	t.Run("use service account settings from function", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.ServiceAccountName = "tender-tesla-sa"
+		f.Spec.AutomountServiceAccountToken = ptr.To(false)
+		d := minimalDeploymentForFunction(f)
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Equal(t, "tender-tesla-sa", r.Spec.Template.Spec.ServiceAccountName)
+		require.Equal(t, ptr.To(false), r.Spec.Template.Spec.AutomountServiceAccountToken)
+	})
+	t.Run("use dedicated service account", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.ServiceAccount = &serverlessv1alpha2.FunctionServiceAccount{}
+		d := minimalDeploymentForFunction(f)
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Equal(t, f.Name, r.Spec.Template.Spec.ServiceAccountName)
+	})
+	t.Run("use default probes", func(t *testing.T) {
+		d := minimalDeployment()
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		c := r.Spec.Template.Spec.Containers[0]
+		require.Equal(t, int32(5), c.StartupProbe.PeriodSeconds)
+		require.Equal(t, int32(30), c.StartupProbe.FailureThreshold)
+		require.Equal(t, int32(2), c.ReadinessProbe.TimeoutSeconds)
+		require.Equal(t, int32(4), c.LivenessProbe.TimeoutSeconds)
+		require.Equal(t, "/healthz", c.LivenessProbe.HTTPGet.Path)
+		require.Nil(t, c.Lifecycle)
+		require.Nil(t, r.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	})
+	t.Run("use probes overrides and graceful shutdown settings from function", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Probes = &serverlessv1alpha2.Probes{
+			Startup: &serverlessv1alpha2.ProbeOverride{
+				FailureThreshold: ptr.To[int32](120),
+			},
+			Liveness: &serverlessv1alpha2.ProbeOverride{
+				InitialDelaySeconds: ptr.To[int32](7),
+				PeriodSeconds:       ptr.To[int32](20),
+				TimeoutSeconds:      ptr.To[int32](10),
+			},
+		}
+		f.Spec.TerminationGracePeriodSeconds = ptr.To[int64](600)
+		f.Spec.PreStop = &corev1.LifecycleHandler{
+			Sleep: &corev1.SleepAction{Seconds: 15},
+		}
+		d := minimalDeploymentForFunction(f)
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		c := r.Spec.Template.Spec.Containers[0]
+		require.Equal(t, int32(5), c.StartupProbe.PeriodSeconds)
+		require.Equal(t, int32(120), c.StartupProbe.FailureThreshold)
+		require.Equal(t, int32(2), c.ReadinessProbe.TimeoutSeconds)
+		require.Equal(t, &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path: "/healthz",
+					Port: intstr.FromInt32(8080),
+				},
+			},
+			InitialDelaySeconds: 7,
+			PeriodSeconds:       20,
+			TimeoutSeconds:      10,
+			FailureThreshold:    3,
+		}, c.LivenessProbe)
+		require.Equal(t, &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Sleep: &corev1.SleepAction{Seconds: 15},
+			},
+		}, c.Lifecycle)
+		require.Equal(t, ptr.To[int64](600), r.Spec.Template.Spec.TerminationGracePeriodSeconds)
+	})
 	t.Run("create labels based on function", func(t *testing.T) {
 		f := minimalFunction()
 		f.Spec.Labels = map[string]string{
@@ -197,10 +314,7 @@ func TestDeployment_construct(t *testing.T) {
 				"sh",
 				"-c",
 				`set -e;
-echo "" > requirements.txt;
-echo "${FUNC_HANDLER_SOURCE}" > handler.py;
 export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;
 cd ..;
 if [ -f "./kubeless.py" ]; then
   # old file location support
@@ -211,6 +325,39 @@ fi`,
 			},
 			r.Spec.Template.Spec.Containers[0].Command)
 	})
+	t.Run("create install init container based on function", func(t *testing.T) {
+		d := minimalDeployment()
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Len(t, r.Spec.Template.Spec.InitContainers, 1)
+		c := r.Spec.Template.Spec.InitContainers[0]
+		require.Equal(t, "install", c.Name)
+		require.Equal(t, r.Spec.Template.Spec.Containers[0].Image, c.Image)
+		require.Equal(t, "/kubeless", c.WorkingDir)
+		require.Equal(t,
+			[]string{
+				"sh",
+				"-c",
+				`set -e;
+echo "" > requirements.txt;
+echo "${FUNC_HANDLER_SOURCE}" > handler.py;
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;`,
+			},
+			c.Command)
+		require.Equal(t, r.Spec.Template.Spec.Containers[0].Env, c.Env)
+		require.Equal(t, r.Spec.Template.Spec.Containers[0].VolumeMounts, c.VolumeMounts)
+		require.Equal(t, r.Spec.Template.Spec.Containers[0].SecurityContext, c.SecurityContext)
+	})
+	t.Run("skip install init container", func(t *testing.T) {
+		d := NewDeployment(minimalFunction(), minimalFunctionConfig(), nil, "", nil, "", true, DeploySkipDependenciesInstall())
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Empty(t, r.Spec.Template.Spec.InitContainers)
+	})
 	t.Run("use container resources based on function", func(t *testing.T) {
 		rc := &serverlessv1alpha2.ResourceConfiguration{
 			Function: &serverlessv1alpha2.ResourceRequirements{
@@ -262,10 +409,35 @@ fi`,
 				MountPath:  "test-mount-path",
 			},
 		}
+		d.function.Spec.ConfigMapMounts = []serverlessv1alpha2.ConfigMapMount{
+			{
+				ConfigMapName: "test-config-map-name",
+				MountPath:     "test-config-map-mount-path",
+			},
+		}
+		d.function.Spec.ServiceAccountTokenMounts = []serverlessv1alpha2.ServiceAccountTokenMount{
+			{
+				MountPath: "test-token-mount-path",
+			},
+		}
 
 		r := d.construct()
 
 		require.NotNil(t, r)
+		require.Contains(t,
+			r.Spec.Template.Spec.Containers[0].VolumeMounts,
+			corev1.VolumeMount{
+				Name:      "fn-configmap-0",
+				ReadOnly:  true,
+				MountPath: "test-config-map-mount-path",
+			})
+		require.Contains(t,
+			r.Spec.Template.Spec.Containers[0].VolumeMounts,
+			corev1.VolumeMount{
+				Name:      "fn-sa-token-0",
+				ReadOnly:  true,
+				MountPath: "test-token-mount-path",
+			})
 		require.Contains(t,
 			r.Spec.Template.Spec.Containers[0].VolumeMounts,
 			corev1.VolumeMount{
@@ -281,6 +453,114 @@ fi`,
 				MountPath: "test-mount-path",
 			})
 	})
+	t.Run("use function volumes", func(t *testing.T) {
+		d := minimalDeployment()
+		d.function.Spec.Volumes = []serverlessv1alpha2.FunctionVolume{
+			{
+				Name:      "scratch",
+				MountPath: "/scratch",
+				EmptyDir: &serverlessv1alpha2.EmptyDirVolume{
+					SizeLimit: ptr.To(k8sresource.MustParse("2Gi")),
+					InMemory:  true,
+				},
+			},
+			{
+				Name:      "data",
+				MountPath: "/data",
+				PersistentVolumeClaim: &serverlessv1alpha2.PersistentVolumeClaimVolume{
+					ClaimName: "test-claim-name",
+					ReadOnly:  true,
+				},
+			},
+		}
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Contains(t,
+			r.Spec.Template.Spec.Volumes,
+			corev1.Volume{
+				Name: "fn-volume-scratch",
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{
+						Medium:    corev1.StorageMediumMemory,
+						SizeLimit: ptr.To(k8sresource.MustParse("2Gi")),
+					},
+				},
+			})
+		require.Contains(t,
+			r.Spec.Template.Spec.Volumes,
+			corev1.Volume{
+				Name: "fn-volume-data",
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: "test-claim-name",
+						ReadOnly:  true,
+					},
+				},
+			})
+		require.Contains(t,
+			r.Spec.Template.Spec.Containers[0].VolumeMounts,
+			corev1.VolumeMount{
+				Name:      "fn-volume-scratch",
+				MountPath: "/scratch",
+			})
+		require.Contains(t,
+			r.Spec.Template.Spec.Containers[0].VolumeMounts,
+			corev1.VolumeMount{
+				Name:      "fn-volume-data",
+				ReadOnly:  true,
+				MountPath: "/data",
+			})
+	})
+	t.Run("use sidecars and init containers based on function", func(t *testing.T) {
+		d := minimalDeployment()
+		d.function.Spec.Source = serverlessv1alpha2.Source{
+			GitRepository: &serverlessv1alpha2.GitRepositorySource{
+				URL: "test-url",
+			},
+		}
+		d.function.Spec.Sidecars = []corev1.Container{
+			{
+				Name:         "test-sidecar",
+				Image:        "test-sidecar-image",
+				VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+				SecurityContext: &corev1.SecurityContext{
+					RunAsNonRoot: ptr.To(false),
+				},
+			},
+		}
+		d.function.Spec.InitContainers = []corev1.Container{
+			{
+				Name:  "test-init",
+				Image: "test-init-image",
+			},
+		}
+
+		r := d.construct()
+
+		require.NotNil(t, r)
+		require.Len(t, r.Spec.Template.Spec.Containers, 2)
+		require.Equal(t, "function", r.Spec.Template.Spec.Containers[0].Name)
+		require.Equal(t, corev1.Container{
+			Name:         "test-sidecar",
+			Image:        "test-sidecar-image",
+			VolumeMounts: []corev1.VolumeMount{{Name: "fn-volume-cache", MountPath: "/cache"}},
+			SecurityContext: &corev1.SecurityContext{
+				Privileged:               ptr.To(false),
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				ReadOnlyRootFilesystem:   ptr.To(true),
+				AllowPrivilegeEscalation: ptr.To(false),
+				RunAsNonRoot:             ptr.To(false),
+			},
+		}, r.Spec.Template.Spec.Containers[1])
+		require.Len(t, r.Spec.Template.Spec.InitContainers, 3)
+		require.Equal(t, "init", r.Spec.Template.Spec.InitContainers[0].Name)
+		require.Equal(t, "install", r.Spec.Template.Spec.InitContainers[1].Name)
+		require.Equal(t, "test-init", r.Spec.Template.Spec.InitContainers[2].Name)
+		// the function spec is not modified
+		require.Equal(t, "cache", d.function.Spec.Sidecars[0].VolumeMounts[0].Name)
+	})
 	t.Run("use volume based on function", func(t *testing.T) {
 		d := minimalDeployment()
 		d.function.Spec.SecretMounts = []serverlessv1alpha2.SecretMount{
@@ -314,13 +594,14 @@ fi`,
 				},
 			})
 	})
-	t.Run("doesn't create init container for inline function", func(t *testing.T) {
+	t.Run("doesn't create git init container for inline function", func(t *testing.T) {
 		d := minimalDeployment()
 
 		r := d.construct()
 
 		require.NotNil(t, r)
-		require.Empty(t, r.Spec.Template.Spec.InitContainers)
+		require.Len(t, r.Spec.Template.Spec.InitContainers, 1)
+		require.Equal(t, "install", r.Spec.Template.Spec.InitContainers[0].Name)
 	})
 	t.Run("create init container for git function with data based on function", func(t *testing.T) {
 		d := minimalDeployment()
@@ -335,7 +616,7 @@ fi`,
 		r := d.construct()
 
 		require.NotNil(t, r)
-		require.Len(t, r.Spec.Template.Spec.InitContainers, 1)
+		require.Len(t, r.Spec.Template.Spec.InitContainers, 2)
 		c := r.Spec.Template.Spec.InitContainers[0]
 		expectedCommand := []string{"sh", "-c",
 			`rm -rf /git-repository/*
@@ -357,7 +638,7 @@ mkdir /git-repository/src;cp -r '/git-repository/repo/recursing-mcnulty'/* /git-
 		r := d.construct()
 
 		require.NotNil(t, r)
-		require.Len(t, r.Spec.Template.Spec.InitContainers, 1)
+		require.Len(t, r.Spec.Template.Spec.InitContainers, 2)
 		c := r.Spec.Template.Spec.InitContainers[0]
 		require.Contains(t, c.Env, corev1.EnvVar{Name: "GODEBUG", Value: "fips140=only,tlsmlkem=0"})
 		require.Contains(t, c.Env, corev1.EnvVar{Name: "APP_KYMA_FIPS_MODE_ENABLED", Value: "true"})
@@ -380,7 +661,7 @@ mkdir /git-repository/src;cp -r '/git-repository/repo/recursing-mcnulty'/* /git-
 		r := d.construct()
 
 		require.NotNil(t, r)
-		require.Len(t, r.Spec.Template.Spec.InitContainers, 1)
+		require.Len(t, r.Spec.Template.Spec.InitContainers, 2)
 		c := r.Spec.Template.Spec.InitContainers[0]
 		expectedCommand := []string{"sh", "-c",
 			`rm -rf /git-repository/*
@@ -417,6 +698,100 @@ func TestDeployment_replicas(t *testing.T) {
 	})
 }
 
+func TestDeployment_topologySpreadConstraints(t *testing.T) {
+	t.Run("don't spread single replica function", func(t *testing.T) {
+		d := &Deployment{
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Replicas: ptr.To[int32](1),
+				},
+			},
+		}
+
+		r := d.topologySpreadConstraints()
+
+		assert.Nil(t, r)
+	})
+	t.Run("spread multi replica function across zones and nodes", func(t *testing.T) {
+		d := &Deployment{
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Replicas: ptr.To[int32](3),
+				},
+			},
+			selectorLabels: map[string]string{"app": "quirky-quokka"},
+		}
+
+		r := d.topologySpreadConstraints()
+
+		assert.Equal(t, []corev1.TopologySpreadConstraint{
+			{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "quirky-quokka"}},
+			},
+			{
+				MaxSkew:           1,
+				TopologyKey:       "kubernetes.io/hostname",
+				WhenUnsatisfiable: corev1.ScheduleAnyway,
+				LabelSelector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "quirky-quokka"}},
+			},
+		}, r)
+	})
+	t.Run("spread event scaled function", func(t *testing.T) {
+		d := &Deployment{
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					EventScaling: &serverlessv1alpha2.EventScaling{},
+				},
+			},
+		}
+
+		r := d.topologySpreadConstraints()
+
+		assert.Len(t, r, 2)
+	})
+	t.Run("use constraints from function", func(t *testing.T) {
+		constraints := []corev1.TopologySpreadConstraint{{
+			MaxSkew:           2,
+			TopologyKey:       "rack",
+			WhenUnsatisfiable: corev1.DoNotSchedule,
+		}}
+		d := &Deployment{
+			function: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Replicas:                  ptr.To[int32](1),
+					TopologySpreadConstraints: constraints,
+				},
+			},
+		}
+
+		r := d.topologySpreadConstraints()
+
+		assert.Equal(t, constraints, r)
+	})
+}
+
+func TestRequestBodySizeInMi(t *testing.T) {
+	tests := []struct {
+		name string
+		size string
+		want int64
+	}{
+		{name: "whole mebibytes", size: "3Mi", want: 3},
+		{name: "round up to whole mebibytes", size: "1500Ki", want: 2},
+		{name: "decimal megabytes", size: "2M", want: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := RequestBodySizeInMi(k8sresource.MustParse(tt.size))
+
+			assert.Equal(t, tt.want, r)
+		})
+	}
+}
+
 func TestDeployment_workingSourcesDir(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -1191,6 +1566,311 @@ func TestDeployment_deploymentSecretVolumes(t *testing.T) {
 	}
 }
 
+func TestDeployment_deploymentConfigMapVolumes(t *testing.T) {
+	tests := []struct {
+		name             string
+		configMapMounts  []serverlessv1alpha2.ConfigMapMount
+		wantVolumes      []corev1.Volume
+		wantVolumeMounts []corev1.VolumeMount
+	}{
+		{
+			name: "build config map volumes based on function",
+			configMapMounts: []serverlessv1alpha2.ConfigMapMount{
+				{
+					ConfigMapName: "config-map-name-1",
+					MountPath:     "mount-path-1",
+				},
+				{
+					ConfigMapName: "config-map-name-2",
+					MountPath:     "mount-path-2",
+					Items: []corev1.KeyToPath{
+						{Key: "key", Path: "path", Mode: ptr.To[int32](0400)},
+					},
+					DefaultMode: ptr.To[int32](0440),
+					Optional:    ptr.To(true),
+				},
+			},
+			wantVolumes: []corev1.Volume{
+				{
+					Name: "fn-configmap-0",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "config-map-name-1"},
+							DefaultMode:          ptr.To[int32](0644),
+							Optional:             ptr.To(false),
+						},
+					},
+				},
+				{
+					Name: "fn-configmap-1",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "config-map-name-2"},
+							Items: []corev1.KeyToPath{
+								{Key: "key", Path: "path", Mode: ptr.To[int32](0400)},
+							},
+							DefaultMode: ptr.To[int32](0440),
+							Optional:    ptr.To(true),
+						},
+					},
+				},
+			},
+			wantVolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "fn-configmap-0",
+					ReadOnly:  true,
+					MountPath: "mount-path-1",
+				},
+				{
+					Name:      "fn-configmap-1",
+					ReadOnly:  true,
+					MountPath: "mount-path-2",
+				},
+			},
+		},
+		{
+			name:             "build empty config map volumes based on function",
+			configMapMounts:  []serverlessv1alpha2.ConfigMapMount{},
+			wantVolumes:      []corev1.Volume{},
+			wantVolumeMounts: []corev1.VolumeMount{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Deployment{
+				function: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						ConfigMapMounts: tt.configMapMounts,
+					},
+				},
+			}
+			rV, rVM := d.deploymentConfigMapVolumes()
+			assert.Equal(t, tt.wantVolumes, rV)
+			assert.Equal(t, tt.wantVolumeMounts, rVM)
+		})
+	}
+}
+
+func TestDeployment_deploymentServiceAccountTokenVolumes(t *testing.T) {
+	tests := []struct {
+		name             string
+		tokenMounts      []serverlessv1alpha2.ServiceAccountTokenMount
+		wantVolumes      []corev1.Volume
+		wantVolumeMounts []corev1.VolumeMount
+	}{
+		{
+			name: "build token volumes based on function",
+			tokenMounts: []serverlessv1alpha2.ServiceAccountTokenMount{
+				{
+					MountPath: "mount-path-1",
+				},
+				{
+					MountPath:         "mount-path-2",
+					Audience:          "vault",
+					ExpirationSeconds: ptr.To[int64](600),
+					Path:              "vault-token",
+				},
+			},
+			wantVolumes: []corev1.Volume{
+				{
+					Name: "fn-sa-token-0",
+					VolumeSource: corev1.VolumeSource{
+						Projected: &corev1.ProjectedVolumeSource{
+							Sources: []corev1.VolumeProjection{{
+								ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+									ExpirationSeconds: ptr.To[int64](3600),
+									Path:              "token",
+								},
+							}},
+							DefaultMode: ptr.To[int32](0644),
+						},
+					},
+				},
+				{
+					Name: "fn-sa-token-1",
+					VolumeSource: corev1.VolumeSource{
+						Projected: &corev1.ProjectedVolumeSource{
+							Sources: []corev1.VolumeProjection{{
+								ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+									Audience:          "vault",
+									ExpirationSeconds: ptr.To[int64](600),
+									Path:              "vault-token",
+								},
+							}},
+							DefaultMode: ptr.To[int32](0644),
+						},
+					},
+				},
+			},
+			wantVolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "fn-sa-token-0",
+					ReadOnly:  true,
+					MountPath: "mount-path-1",
+				},
+				{
+					Name:      "fn-sa-token-1",
+					ReadOnly:  true,
+					MountPath: "mount-path-2",
+				},
+			},
+		},
+		{
+			name:             "build empty token volumes based on function",
+			tokenMounts:      []serverlessv1alpha2.ServiceAccountTokenMount{},
+			wantVolumes:      []corev1.Volume{},
+			wantVolumeMounts: []corev1.VolumeMount{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &Deployment{
+				function: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						ServiceAccountTokenMounts: tt.tokenMounts,
+					},
+				},
+			}
+			rV, rVM := d.deploymentServiceAccountTokenVolumes()
+			assert.Equal(t, tt.wantVolumes, rV)
+			assert.Equal(t, tt.wantVolumeMounts, rVM)
+		})
+	}
+}
+
+func Test_mergeEnvs(t *testing.T) {
+	systemEnvs := []corev1.EnvVar{
+		{Name: "FUNC_NAME", Value: "function-name"},
+		{Name: "TRACE_COLLECTOR_ENDPOINT", Value: "system-endpoint"},
+		{Name: "PUBLISHER_PROXY_ADDRESS", Value: "system-address"},
+	}
+	t.Run("append user envs after system envs", func(t *testing.T) {
+		userEnvs := []corev1.EnvVar{
+			{Name: "USER_ENV", Value: "user-value"},
+		}
+
+		r := mergeEnvs(systemEnvs, userEnvs)
+
+		require.Equal(t, []corev1.EnvVar{
+			{Name: "FUNC_NAME", Value: "function-name"},
+			{Name: "TRACE_COLLECTOR_ENDPOINT", Value: "system-endpoint"},
+			{Name: "PUBLISHER_PROXY_ADDRESS", Value: "system-address"},
+			{Name: "USER_ENV", Value: "user-value"},
+		}, r)
+	})
+	t.Run("replace overridable system envs", func(t *testing.T) {
+		userEnvs := []corev1.EnvVar{
+			{Name: "COLLECTOR_HOST", Value: "collector"},
+			{Name: "TRACE_COLLECTOR_ENDPOINT", Value: "http://$(COLLECTOR_HOST):4318"},
+		}
+
+		r := mergeEnvs(systemEnvs, userEnvs)
+
+		require.Equal(t, []corev1.EnvVar{
+			{Name: "FUNC_NAME", Value: "function-name"},
+			{Name: "PUBLISHER_PROXY_ADDRESS", Value: "system-address"},
+			{Name: "COLLECTOR_HOST", Value: "collector"},
+			{Name: "TRACE_COLLECTOR_ENDPOINT", Value: "http://$(COLLECTOR_HOST):4318"},
+		}, r)
+	})
+	t.Run("replace non overridable system envs", func(t *testing.T) {
+		userEnvs := []corev1.EnvVar{
+			{Name: "FUNC_NAME", Value: "user-name"},
+		}
+
+		r := mergeEnvs(systemEnvs, userEnvs)
+
+		require.Equal(t, []corev1.EnvVar{
+			{Name: "TRACE_COLLECTOR_ENDPOINT", Value: "system-endpoint"},
+			{Name: "PUBLISHER_PROXY_ADDRESS", Value: "system-address"},
+			{Name: "FUNC_NAME", Value: "user-name"},
+		}, r)
+	})
+}
+
+func TestDeprecatedEnvOverrides(t *testing.T) {
+	t.Run("return system envs which are not overridable", func(t *testing.T) {
+		f := minimalFunction()
+		f.Spec.Env = []corev1.EnvVar{
+			{Name: "SERVICE_NAMESPACE", Value: "user-namespace"},
+			{Name: "PUBLISHER_PROXY_ADDRESS", Value: "user-address"},
+			{Name: "FUNC_TIMEOUT", Value: "30"},
+			{Name: "USER_ENV", Value: "user-value"},
+		}
+
+		r := DeprecatedEnvOverrides(f, &config.FunctionConfig{})
+
+		require.Equal(t, []string{"SERVICE_NAMESPACE"}, r)
+	})
+	t.Run("return nothing without overrides", func(t *testing.T) {
+		f := minimalFunction()
+
+		r := DeprecatedEnvOverrides(f, &config.FunctionConfig{})
+
+		require.Empty(t, r)
+	})
+}
+
+func TestDeployment_EffectiveEnvs(t *testing.T) {
+	f := minimalFunction()
+	f.Spec.Env = []corev1.EnvVar{
+		{Name: "PUBLISHER_PROXY_ADDRESS", Value: "user-address"},
+		{Name: "USER_ENV", Value: "user-value"},
+	}
+	f.Spec.EnvFrom = []corev1.EnvFromSource{
+		{
+			Prefix:       "CONFIG_",
+			ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config-map-name"}},
+		},
+		{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "secret-name"}},
+		},
+	}
+	c := minimalFunctionConfig()
+
+	d := NewDeployment(f, c, nil, "", nil, "", false)
+	r := d.EffectiveEnvs()
+
+	require.Equal(t, []corev1.EnvFromSource{
+		{
+			Prefix:       "CONFIG_",
+			ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "config-map-name"}},
+		},
+		{
+			SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "secret-name"}},
+		},
+	}, d.Spec.Template.Spec.Containers[0].EnvFrom)
+	require.Equal(t, serverlessv1alpha2.EnvStatus{Name: "CONFIG_*", Source: serverlessv1alpha2.EnvSourceConfigMap, Reference: "config-map-name"}, r[0])
+	require.Equal(t, serverlessv1alpha2.EnvStatus{Name: "*", Source: serverlessv1alpha2.EnvSourceSecret, Reference: "secret-name"}, r[1])
+	require.Contains(t, r, serverlessv1alpha2.EnvStatus{Name: "FUNC_NAME", Source: serverlessv1alpha2.EnvSourceSystem})
+	require.Contains(t, r, serverlessv1alpha2.EnvStatus{Name: "TRACE_COLLECTOR_ENDPOINT", Source: serverlessv1alpha2.EnvSourceSystem})
+	require.Contains(t, r, serverlessv1alpha2.EnvStatus{Name: "PUBLISHER_PROXY_ADDRESS", Source: serverlessv1alpha2.EnvSourceOverride})
+	require.Contains(t, r, serverlessv1alpha2.EnvStatus{Name: "USER_ENV", Source: serverlessv1alpha2.EnvSourceUser})
+	require.NotContains(t, r, serverlessv1alpha2.EnvStatus{Name: "PUBLISHER_PROXY_ADDRESS", Source: serverlessv1alpha2.EnvSourceSystem})
+}
+
+func TestDeployment_EffectiveEnvsWithDeprecatedOverrides(t *testing.T) {
+	f := minimalFunction()
+	f.Spec.Env = []corev1.EnvVar{
+		{Name: "SERVICE_NAMESPACE", Value: "user-namespace"},
+		{Name: "FUNCTION_PATH", Value: "/user-kubeless"},
+	}
+	c := minimalFunctionConfig()
+
+	d := NewDeployment(f, c, nil, "", nil, "", false)
+	r := d.EffectiveEnvs()
+
+	envs := d.Spec.Template.Spec.Containers[0].Env
+	require.Equal(t, []corev1.EnvVar{{Name: "SERVICE_NAMESPACE", Value: "user-namespace"}},
+		slices.DeleteFunc(slices.Clone(envs), func(e corev1.EnvVar) bool { return e.Name != "SERVICE_NAMESPACE" }))
+	require.Equal(t, []corev1.EnvVar{{Name: "FUNCTION_PATH", Value: "/user-kubeless"}},
+		slices.DeleteFunc(slices.Clone(envs), func(e corev1.EnvVar) bool { return e.Name != "FUNCTION_PATH" }))
+	require.Contains(t, r, serverlessv1alpha2.EnvStatus{Name: "SERVICE_NAMESPACE", Source: serverlessv1alpha2.EnvSourceOverride})
+	require.NotContains(t, r, serverlessv1alpha2.EnvStatus{Name: "SERVICE_NAMESPACE", Source: serverlessv1alpha2.EnvSourceSystem})
+	require.Contains(t, r, serverlessv1alpha2.EnvStatus{Name: "FUNCTION_PATH", Source: serverlessv1alpha2.EnvSourceOverride})
+	require.NotContains(t, r, serverlessv1alpha2.EnvStatus{Name: "FUNCTION_PATH", Source: serverlessv1alpha2.EnvSourceSystem})
+}
+
 func TestDeployment_envs(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1250,6 +1930,80 @@ func TestDeployment_envs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "build envs with request limits based on inline python312 function",
+			function: &serverlessv1alpha2.Function{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "function-name",
+					Namespace: "function-namespace",
+				},
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.Python312,
+					Source: serverlessv1alpha2.Source{
+						Inline: &serverlessv1alpha2.InlineSource{
+							Source:       "function-source-py",
+							Dependencies: "function-dependencies-py",
+						},
+					},
+					TimeoutSeconds:     ptr.To[int32](30),
+					MaxRequestBodySize: ptr.To(k8sresource.MustParse("5Mi")),
+				},
+			},
+			want: []corev1.EnvVar{
+				{
+					Name:  "FUNC_NAME",
+					Value: "function-name",
+				},
+				{
+					Name:  "FUNC_RUNTIME",
+					Value: "python312",
+				},
+				{
+					Name:  "SERVICE_NAMESPACE",
+					Value: "function-namespace",
+				},
+				{
+					Name:  "FUNC_HANDLER_SOURCE",
+					Value: "function-source-py",
+				},
+				{
+					Name:  "FUNCTION_PATH",
+					Value: "/kubeless",
+				},
+				{
+					Name:  "FUNC_HANDLER_DEPENDENCIES",
+					Value: "function-dependencies-py",
+				},
+				{
+					Name:  "TRACE_COLLECTOR_ENDPOINT",
+					Value: "test-trace-collector-endpoint",
+				},
+				{
+					Name:  "PUBLISHER_PROXY_ADDRESS",
+					Value: "test-proxy-address",
+				},
+				{
+					Name:  "PYTHONUNBUFFERED",
+					Value: "TRUE",
+				},
+				{
+					Name:  "MOD_NAME",
+					Value: "handler",
+				},
+				{
+					Name:  "FUNC_HANDLER",
+					Value: "main",
+				},
+				{
+					Name:  "FUNC_TIMEOUT",
+					Value: "30",
+				},
+				{
+					Name:  "REQ_MB_LIMIT",
+					Value: "5",
+				},
+			},
+		},
 		{
 			name: "build envs based on inline nodejs22 function",
 			function: &serverlessv1alpha2.Function{
@@ -1478,14 +2232,15 @@ func TestDeployment_envs(t *testing.T) {
 	}
 }
 
-func TestDeployment_runtimeCommand(t *testing.T) {
+func TestDeployment_installAndRuntimeCommand(t *testing.T) {
 	tests := []struct {
-		name     string
-		function *serverlessv1alpha2.Function
-		want     string
+		name        string
+		function    *serverlessv1alpha2.Function
+		wantInstall string
+		wantRuntime string
 	}{
 		{
-			name: "build runtime command for inline python312 without dependencies",
+			name: "build commands for inline python312 without dependencies",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.Python312,
@@ -1496,11 +2251,12 @@ func TestDeployment_runtimeCommand(t *testing.T) {
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "" > requirements.txt;
 echo "${FUNC_HANDLER_SOURCE}" > handler.py;
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;`,
+			wantRuntime: `set -e;
 export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;
 cd ..;
 if [ -f "./kubeless.py" ]; then
   # old file location support
@@ -1510,7 +2266,7 @@ else
 fi`,
 		},
 		{
-			name: "build runtime command for inline python312 with dependencies",
+			name: "build commands for inline python312 with dependencies",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.Python312,
@@ -1522,12 +2278,13 @@ fi`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "" > requirements.txt;
 echo "${FUNC_HANDLER_SOURCE}" > handler.py;
 echo "${FUNC_HANDLER_DEPENDENCIES}" > requirements.txt;
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;`,
+			wantRuntime: `set -e;
 export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;
 cd ..;
 if [ -f "./kubeless.py" ]; then
   # old file location support
@@ -1537,7 +2294,7 @@ else
 fi`,
 		},
 		{
-			name: "build runtime command for git python312",
+			name: "build commands for git python312",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.Python312,
@@ -1552,10 +2309,11 @@ fi`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 cp -r /git-repository/src/* .;
+PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;`,
+			wantRuntime: `set -e;
 export PYTHONPATH="/kubeless/.local:${PYTHONPATH}"
-PIP_CONFIG_FILE=package-registry-config/pip.conf pip install --target=/kubeless/.local --no-cache-dir -r requirements.txt;
 cd ..;
 if [ -f "./kubeless.py" ]; then
   # old file location support
@@ -1565,7 +2323,7 @@ else
 fi`,
 		},
 		{
-			name: "build runtime command for inline nodejs20 without dependencies",
+			name: "build commands for inline nodejs20 without dependencies",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.NodeJs20,
@@ -1576,15 +2334,16 @@ fi`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "{}" > package.json;
 echo "${FUNC_HANDLER_SOURCE}" > handler.js;
-NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;
+NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`,
+			wantRuntime: `set -e;
 cd ..;
 npm start;`,
 		},
 		{
-			name: "build runtime command for inline nodejs20 with dependencies",
+			name: "build commands for inline nodejs20 with dependencies",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.NodeJs20,
@@ -1596,16 +2355,17 @@ npm start;`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "{}" > package.json;
 echo "${FUNC_HANDLER_SOURCE}" > handler.js;
 echo "${FUNC_HANDLER_DEPENDENCIES}" > package.json;
-NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;
+NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`,
+			wantRuntime: `set -e;
 cd ..;
 npm start;`,
 		},
 		{
-			name: "build runtime command for git nodejs20",
+			name: "build commands for git nodejs20",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.NodeJs20,
@@ -1620,15 +2380,16 @@ npm start;`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "{}" > package.json;
 cp -r /git-repository/src/* .;
-NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;
+NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`,
+			wantRuntime: `set -e;
 cd ..;
 npm start;`,
 		},
 		{
-			name: "build runtime command for inline nodejs22 without dependencies",
+			name: "build commands for inline nodejs22 without dependencies",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.NodeJs22,
@@ -1639,15 +2400,16 @@ npm start;`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "{}" > package.json;
 echo "${FUNC_HANDLER_SOURCE}" > handler.js;
-NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;
+NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`,
+			wantRuntime: `set -e;
 cd ..;
 npm start;`,
 		},
 		{
-			name: "build runtime command for inline nodejs22 with dependencies",
+			name: "build commands for inline nodejs22 with dependencies",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.NodeJs22,
@@ -1659,16 +2421,17 @@ npm start;`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "{}" > package.json;
 echo "${FUNC_HANDLER_SOURCE}" > handler.js;
 echo "${FUNC_HANDLER_DEPENDENCIES}" > package.json;
-NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;
+NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`,
+			wantRuntime: `set -e;
 cd ..;
 npm start;`,
 		},
 		{
-			name: "build runtime command for git nodejs22",
+			name: "build commands for git nodejs22",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.NodeJs22,
@@ -1683,15 +2446,16 @@ npm start;`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "{}" > package.json;
 cp -r /git-repository/src/* .;
-NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;
+NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`,
+			wantRuntime: `set -e;
 cd ..;
 npm start;`,
 		},
 		{
-			name: "build runtime command for inline nodejs24 without dependencies",
+			name: "build commands for inline nodejs24 without dependencies",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.NodeJs24,
@@ -1702,15 +2466,16 @@ npm start;`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "{}" > package.json;
 echo "${FUNC_HANDLER_SOURCE}" > handler.js;
-NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;
+NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`,
+			wantRuntime: `set -e;
 cd ..;
 npm start;`,
 		},
 		{
-			name: "build runtime command for inline nodejs24 with dependencies",
+			name: "build commands for inline nodejs24 with dependencies",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.NodeJs24,
@@ -1722,16 +2487,17 @@ npm start;`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "{}" > package.json;
 echo "${FUNC_HANDLER_SOURCE}" > handler.js;
 echo "${FUNC_HANDLER_DEPENDENCIES}" > package.json;
-NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;
+NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`,
+			wantRuntime: `set -e;
 cd ..;
 npm start;`,
 		},
 		{
-			name: "build runtime command for git nodejs24",
+			name: "build commands for git nodejs24",
 			function: &serverlessv1alpha2.Function{
 				Spec: serverlessv1alpha2.FunctionSpec{
 					Runtime: serverlessv1alpha2.NodeJs24,
@@ -1746,19 +2512,22 @@ npm start;`,
 					},
 				},
 			},
-			want: `set -e;
+			wantInstall: `set -e;
 echo "{}" > package.json;
 cp -r /git-repository/src/* .;
-NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;
+NPM_CONFIG_USERCONFIG=package-registry-config/.npmrc npm install --prefer-offline --no-audit --progress=false;`,
+			wantRuntime: `set -e;
 cd ..;
 npm start;`,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			r := runtimeCommand(tt.function)
+			install := installCommand(tt.function)
+			runtime := runtimeCommand(tt.function)
 
-			assert.Equal(t, tt.want, r)
+			assert.Equal(t, tt.wantInstall, install)
+			assert.Equal(t, tt.wantRuntime, runtime)
 		})
 	}
 }
@@ -0,0 +1,224 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	defaultExposePath = "/"
+)
+
+var (
+	// APIRuleGVK is the Kyma API Gateway APIRule kind. API Gateway is an optional dependency,
+	// so the object is handled as unstructured instead of importing its API.
+	APIRuleGVK = schema.GroupVersionKind{
+		Group:   "gateway.kyma-project.io",
+		Version: "v2",
+		Kind:    "APIRule",
+	}
+
+	// HTTPRouteGVK is the Gateway API HTTPRoute kind. Gateway API is an optional dependency,
+	// so the object is handled as unstructured instead of importing its API.
+	HTTPRouteGVK = schema.GroupVersionKind{
+		Group:   "gateway.networking.k8s.io",
+		Version: "v1",
+		Kind:    "HTTPRoute",
+	}
+
+	defaultAPIRuleMethods = []interface{}{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"}
+)
+
+type Expose struct {
+	*unstructured.Unstructured
+	function       *serverlessv1alpha2.Function
+	functionConfig *config.FunctionConfig
+}
+
+// NewAPIRule builds the APIRule exposing the Function
+func NewAPIRule(f *serverlessv1alpha2.Function, c *config.FunctionConfig) *Expose {
+	e := &Expose{
+		function:       f,
+		functionConfig: c,
+	}
+
+	e.Unstructured = e.construct(APIRuleGVK, e.apiRuleSpec())
+	return e
+}
+
+// NewHTTPRoute builds the HTTPRoute exposing the Function
+func NewHTTPRoute(f *serverlessv1alpha2.Function, c *config.FunctionConfig) *Expose {
+	e := &Expose{
+		function:       f,
+		functionConfig: c,
+	}
+
+	e.Unstructured = e.construct(HTTPRouteGVK, e.httpRouteSpec())
+	return e
+}
+
+// ExposeEnabled returns true if the Function should be exposed outside the cluster.
+func ExposeEnabled(f *serverlessv1alpha2.Function) bool {
+	return f.Spec.Expose != nil
+}
+
+// ExposeHost returns the host the Function is exposed on, completed with the configured domain.
+func ExposeHost(f *serverlessv1alpha2.Function, c *config.FunctionConfig) string {
+	host := f.Spec.Expose.Host
+	if !strings.Contains(host, ".") && c.ExposeDomain != "" {
+		return fmt.Sprintf("%s.%s", host, c.ExposeDomain)
+	}
+	return host
+}
+
+// ExposeURL returns the public URL of the exposed Function.
+func ExposeURL(f *serverlessv1alpha2.Function, c *config.FunctionConfig) string {
+	return fmt.Sprintf("https://%s%s", ExposeHost(f, c), strings.TrimSuffix(exposePath(f), "/"))
+}
+
+// ExposeGateway returns the namespace and name of the Istio gateway used in the Function's APIRule.
+func ExposeGateway(f *serverlessv1alpha2.Function, c *config.FunctionConfig) (string, string) {
+	return exposeGateway(f, c.ExposeGateway)
+}
+
+// ExposeHTTPRouteGateway returns the namespace and name of the Gateway API gateway used in the Function's HTTPRoute.
+// The name is empty when neither the Function nor the Serverless configuration sets the gateway.
+func ExposeHTTPRouteGateway(f *serverlessv1alpha2.Function, c *config.FunctionConfig) (string, string) {
+	return exposeGateway(f, c.ExposeHTTPRouteGateway)
+}
+
+func exposeGateway(f *serverlessv1alpha2.Function, defaultGateway string) (string, string) {
+	gateway := f.Spec.Expose.Gateway
+	if gateway == "" {
+		gateway = defaultGateway
+	}
+	if gateway == "" {
+		return "", ""
+	}
+	namespace, name, found := strings.Cut(gateway, "/")
+	if !found {
+		return f.GetNamespace(), gateway
+	}
+	return namespace, name
+}
+
+func (e *Expose) construct(gvk schema.GroupVersionKind, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(e.function.GetName())
+	obj.SetNamespace(e.function.GetNamespace())
+	obj.SetLabels(e.function.FunctionLabels())
+
+	return obj
+}
+
+func (e *Expose) apiRuleSpec() map[string]interface{} {
+	expose := e.function.Spec.Expose
+	gatewayNamespace, gatewayName := ExposeGateway(e.function, e.functionConfig)
+
+	rule := map[string]interface{}{
+		"path":    apiRulePath(exposePath(e.function)),
+		"methods": defaultAPIRuleMethods,
+	}
+	if len(expose.Methods) > 0 {
+		rule["methods"] = toInterfaceSlice(expose.Methods)
+	}
+	if expose.Auth != nil && expose.Auth.Strategy == serverlessv1alpha2.ExposeAuthJWT && expose.Auth.JWT != nil {
+		rule["jwt"] = map[string]interface{}{
+			"authentications": []interface{}{
+				map[string]interface{}{
+					"issuer":  expose.Auth.JWT.Issuer,
+					"jwksUri": expose.Auth.JWT.JwksUri,
+				},
+			},
+		}
+	} else {
+		rule["noAuth"] = true
+	}
+
+	return map[string]interface{}{
+		"hosts": []interface{}{ExposeHost(e.function, e.functionConfig)},
+		"service": map[string]interface{}{
+			"name":      e.function.GetName(),
+			"namespace": e.function.GetNamespace(),
+			"port":      int64(FunctionServicePort(e.function)),
+		},
+		"gateway": fmt.Sprintf("%s/%s", gatewayNamespace, gatewayName),
+		"rules":   []interface{}{rule},
+	}
+}
+
+func (e *Expose) httpRouteSpec() map[string]interface{} {
+	expose := e.function.Spec.Expose
+	gatewayNamespace, gatewayName := ExposeHTTPRouteGateway(e.function, e.functionConfig)
+
+	pathMatch := map[string]interface{}{
+		"type":  "PathPrefix",
+		"value": exposePath(e.function),
+	}
+	matches := []interface{}{}
+	for _, method := range expose.Methods {
+		matches = append(matches, map[string]interface{}{
+			"path":   pathMatch,
+			"method": method,
+		})
+	}
+	if len(matches) == 0 {
+		matches = append(matches, map[string]interface{}{
+			"path": pathMatch,
+		})
+	}
+
+	return map[string]interface{}{
+		"parentRefs": []interface{}{
+			map[string]interface{}{
+				"name":      gatewayName,
+				"namespace": gatewayNamespace,
+			},
+		},
+		"hostnames": []interface{}{ExposeHost(e.function, e.functionConfig)},
+		"rules": []interface{}{
+			map[string]interface{}{
+				"matches": matches,
+				"backendRefs": []interface{}{
+					map[string]interface{}{
+						"name": e.function.GetName(),
+						"port": int64(FunctionServicePort(e.function)),
+					},
+				},
+			},
+		},
+	}
+}
+
+func exposePath(f *serverlessv1alpha2.Function) string {
+	if f.Spec.Expose.Path == "" {
+		return defaultExposePath
+	}
+	return f.Spec.Expose.Path
+}
+
+// apiRulePath converts the path prefix to the APIRule path matching all subpaths
+func apiRulePath(path string) string {
+	if path == defaultExposePath {
+		return "/*"
+	}
+	return fmt.Sprintf("%s/{**}", strings.TrimSuffix(path, "/"))
+}
+
+func toInterfaceSlice(values []string) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		result = append(result, v)
+	}
+	return result
+}
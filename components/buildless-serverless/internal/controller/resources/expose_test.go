@@ -0,0 +1,218 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNewAPIRule(t *testing.T) {
+	c := &config.FunctionConfig{
+		ExposeGateway: "kyma-system/kyma-gateway",
+		ExposeDomain:  "example.com",
+	}
+	t.Run("create proper api rule", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Expose: &serverlessv1alpha2.FunctionExpose{
+					Host: "test-host",
+				},
+			},
+		}
+		expectedAPIRule := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "gateway.kyma-project.io/v2",
+			"kind":       "APIRule",
+			"metadata": map[string]interface{}{
+				"name":      "test-function-name",
+				"namespace": "test-function-namespace",
+				"labels": map[string]interface{}{
+					"serverless.kyma-project.io/function-name": "test-function-name",
+					"serverless.kyma-project.io/managed-by":    "function-controller",
+					"serverless.kyma-project.io/uuid":          "test-uid",
+				},
+			},
+			"spec": map[string]interface{}{
+				"hosts": []interface{}{"test-host.example.com"},
+				"service": map[string]interface{}{
+					"name":      "test-function-name",
+					"namespace": "test-function-namespace",
+					"port":      int64(80),
+				},
+				"gateway": "kyma-system/kyma-gateway",
+				"rules": []interface{}{
+					map[string]interface{}{
+						"path":    "/*",
+						"methods": []interface{}{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE"},
+						"noAuth":  true,
+					},
+				},
+			},
+		}}
+
+		r := NewAPIRule(f, c)
+
+		require.NotNil(t, r)
+		require.Equal(t, expectedAPIRule, r.Unstructured)
+	})
+	t.Run("create api rule with jwt authentication", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Expose: &serverlessv1alpha2.FunctionExpose{
+					Host:    "test-host.custom.com",
+					Path:    "/api/",
+					Methods: []string{"GET"},
+					Gateway: "test-gateway",
+					Auth: &serverlessv1alpha2.ExposeAuth{
+						Strategy: serverlessv1alpha2.ExposeAuthJWT,
+						JWT: &serverlessv1alpha2.ExposeJWT{
+							Issuer:  "https://issuer.example.com",
+							JwksUri: "https://issuer.example.com/jwks",
+						},
+					},
+				},
+			},
+		}
+
+		r := NewAPIRule(f, c)
+
+		require.Equal(t, []interface{}{"test-host.custom.com"}, r.Object["spec"].(map[string]interface{})["hosts"])
+		require.Equal(t, "test-function-namespace/test-gateway", r.Object["spec"].(map[string]interface{})["gateway"])
+		require.Equal(t, []interface{}{
+			map[string]interface{}{
+				"path":    "/api/{**}",
+				"methods": []interface{}{"GET"},
+				"jwt": map[string]interface{}{
+					"authentications": []interface{}{
+						map[string]interface{}{
+							"issuer":  "https://issuer.example.com",
+							"jwksUri": "https://issuer.example.com/jwks",
+						},
+					},
+				},
+			},
+		}, r.Object["spec"].(map[string]interface{})["rules"])
+	})
+}
+
+func TestNewHTTPRoute(t *testing.T) {
+	c := &config.FunctionConfig{
+		ExposeGateway:          "kyma-system/kyma-gateway",
+		ExposeHTTPRouteGateway: "gateway-system/public-gateway",
+	}
+	t.Run("create proper http route", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Expose: &serverlessv1alpha2.FunctionExpose{
+					Host:    "test-host.example.com",
+					Path:    "/api",
+					Methods: []string{"GET", "POST"},
+				},
+			},
+		}
+		expectedSpec := map[string]interface{}{
+			"parentRefs": []interface{}{
+				map[string]interface{}{
+					"name":      "public-gateway",
+					"namespace": "gateway-system",
+				},
+			},
+			"hostnames": []interface{}{"test-host.example.com"},
+			"rules": []interface{}{
+				map[string]interface{}{
+					"matches": []interface{}{
+						map[string]interface{}{
+							"path":   map[string]interface{}{"type": "PathPrefix", "value": "/api"},
+							"method": "GET",
+						},
+						map[string]interface{}{
+							"path":   map[string]interface{}{"type": "PathPrefix", "value": "/api"},
+							"method": "POST",
+						},
+					},
+					"backendRefs": []interface{}{
+						map[string]interface{}{
+							"name": "test-function-name",
+							"port": int64(80),
+						},
+					},
+				},
+			},
+		}
+
+		r := NewHTTPRoute(f, c)
+
+		require.NotNil(t, r)
+		require.Equal(t, HTTPRouteGVK, r.GroupVersionKind())
+		require.Equal(t, "test-function-name", r.GetName())
+		require.Equal(t, "test-function-namespace", r.GetNamespace())
+		require.Equal(t, expectedSpec, r.Object["spec"])
+	})
+	t.Run("route to the function port of headless service", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Expose:  &serverlessv1alpha2.FunctionExpose{Host: "test-host.example.com"},
+				Service: &serverlessv1alpha2.FunctionService{Headless: true},
+			},
+		}
+
+		r := NewHTTPRoute(f, c)
+
+		backendRefs, _, err := unstructured.NestedSlice(r.Object, "spec", "rules")
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{
+			map[string]interface{}{"name": "test-function-name", "port": int64(8080)},
+		}, backendRefs[0].(map[string]interface{})["backendRefs"])
+	})
+}
+
+func TestExposeURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		expose *serverlessv1alpha2.FunctionExpose
+		domain string
+		want   string
+	}{
+		{
+			name:   "short host with default path",
+			expose: &serverlessv1alpha2.FunctionExpose{Host: "test-host"},
+			domain: "example.com",
+			want:   "https://test-host.example.com",
+		},
+		{
+			name:   "fully qualified host with path",
+			expose: &serverlessv1alpha2.FunctionExpose{Host: "test-host.custom.com", Path: "/api/"},
+			domain: "example.com",
+			want:   "https://test-host.custom.com/api",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{Expose: tt.expose},
+			}
+			require.Equal(t, tt.want, ExposeURL(f, &config.FunctionConfig{ExposeDomain: tt.domain}))
+		})
+	}
+}
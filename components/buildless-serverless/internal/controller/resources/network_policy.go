@@ -0,0 +1,267 @@
+package resources
+
+import (
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+)
+
+var (
+	dnsPorts             = []int32{53, 8053}
+	packageRegistryPorts = []int32{80, 443}
+	gitRepositoryPorts   = []int32{22, 443}
+	istiodPorts          = []int32{15012}
+)
+
+type NetworkPolicy struct {
+	*networkingv1.NetworkPolicy
+	function       *serverlessv1alpha2.Function
+	functionConfig *config.FunctionConfig
+}
+
+func NewNetworkPolicy(f *serverlessv1alpha2.Function, c *config.FunctionConfig) *NetworkPolicy {
+	np := &NetworkPolicy{
+		function:       f,
+		functionConfig: c,
+	}
+
+	np.NetworkPolicy = np.construct()
+	return np
+}
+
+// NetworkPolicyEnabled returns true if the Function's NetworkPolicy should exist.
+func NetworkPolicyEnabled(f *serverlessv1alpha2.Function) bool {
+	return f.Spec.NetworkPolicy != nil
+}
+
+func (np *NetworkPolicy) construct() *networkingv1.NetworkPolicy {
+	networkPolicy := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "NetworkPolicy",
+			APIVersion: "networking.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      np.function.Name,
+			Namespace: np.function.Namespace,
+			Labels:    np.function.FunctionLabels(),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: np.function.SelectorLabels(),
+			},
+			PolicyTypes: []networkingv1.PolicyType{
+				networkingv1.PolicyTypeIngress,
+				networkingv1.PolicyTypeEgress,
+			},
+			Egress: np.defaultEgress(),
+		},
+	}
+
+	if policyConfig := np.function.Spec.NetworkPolicy; policyConfig != nil {
+		networkPolicy.Spec.Ingress = policyConfig.Ingress
+		networkPolicy.Spec.Egress = append(networkPolicy.Spec.Egress, policyConfig.Egress...)
+	}
+
+	if len(np.function.Spec.Schedules) > 0 {
+		// Pods of the schedules' CronJobs invoke the Function
+		networkPolicy.Spec.Ingress = append(networkPolicy.Spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From: []networkingv1.NetworkPolicyPeer{
+				{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: ScheduleLabels(np.function),
+					},
+				},
+			},
+		})
+	}
+
+	if len(np.function.Spec.Events) > 0 {
+		if rule := np.eventingIngressRule(); rule != nil {
+			networkPolicy.Spec.Ingress = append(networkPolicy.Spec.Ingress, *rule)
+		}
+	}
+
+	if ExposeEnabled(np.function) {
+		// gateways created for the Gateway API run in the gateway's namespace, the Istio ingress gateway in the mesh namespace
+		gatewayNamespace, _ := ExposeGateway(np.function, np.functionConfig)
+		httpRouteGatewayNamespace, _ := ExposeHTTPRouteGateway(np.function, np.functionConfig)
+		if rule := namespacesIngressRule(gatewayNamespace, httpRouteGatewayNamespace, np.functionConfig.MeshNamespace); rule != nil {
+			networkPolicy.Spec.Ingress = append(networkPolicy.Spec.Ingress, *rule)
+		}
+	}
+
+	// the controller's internal endpoint invokes the Function on behalf of users
+	if rule := namespacesIngressRule(np.functionConfig.ControllerNamespace); rule != nil {
+		networkPolicy.Spec.Ingress = append(networkPolicy.Spec.Ingress, *rule)
+	}
+
+	return networkPolicy
+}
+
+// eventingIngressRule allows Eventing to deliver events to the Function.
+// Eventing runs in the namespace of the publisher proxy.
+func (np *NetworkPolicy) eventingIngressRule() *networkingv1.NetworkPolicyIngressRule {
+	u, err := url.Parse(envValue(generalEnvs(np.function, np.functionConfig), "PUBLISHER_PROXY_ADDRESS"))
+	if err != nil {
+		return nil
+	}
+	namespace := serviceNamespace(u.Hostname())
+	if namespace == "" {
+		return nil
+	}
+	return &networkingv1.NetworkPolicyIngressRule{
+		From: namespacePeers(namespace),
+	}
+}
+
+// namespacesIngressRule allows traffic from all Pods of the given namespaces, empty and repeated namespaces are skipped
+func namespacesIngressRule(namespaces ...string) *networkingv1.NetworkPolicyIngressRule {
+	peers := []networkingv1.NetworkPolicyPeer{}
+	for i, namespace := range namespaces {
+		if namespace == "" || slices.Contains(namespaces[:i], namespace) {
+			continue
+		}
+		peers = append(peers, namespacePeers(namespace)...)
+	}
+	if len(peers) == 0 {
+		return nil
+	}
+	return &networkingv1.NetworkPolicyIngressRule{From: peers}
+}
+
+func (np *NetworkPolicy) defaultEgress() []networkingv1.NetworkPolicyEgressRule {
+	rules := []networkingv1.NetworkPolicyEgressRule{
+		{
+			// dns
+			Ports: append(policyPorts(corev1.ProtocolUDP, dnsPorts...), policyPorts(corev1.ProtocolTCP, dnsPorts...)...),
+		},
+		{
+			// package registry used to install the Function's dependencies
+			Ports: policyPorts(corev1.ProtocolTCP, packageRegistryPorts...),
+			To:    ipBlockPeers(np.functionConfig.PackageRegistryEgressCIDRs),
+		},
+	}
+
+	if np.function.HasGitSources() {
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			// git repository fetched by the init container
+			Ports: policyPorts(corev1.ProtocolTCP, gitRepositoryPorts...),
+		})
+	}
+
+	if meshNamespace := np.functionConfig.MeshNamespace; meshNamespace != "" {
+		rules = append(rules, networkingv1.NetworkPolicyEgressRule{
+			// istiod configuring the sidecar injected into the Function's Pods
+			Ports: policyPorts(corev1.ProtocolTCP, istiodPorts...),
+			To:    namespacePeers(meshNamespace),
+		})
+	}
+
+	envs := generalEnvs(np.function, np.functionConfig)
+	for _, envName := range []string{"TRACE_COLLECTOR_ENDPOINT", "PUBLISHER_PROXY_ADDRESS"} {
+		if rule := endpointEgressRule(envValue(envs, envName)); rule != nil {
+			rules = append(rules, *rule)
+		}
+	}
+
+	return rules
+}
+
+// endpointEgressRule allows traffic to the endpoint's port.
+// The traffic is limited to the endpoint's namespace when it points to a cluster-local Service.
+func endpointEgressRule(endpoint string) *networkingv1.NetworkPolicyEgressRule {
+	if endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Hostname() == "" {
+		return nil
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	portNumber, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		return nil
+	}
+
+	rule := &networkingv1.NetworkPolicyEgressRule{
+		Ports: policyPorts(corev1.ProtocolTCP, int32(portNumber)),
+	}
+	if namespace := serviceNamespace(u.Hostname()); namespace != "" {
+		rule.To = namespacePeers(namespace)
+	}
+	return rule
+}
+
+// serviceNamespace returns the namespace of the cluster-local Service host like <name>.<namespace>.svc.cluster.local
+func serviceNamespace(host string) string {
+	if net.ParseIP(host) != nil {
+		return ""
+	}
+	parts := strings.Split(host, ".")
+	if len(parts) < 3 || parts[2] != "svc" {
+		return ""
+	}
+	return parts[1]
+}
+
+func envValue(envs []corev1.EnvVar, name string) string {
+	for _, env := range envs {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+	return ""
+}
+
+func policyPorts(protocol corev1.Protocol, ports ...int32) []networkingv1.NetworkPolicyPort {
+	result := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+	for _, port := range ports {
+		p := intstr.FromInt32(port)
+		result = append(result, networkingv1.NetworkPolicyPort{
+			Protocol: ptr.To(protocol),
+			Port:     &p,
+		})
+	}
+	return result
+}
+
+func namespacePeers(namespace string) []networkingv1.NetworkPolicyPeer {
+	return []networkingv1.NetworkPolicyPeer{
+		{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{namespaceNameLabel: namespace},
+			},
+		},
+	}
+}
+
+func ipBlockPeers(cidrs []string) []networkingv1.NetworkPolicyPeer {
+	var peers []networkingv1.NetworkPolicyPeer
+	for _, cidr := range cidrs {
+		peers = append(peers, networkingv1.NetworkPolicyPeer{
+			IPBlock: &networkingv1.IPBlock{CIDR: cidr},
+		})
+	}
+	return peers
+}
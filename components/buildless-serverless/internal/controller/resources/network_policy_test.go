@@ -0,0 +1,267 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewNetworkPolicy(t *testing.T) {
+	c := &config.FunctionConfig{
+		FunctionTraceCollectorEndpoint: "http://telemetry-otlp-traces.kyma-system.svc.cluster.local:4318/v1/traces",
+		FunctionPublisherProxyAddress:  "http://eventing-publisher-proxy.kyma-system.svc.cluster.local/publish",
+		PackageRegistryEgressCIDRs:     []string{"0.0.0.0/0"},
+	}
+	t.Run("create proper network policy", func(t *testing.T) {
+		ingress := []networkingv1.NetworkPolicyIngressRule{{
+			From: []networkingv1.NetworkPolicyPeer{{
+				PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}},
+			}},
+		}}
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				NetworkPolicy: &serverlessv1alpha2.FunctionNetworkPolicy{
+					Ingress: ingress,
+					Egress: []networkingv1.NetworkPolicyEgressRule{{
+						To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+					}},
+				},
+			},
+		}
+		expectedNP := &networkingv1.NetworkPolicy{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "NetworkPolicy",
+				APIVersion: "networking.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				Labels: map[string]string{
+					"serverless.kyma-project.io/function-name": "test-function-name",
+					"serverless.kyma-project.io/managed-by":    "function-controller",
+					"serverless.kyma-project.io/uuid":          "test-uid",
+				},
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"serverless.kyma-project.io/function-name": "test-function-name",
+						"serverless.kyma-project.io/managed-by":    "function-controller",
+						"serverless.kyma-project.io/resource":      "deployment",
+						"serverless.kyma-project.io/uuid":          "test-uid",
+					},
+				},
+				PolicyTypes: []networkingv1.PolicyType{
+					networkingv1.PolicyTypeIngress,
+					networkingv1.PolicyTypeEgress,
+				},
+				Ingress: ingress,
+				Egress: []networkingv1.NetworkPolicyEgressRule{
+					{
+						Ports: []networkingv1.NetworkPolicyPort{
+							fixPolicyPort(corev1.ProtocolUDP, 53),
+							fixPolicyPort(corev1.ProtocolUDP, 8053),
+							fixPolicyPort(corev1.ProtocolTCP, 53),
+							fixPolicyPort(corev1.ProtocolTCP, 8053),
+						},
+					},
+					{
+						Ports: []networkingv1.NetworkPolicyPort{
+							fixPolicyPort(corev1.ProtocolTCP, 80),
+							fixPolicyPort(corev1.ProtocolTCP, 443),
+						},
+						To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "0.0.0.0/0"}}},
+					},
+					{
+						Ports: []networkingv1.NetworkPolicyPort{fixPolicyPort(corev1.ProtocolTCP, 4318)},
+						To:    fixNamespacePeers("kyma-system"),
+					},
+					{
+						Ports: []networkingv1.NetworkPolicyPort{fixPolicyPort(corev1.ProtocolTCP, 80)},
+						To:    fixNamespacePeers("kyma-system"),
+					},
+					{
+						To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+					},
+				},
+			},
+		}
+
+		r := NewNetworkPolicy(f, c)
+
+		require.NotNil(t, r)
+		require.Equal(t, expectedNP, r.NetworkPolicy)
+	})
+	t.Run("allow git repository for git function", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			Spec: serverlessv1alpha2.FunctionSpec{
+				NetworkPolicy: &serverlessv1alpha2.FunctionNetworkPolicy{},
+				Source: serverlessv1alpha2.Source{
+					GitRepository: &serverlessv1alpha2.GitRepositorySource{},
+				},
+			},
+		}
+
+		r := NewNetworkPolicy(f, &config.FunctionConfig{})
+
+		require.Contains(t, r.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{
+				fixPolicyPort(corev1.ProtocolTCP, 22),
+				fixPolicyPort(corev1.ProtocolTCP, 443),
+			},
+		})
+	})
+	t.Run("use overridden trace collector endpoint", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			Spec: serverlessv1alpha2.FunctionSpec{
+				NetworkPolicy: &serverlessv1alpha2.FunctionNetworkPolicy{},
+				Env: []corev1.EnvVar{
+					{Name: "TRACE_COLLECTOR_ENDPOINT", Value: "https://collector.example.com/v1/traces"},
+				},
+			},
+		}
+
+		r := NewNetworkPolicy(f, c)
+
+		require.Contains(t, r.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{fixPolicyPort(corev1.ProtocolTCP, 443)},
+		})
+		require.NotContains(t, r.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{fixPolicyPort(corev1.ProtocolTCP, 4318)},
+			To:    fixNamespacePeers("kyma-system"),
+		})
+	})
+	t.Run("allow ingress from schedule invokers", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-function-name",
+				UID:  "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				NetworkPolicy: &serverlessv1alpha2.FunctionNetworkPolicy{},
+				Schedules: []serverlessv1alpha2.FunctionSchedule{
+					{Name: "nightly", Schedule: "0 2 * * *"},
+				},
+			},
+		}
+
+		r := NewNetworkPolicy(f, c)
+
+		require.Equal(t, []networkingv1.NetworkPolicyIngressRule{
+			{
+				From: []networkingv1.NetworkPolicyPeer{
+					{
+						PodSelector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{
+								"serverless.kyma-project.io/function-name": "test-function-name",
+								"serverless.kyma-project.io/managed-by":    "function-controller",
+								"serverless.kyma-project.io/uuid":          "test-uid",
+								"serverless.kyma-project.io/resource":      "schedule",
+							},
+						},
+					},
+				},
+			},
+		}, r.Spec.Ingress)
+	})
+	t.Run("allow ingress from eventing", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			Spec: serverlessv1alpha2.FunctionSpec{
+				NetworkPolicy: &serverlessv1alpha2.FunctionNetworkPolicy{},
+				Events: []serverlessv1alpha2.FunctionEventSubscription{
+					{Name: "orders", Source: "commerce", Types: []string{"order.created.v1"}},
+				},
+			},
+		}
+
+		r := NewNetworkPolicy(f, &config.FunctionConfig{
+			FunctionPublisherProxyAddress: "http://eventing-publisher-proxy.kyma-system.svc.cluster.local/publish",
+		})
+
+		require.Equal(t, []networkingv1.NetworkPolicyIngressRule{
+			{From: fixNamespacePeers("kyma-system")},
+		}, r.Spec.Ingress)
+	})
+	t.Run("allow ingress from expose gateway", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			Spec: serverlessv1alpha2.FunctionSpec{
+				NetworkPolicy: &serverlessv1alpha2.FunctionNetworkPolicy{},
+				Expose:        &serverlessv1alpha2.FunctionExpose{Host: "zealous-zhukovsky"},
+			},
+		}
+
+		r := NewNetworkPolicy(f, &config.FunctionConfig{
+			ExposeGateway: "gateway-ns/gateway-name",
+			MeshNamespace: "istio-system",
+		})
+
+		require.Equal(t, []networkingv1.NetworkPolicyIngressRule{
+			{From: append(fixNamespacePeers("gateway-ns"), fixNamespacePeers("istio-system")...)},
+		}, r.Spec.Ingress)
+	})
+	t.Run("allow ingress from controller and egress to istiod", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			Spec: serverlessv1alpha2.FunctionSpec{
+				NetworkPolicy: &serverlessv1alpha2.FunctionNetworkPolicy{},
+			},
+		}
+
+		r := NewNetworkPolicy(f, &config.FunctionConfig{
+			ControllerNamespace: "kyma-system",
+			MeshNamespace:       "istio-system",
+		})
+
+		require.Equal(t, []networkingv1.NetworkPolicyIngressRule{
+			{From: fixNamespacePeers("kyma-system")},
+		}, r.Spec.Ingress)
+		require.Contains(t, r.Spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			Ports: []networkingv1.NetworkPolicyPort{fixPolicyPort(corev1.ProtocolTCP, 15012)},
+			To:    fixNamespacePeers("istio-system"),
+		})
+	})
+}
+
+func Test_serviceNamespace(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{name: "cluster local service", host: "svc-name.svc-ns.svc.cluster.local", want: "svc-ns"},
+		{name: "short service name", host: "svc-name.svc-ns.svc", want: "svc-ns"},
+		{name: "external host", host: "collector.example.com", want: ""},
+		{name: "ip address", host: "10.0.0.1", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, serviceNamespace(tt.host))
+		})
+	}
+}
+
+func fixPolicyPort(protocol corev1.Protocol, port int32) networkingv1.NetworkPolicyPort {
+	return networkingv1.NetworkPolicyPort{
+		Protocol: ptr.To(protocol),
+		Port:     ptr.To(intstr.FromInt32(port)),
+	}
+}
+
+func fixNamespacePeers(namespace string) []networkingv1.NetworkPolicyPeer {
+	return []networkingv1.NetworkPolicyPeer{{
+		NamespaceSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"kubernetes.io/metadata.name": namespace},
+		},
+	}}
+}
@@ -0,0 +1,68 @@
+package resources
+
+import (
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var (
+	defaultPDBMaxUnavailable = intstr.FromInt32(1)
+)
+
+type PodDisruptionBudget struct {
+	*policyv1.PodDisruptionBudget
+	function *serverlessv1alpha2.Function
+}
+
+func NewPodDisruptionBudget(f *serverlessv1alpha2.Function) *PodDisruptionBudget {
+	pdb := &PodDisruptionBudget{
+		function: f,
+	}
+
+	pdb.PodDisruptionBudget = pdb.construct()
+	return pdb
+}
+
+// PodDisruptionBudgetEnabled returns true if the Function's PodDisruptionBudget should exist.
+// Unless explicitly configured, the budget is created only for Functions which may run more than one replica.
+func PodDisruptionBudgetEnabled(f *serverlessv1alpha2.Function) bool {
+	pdbConfig := f.Spec.PodDisruptionBudget
+	if pdbConfig != nil && pdbConfig.Enabled != nil {
+		return *pdbConfig.Enabled
+	}
+	return f.MayRunMultipleReplicas()
+}
+
+func (pdb *PodDisruptionBudget) construct() *policyv1.PodDisruptionBudget {
+	podDisruptionBudget := &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PodDisruptionBudget",
+			APIVersion: "policy/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pdb.function.Name,
+			Namespace: pdb.function.Namespace,
+			Labels:    pdb.function.FunctionLabels(),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: pdb.function.SelectorLabels(),
+			},
+		},
+	}
+
+	pdbConfig := pdb.function.Spec.PodDisruptionBudget
+	switch {
+	case pdbConfig != nil && pdbConfig.MinAvailable != nil:
+		podDisruptionBudget.Spec.MinAvailable = pdbConfig.MinAvailable
+	case pdbConfig != nil && pdbConfig.MaxUnavailable != nil:
+		podDisruptionBudget.Spec.MaxUnavailable = pdbConfig.MaxUnavailable
+	default:
+		maxUnavailable := defaultPDBMaxUnavailable
+		podDisruptionBudget.Spec.MaxUnavailable = &maxUnavailable
+	}
+
+	return podDisruptionBudget
+}
@@ -0,0 +1,149 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewPodDisruptionBudget(t *testing.T) {
+	t.Run("create proper pod disruption budget", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Replicas: ptr.To[int32](3),
+			},
+		}
+		maxUnavailable := intstr.FromInt32(1)
+		expectedPDB := &policyv1.PodDisruptionBudget{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PodDisruptionBudget",
+				APIVersion: "policy/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				Labels: map[string]string{
+					"serverless.kyma-project.io/function-name": "test-function-name",
+					"serverless.kyma-project.io/managed-by":    "function-controller",
+					"serverless.kyma-project.io/uuid":          "test-uid",
+				},
+			},
+			Spec: policyv1.PodDisruptionBudgetSpec{
+				MaxUnavailable: &maxUnavailable,
+				Selector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{
+						"serverless.kyma-project.io/function-name": "test-function-name",
+						"serverless.kyma-project.io/managed-by":    "function-controller",
+						"serverless.kyma-project.io/resource":      "deployment",
+						"serverless.kyma-project.io/uuid":          "test-uid",
+					},
+				},
+			},
+		}
+
+		r := NewPodDisruptionBudget(f)
+
+		require.NotNil(t, r)
+		require.Equal(t, expectedPDB, r.PodDisruptionBudget)
+	})
+	t.Run("use minAvailable from function", func(t *testing.T) {
+		minAvailable := intstr.FromString("50%")
+		f := &serverlessv1alpha2.Function{
+			Spec: serverlessv1alpha2.FunctionSpec{
+				PodDisruptionBudget: &serverlessv1alpha2.PodDisruptionBudget{
+					MinAvailable: &minAvailable,
+				},
+			},
+		}
+
+		r := NewPodDisruptionBudget(f)
+
+		require.Equal(t, &minAvailable, r.Spec.MinAvailable)
+		require.Nil(t, r.Spec.MaxUnavailable)
+	})
+}
+
+func TestPodDisruptionBudgetEnabled(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want bool
+	}{
+		{
+			name: "disabled for default replicas",
+			spec: serverlessv1alpha2.FunctionSpec{},
+			want: false,
+		},
+		{
+			name: "disabled for single replica",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Replicas: ptr.To[int32](1),
+			},
+			want: false,
+		},
+		{
+			name: "enabled for multiple replicas",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Replicas: ptr.To[int32](2),
+			},
+			want: true,
+		},
+		{
+			name: "enabled for event scaling",
+			spec: serverlessv1alpha2.FunctionSpec{
+				EventScaling: &serverlessv1alpha2.EventScaling{
+					MaxReplicas: ptr.To[int32](5),
+				},
+			},
+			want: true,
+		},
+		{
+			name: "disabled for event scaling with single replica",
+			spec: serverlessv1alpha2.FunctionSpec{
+				EventScaling: &serverlessv1alpha2.EventScaling{
+					MaxReplicas: ptr.To[int32](1),
+				},
+			},
+			want: false,
+		},
+		{
+			name: "explicitly enabled for single replica",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Replicas: ptr.To[int32](1),
+				PodDisruptionBudget: &serverlessv1alpha2.PodDisruptionBudget{
+					Enabled: ptr.To(true),
+				},
+			},
+			want: true,
+		},
+		{
+			name: "explicitly disabled for multiple replicas",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Replicas: ptr.To[int32](4),
+				PodDisruptionBudget: &serverlessv1alpha2.PodDisruptionBudget{
+					Enabled: ptr.To(false),
+				},
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{Spec: tt.spec}
+
+			r := PodDisruptionBudgetEnabled(f)
+
+			require.Equal(t, tt.want, r)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	defaultNatsJetStreamAccount = "$G"
+	defaultEventLagThreshold    = int32(10)
+)
+
+// ScaledObjectGVK is the KEDA ScaledObject kind. KEDA is an optional dependency,
+// so the object is handled as unstructured instead of importing its API.
+var ScaledObjectGVK = schema.GroupVersionKind{
+	Group:   "keda.sh",
+	Version: "v1alpha1",
+	Kind:    "ScaledObject",
+}
+
+type ScaledObject struct {
+	*unstructured.Unstructured
+	function *serverlessv1alpha2.Function
+}
+
+func NewScaledObject(f *serverlessv1alpha2.Function) *ScaledObject {
+	so := &ScaledObject{
+		function: f,
+	}
+
+	so.Unstructured = so.construct()
+	return so
+}
+
+func (so *ScaledObject) construct() *unstructured.Unstructured {
+	eventScaling := so.function.Spec.EventScaling
+
+	spec := map[string]interface{}{
+		"scaleTargetRef": map[string]interface{}{
+			"apiVersion": serverlessv1alpha2.GroupVersion.String(),
+			"kind":       "Function",
+			"name":       so.function.GetName(),
+		},
+		"triggers": []interface{}{so.trigger()},
+	}
+	if eventScaling.MinReplicas != nil {
+		spec["minReplicaCount"] = int64(*eventScaling.MinReplicas)
+	}
+	if eventScaling.MaxReplicas != nil {
+		spec["maxReplicaCount"] = int64(*eventScaling.MaxReplicas)
+	}
+	if eventScaling.CooldownPeriod != nil {
+		spec["cooldownPeriod"] = int64(*eventScaling.CooldownPeriod)
+	}
+
+	scaledObject := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": spec,
+		},
+	}
+	scaledObject.SetGroupVersionKind(ScaledObjectGVK)
+	scaledObject.SetName(so.function.GetName())
+	scaledObject.SetNamespace(so.function.GetNamespace())
+	scaledObject.SetLabels(so.function.FunctionLabels())
+
+	return scaledObject
+}
+
+func (so *ScaledObject) trigger() map[string]interface{} {
+	eventScaling := so.function.Spec.EventScaling
+
+	var trigger map[string]interface{}
+	if eventScaling.NatsJetStream != nil {
+		trigger = natsJetStreamTrigger(eventScaling.NatsJetStream)
+	} else if eventScaling.Kafka != nil {
+		trigger = kafkaTrigger(eventScaling.Kafka)
+	}
+
+	if eventScaling.AuthenticationRef != "" {
+		trigger["authenticationRef"] = map[string]interface{}{
+			"name": eventScaling.AuthenticationRef,
+		}
+	}
+	return trigger
+}
+
+func natsJetStreamTrigger(source *serverlessv1alpha2.NatsJetStreamEventSource) map[string]interface{} {
+	account := source.Account
+	if account == "" {
+		account = defaultNatsJetStreamAccount
+	}
+
+	return map[string]interface{}{
+		"type": "nats-jetstream",
+		"metadata": map[string]interface{}{
+			"natsServerMonitoringEndpoint": source.MonitoringEndpoint,
+			"account":                      account,
+			"stream":                       source.Stream,
+			"consumer":                     source.Consumer,
+			"lagThreshold":                 lagThreshold(source.LagThreshold),
+		},
+	}
+}
+
+func kafkaTrigger(source *serverlessv1alpha2.KafkaEventSource) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "kafka",
+		"metadata": map[string]interface{}{
+			"bootstrapServers": strings.Join(source.BootstrapServers, ","),
+			"consumerGroup":    source.ConsumerGroup,
+			"topic":            source.Topic,
+			"lagThreshold":     lagThreshold(source.LagThreshold),
+		},
+	}
+}
+
+// KEDA expects all trigger metadata values as strings
+func lagThreshold(threshold *int32) string {
+	if threshold == nil {
+		return fmt.Sprint(defaultEventLagThreshold)
+	}
+	return fmt.Sprint(*threshold)
+}
@@ -0,0 +1,111 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestNewScaledObject(t *testing.T) {
+	t.Run("create proper scaled object for nats jetstream", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				EventScaling: &serverlessv1alpha2.EventScaling{
+					MinReplicas:       ptr.To[int32](0),
+					MaxReplicas:       ptr.To[int32](5),
+					AuthenticationRef: "test-auth",
+					NatsJetStream: &serverlessv1alpha2.NatsJetStreamEventSource{
+						MonitoringEndpoint: "nats.kyma-system.svc:8222",
+						Stream:             "sap",
+						Consumer:           "test-consumer",
+					},
+				},
+			},
+		}
+
+		so := NewScaledObject(f)
+
+		require.NotNil(t, so)
+		require.Equal(t, ScaledObjectGVK, so.GroupVersionKind())
+		require.Equal(t, "test-function-name", so.GetName())
+		require.Equal(t, "test-function-namespace", so.GetNamespace())
+		require.Equal(t, map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}, so.GetLabels())
+		require.Equal(t, map[string]interface{}{
+			"scaleTargetRef": map[string]interface{}{
+				"apiVersion": "serverless.kyma-project.io/v1alpha2",
+				"kind":       "Function",
+				"name":       "test-function-name",
+			},
+			"minReplicaCount": int64(0),
+			"maxReplicaCount": int64(5),
+			"triggers": []interface{}{
+				map[string]interface{}{
+					"type": "nats-jetstream",
+					"metadata": map[string]interface{}{
+						"natsServerMonitoringEndpoint": "nats.kyma-system.svc:8222",
+						"account":                      "$G",
+						"stream":                       "sap",
+						"consumer":                     "test-consumer",
+						"lagThreshold":                 "10",
+					},
+					"authenticationRef": map[string]interface{}{
+						"name": "test-auth",
+					},
+				},
+			},
+		}, so.Object["spec"])
+	})
+	t.Run("create proper scaled object for kafka", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				EventScaling: &serverlessv1alpha2.EventScaling{
+					CooldownPeriod: ptr.To[int32](60),
+					Kafka: &serverlessv1alpha2.KafkaEventSource{
+						BootstrapServers: []string{"kafka-0:9092", "kafka-1:9092"},
+						ConsumerGroup:    "test-group",
+						Topic:            "test-topic",
+						LagThreshold:     ptr.To[int32](50),
+					},
+				},
+			},
+		}
+
+		so := NewScaledObject(f)
+
+		require.Equal(t, map[string]interface{}{
+			"scaleTargetRef": map[string]interface{}{
+				"apiVersion": "serverless.kyma-project.io/v1alpha2",
+				"kind":       "Function",
+				"name":       "test-function-name",
+			},
+			"cooldownPeriod": int64(60),
+			"triggers": []interface{}{
+				map[string]interface{}{
+					"type": "kafka",
+					"metadata": map[string]interface{}{
+						"bootstrapServers": "kafka-0:9092,kafka-1:9092",
+						"consumerGroup":    "test-group",
+						"topic":            "test-topic",
+						"lagThreshold":     "50",
+					},
+				},
+			},
+		}, so.Object["spec"])
+	})
+}
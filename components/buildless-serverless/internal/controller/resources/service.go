@@ -4,11 +4,17 @@ import (
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+const (
+	ServiceHTTPPortName = "http"
+	ServiceHTTPPort     = 80
+)
+
 var (
-	svcTargetPort = intstr.FromInt32(8080)
+	svcTargetPort = intstr.FromInt32(FunctionPort)
 )
 
 type serviceOptions func(*Service)
@@ -40,12 +46,20 @@ func ServiceTrimClusterInfoLabels() serviceOptions {
 	}
 }
 
+// ServiceCurrentAnnotations - keep annotations added to the cluster Service by other components
+func ServiceCurrentAnnotations(annotations map[string]string) serviceOptions {
+	return func(s *Service) {
+		s.currentAnnotations = annotations
+	}
+}
+
 type Service struct {
 	*corev1.Service
-	function       *serverlessv1alpha2.Function
-	functionLabels map[string]string
-	selectorLabels map[string]string
-	svcName        string
+	function           *serverlessv1alpha2.Function
+	functionLabels     map[string]string
+	selectorLabels     map[string]string
+	currentAnnotations map[string]string
+	svcName            string
 }
 
 func NewService(f *serverlessv1alpha2.Function, opts ...serviceOptions) *Service {
@@ -56,6 +70,11 @@ func NewService(f *serverlessv1alpha2.Function, opts ...serviceOptions) *Service
 		svcName:        f.Name,
 	}
 
+	if svcConfig := f.Spec.Service; svcConfig != nil && svcConfig.Labels != nil {
+		// internal labels can't be overridden
+		s.functionLabels = labels.Merge(svcConfig.Labels, s.functionLabels)
+	}
+
 	for _, o := range opts {
 		o(s)
 	}
@@ -71,22 +90,87 @@ func (s *Service) construct() *corev1.Service {
 			APIVersion: "v1",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      s.svcName,
-			Namespace: s.function.Namespace,
-			Labels:    s.functionLabels,
-			//TODO: do we need to add annotations here?
-			//Annotations: s.functionAnnotations(),
+			Name:        s.svcName,
+			Namespace:   s.function.Namespace,
+			Labels:      s.functionLabels,
+			Annotations: s.annotations(),
 		},
 		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{{
-				Name:       "http", // it has to be here for istio to work properly
-				TargetPort: svcTargetPort,
-				Port:       80,
-				Protocol:   corev1.ProtocolTCP,
-			}},
+			Ports:    s.ports(),
 			Selector: s.selectorLabels,
 		},
 	}
 
+	if svcConfig := s.function.Spec.Service; svcConfig != nil {
+		service.Spec.Type = svcConfig.Type
+		service.Spec.SessionAffinity = svcConfig.SessionAffinity
+		if svcConfig.Headless {
+			service.Spec.ClusterIP = corev1.ClusterIPNone
+		}
+	}
+
 	return service
 }
+
+// FunctionServicePort returns the port on which the Function's Service serves the Function.
+// A headless Service resolves to Pod IPs, so it uses the Function port instead of ServiceHTTPPort.
+func FunctionServicePort(f *serverlessv1alpha2.Function) int32 {
+	if f.Spec.Service != nil && f.Spec.Service.Headless {
+		return FunctionPort
+	}
+	return ServiceHTTPPort
+}
+
+func (s *Service) ports() []corev1.ServicePort {
+	ports := []corev1.ServicePort{{
+		Name:       ServiceHTTPPortName, // it has to be here for istio to work properly
+		TargetPort: svcTargetPort,
+		Port:       FunctionServicePort(s.function),
+		Protocol:   corev1.ProtocolTCP,
+	}}
+
+	if s.function.Spec.Service == nil {
+		return ports
+	}
+	for _, port := range s.function.Spec.Service.Ports {
+		targetPort := intstr.FromInt32(port.Port)
+		if port.TargetPort != nil {
+			targetPort = *port.TargetPort
+		}
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		ports = append(ports, corev1.ServicePort{
+			Name:       port.Name,
+			TargetPort: targetPort,
+			Port:       port.Port,
+			Protocol:   protocol,
+		})
+	}
+	return ports
+}
+
+func (s *Service) annotations() map[string]string {
+	var result map[string]string
+	if svcConfig := s.function.Spec.Service; svcConfig != nil && len(svcConfig.Annotations) > 0 {
+		result = labels.Merge(nil, svcConfig.Annotations)
+	}
+
+	// merge with annotations added by other components (for example cloud load balancer controllers)
+	// before merge we need to remove annotations that are not present in the current function to allow removing them
+	previousAnnotations := s.function.Status.ServiceAnnotations
+	for key, value := range s.currentAnnotations {
+		if _, ok := previousAnnotations[key]; ok {
+			continue
+		}
+		if _, ok := result[key]; ok {
+			continue
+		}
+		if result == nil {
+			result = map[string]string{}
+		}
+		result[key] = value
+	}
+	return result
+}
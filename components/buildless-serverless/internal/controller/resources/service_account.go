@@ -0,0 +1,111 @@
+package resources
+
+import (
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type serviceAccountOptions func(*ServiceAccount)
+
+// ServiceAccountSetName - set the name of the service account and its role binding
+func ServiceAccountSetName(name string) serviceAccountOptions {
+	return func(sa *ServiceAccount) {
+		sa.name = name
+	}
+}
+
+// ServiceAccountTrimClusterInfoLabels - get rid of internal labels like managed-by, function-name or uuid
+func ServiceAccountTrimClusterInfoLabels() serviceAccountOptions {
+	return func(sa *ServiceAccount) {
+		for key := range sa.function.InternalFunctionLabels() {
+			delete(sa.functionLabels, key)
+		}
+	}
+}
+
+type ServiceAccount struct {
+	*corev1.ServiceAccount
+	RoleBinding    *rbacv1.RoleBinding
+	function       *serverlessv1alpha2.Function
+	functionLabels map[string]string
+	name           string
+}
+
+// NewServiceAccount builds the dedicated ServiceAccount of the Function together with its RoleBinding.
+// The RoleBinding is nil when the Function doesn't reference any role.
+func NewServiceAccount(f *serverlessv1alpha2.Function, opts ...serviceAccountOptions) *ServiceAccount {
+	sa := &ServiceAccount{
+		function:       f,
+		functionLabels: f.FunctionLabels(),
+		name:           f.Name,
+	}
+
+	for _, o := range opts {
+		o(sa)
+	}
+
+	sa.ServiceAccount = sa.construct()
+	sa.RoleBinding = sa.constructRoleBinding()
+	return sa
+}
+
+// DedicatedServiceAccountEnabled returns true if the controller should create a ServiceAccount for the Function.
+func DedicatedServiceAccountEnabled(f *serverlessv1alpha2.Function) bool {
+	return f.Spec.ServiceAccount != nil
+}
+
+// ServiceAccountName returns the name of the ServiceAccount used by the Function's Pods.
+// Empty name means the namespace's default ServiceAccount.
+func ServiceAccountName(f *serverlessv1alpha2.Function) string {
+	if DedicatedServiceAccountEnabled(f) {
+		return f.Name
+	}
+	return f.Spec.ServiceAccountName
+}
+
+func (sa *ServiceAccount) construct() *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: "v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sa.name,
+			Namespace: sa.function.Namespace,
+			Labels:    sa.functionLabels,
+		},
+	}
+}
+
+func (sa *ServiceAccount) constructRoleBinding() *rbacv1.RoleBinding {
+	saConfig := sa.function.Spec.ServiceAccount
+	if saConfig == nil || saConfig.RoleRef == nil {
+		return nil
+	}
+
+	return &rbacv1.RoleBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "RoleBinding",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sa.name,
+			Namespace: sa.function.Namespace,
+			Labels:    sa.functionLabels,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      sa.name,
+				Namespace: sa.function.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     saConfig.RoleRef.Kind,
+			Name:     saConfig.RoleRef.Name,
+		},
+	}
+}
@@ -0,0 +1,147 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewServiceAccount(t *testing.T) {
+	t.Run("create proper service account and role binding", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{
+					RoleRef: &serverlessv1alpha2.ServiceAccountRoleRef{
+						Kind: "Role",
+						Name: "test-role",
+					},
+				},
+			},
+		}
+		expectedLabels := map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+		}
+		expectedSA := &corev1.ServiceAccount{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ServiceAccount",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				Labels:    expectedLabels,
+			},
+		}
+		expectedRB := &rbacv1.RoleBinding{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "RoleBinding",
+				APIVersion: "rbac.authorization.k8s.io/v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				Labels:    expectedLabels,
+			},
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      "ServiceAccount",
+					Name:      "test-function-name",
+					Namespace: "test-function-namespace",
+				},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "Role",
+				Name:     "test-role",
+			},
+		}
+
+		r := NewServiceAccount(f)
+
+		require.NotNil(t, r)
+		require.Equal(t, expectedSA, r.ServiceAccount)
+		require.Equal(t, expectedRB, r.RoleBinding)
+	})
+	t.Run("skip role binding when role is not referenced", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			Spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{},
+			},
+		}
+
+		r := NewServiceAccount(f)
+
+		require.NotNil(t, r.ServiceAccount)
+		require.Nil(t, r.RoleBinding)
+	})
+	t.Run("use custom name and trim internal labels", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{
+					RoleRef: &serverlessv1alpha2.ServiceAccountRoleRef{
+						Kind: "ClusterRole",
+						Name: "view",
+					},
+				},
+			},
+		}
+
+		r := NewServiceAccount(f, ServiceAccountSetName("ejected-name"), ServiceAccountTrimClusterInfoLabels())
+
+		require.Equal(t, "ejected-name", r.ServiceAccount.Name)
+		require.Empty(t, r.ServiceAccount.Labels)
+		require.Equal(t, "ejected-name", r.RoleBinding.Name)
+		require.Equal(t, "ejected-name", r.RoleBinding.Subjects[0].Name)
+		require.Empty(t, r.RoleBinding.Labels)
+	})
+}
+
+func TestServiceAccountName(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want string
+	}{
+		{
+			name: "default service account",
+			spec: serverlessv1alpha2.FunctionSpec{},
+			want: "",
+		},
+		{
+			name: "existing service account",
+			spec: serverlessv1alpha2.FunctionSpec{ServiceAccountName: "existing-sa"},
+			want: "existing-sa",
+		},
+		{
+			name: "dedicated service account",
+			spec: serverlessv1alpha2.FunctionSpec{ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{}},
+			want: "test-function-name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-function-name"},
+				Spec:       tt.spec,
+			}
+
+			require.Equal(t, tt.want, ServiceAccountName(f))
+		})
+	}
+}
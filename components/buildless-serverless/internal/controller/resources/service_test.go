@@ -8,6 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
 )
 
 func TestNewService(t *testing.T) {
@@ -60,4 +61,55 @@ func TestNewService(t *testing.T) {
 		require.IsType(t, &corev1.Service{}, s)
 		require.Equal(t, expectedSvc, s)
 	})
+	t.Run("create service with exposure options", func(t *testing.T) {
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function-name",
+				Namespace: "test-function-namespace",
+				UID:       "test-uid",
+			},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Service: &serverlessv1alpha2.FunctionService{
+					Type:     corev1.ServiceTypeClusterIP,
+					Headless: true,
+					Ports: []serverlessv1alpha2.FunctionServicePort{
+						{Name: "metrics", Port: 9090},
+						{Name: "grpc", Port: 5000, TargetPort: ptr.To(intstr.FromString("grpc")), Protocol: corev1.ProtocolUDP},
+					},
+					Annotations:     map[string]string{"test-annotation": "test-value"},
+					Labels:          map[string]string{"test-label": "test-value", "serverless.kyma-project.io/uuid": "other-uid"},
+					SessionAffinity: corev1.ServiceAffinityClientIP,
+				},
+			},
+			Status: serverlessv1alpha2.FunctionStatus{
+				ServiceAnnotations: map[string]string{"previous-annotation": "test-value"},
+			},
+		}
+
+		r := NewService(f, ServiceCurrentAnnotations(map[string]string{
+			"previous-annotation": "test-value",
+			"other-annotation":    "test-value",
+			"test-annotation":     "other-value",
+		}))
+
+		s := r.Service
+		require.Equal(t, map[string]string{
+			"serverless.kyma-project.io/function-name": "test-function-name",
+			"serverless.kyma-project.io/managed-by":    "function-controller",
+			"serverless.kyma-project.io/uuid":          "test-uid",
+			"test-label":                               "test-value",
+		}, s.Labels)
+		require.Equal(t, map[string]string{
+			"test-annotation":  "test-value",
+			"other-annotation": "test-value",
+		}, s.Annotations)
+		require.Equal(t, corev1.ServiceTypeClusterIP, s.Spec.Type)
+		require.Equal(t, corev1.ClusterIPNone, s.Spec.ClusterIP)
+		require.Equal(t, corev1.ServiceAffinityClientIP, s.Spec.SessionAffinity)
+		require.Equal(t, []corev1.ServicePort{
+			{Name: "http", TargetPort: intstr.FromInt32(8080), Port: 8080, Protocol: corev1.ProtocolTCP},
+			{Name: "metrics", TargetPort: intstr.FromInt32(9090), Port: 9090, Protocol: corev1.ProtocolTCP},
+			{Name: "grpc", TargetPort: intstr.FromString("grpc"), Port: 5000, Protocol: corev1.ProtocolUDP},
+		}, s.Spec.Ports)
+	})
 }
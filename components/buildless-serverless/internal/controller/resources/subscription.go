@@ -0,0 +1,94 @@
+package resources
+
+import (
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	// SubscriptionGVK is the Kyma Eventing Subscription kind. Eventing is an optional dependency,
+	// so the object is handled as unstructured instead of importing its API.
+	SubscriptionGVK = schema.GroupVersionKind{
+		Group:   "eventing.kyma-project.io",
+		Version: "v1alpha2",
+		Kind:    "Subscription",
+	}
+)
+
+type Subscription struct {
+	*unstructured.Unstructured
+	function       *serverlessv1alpha2.Function
+	functionConfig *config.FunctionConfig
+	event          serverlessv1alpha2.FunctionEventSubscription
+}
+
+// NewSubscription builds the Subscription delivering the consumed events to the Function
+func NewSubscription(f *serverlessv1alpha2.Function, c *config.FunctionConfig, event serverlessv1alpha2.FunctionEventSubscription) *Subscription {
+	s := &Subscription{
+		function:       f,
+		functionConfig: c,
+		event:          event,
+	}
+
+	s.Unstructured = s.construct()
+	return s
+}
+
+// NewSubscriptions builds Subscriptions for all events consumed by the Function.
+func NewSubscriptions(f *serverlessv1alpha2.Function, c *config.FunctionConfig) []*unstructured.Unstructured {
+	subscriptions := make([]*unstructured.Unstructured, 0, len(f.Spec.Events))
+	for _, event := range f.Spec.Events {
+		subscriptions = append(subscriptions, NewSubscription(f, c, event).Unstructured)
+	}
+	return subscriptions
+}
+
+// SubscriptionName returns the name of the Subscription created for the consumed events.
+func SubscriptionName(f *serverlessv1alpha2.Function, eventName string) string {
+	return fmt.Sprintf("%s-%s", f.GetName(), eventName)
+}
+
+// SubscriptionLabels returns labels of Subscriptions created for the Function's events.
+func SubscriptionLabels(f *serverlessv1alpha2.Function) map[string]string {
+	return labels.Merge(f.InternalFunctionLabels(), map[string]string{
+		serverlessv1alpha2.FunctionResourceLabel: serverlessv1alpha2.FunctionResourceLabelSubscriptionValue,
+	})
+}
+
+func (s *Subscription) construct() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": s.spec(),
+		},
+	}
+	obj.SetGroupVersionKind(SubscriptionGVK)
+	obj.SetName(SubscriptionName(s.function, s.event.Name))
+	obj.SetNamespace(s.function.GetNamespace())
+	obj.SetLabels(labels.Merge(s.function.GetLabels(), SubscriptionLabels(s.function)))
+
+	return obj
+}
+
+func (s *Subscription) spec() map[string]interface{} {
+	typeMatching := s.event.TypeMatching
+	if typeMatching == "" {
+		typeMatching = serverlessv1alpha2.EventTypeMatchingStandard
+	}
+
+	return map[string]interface{}{
+		"sink":         s.sink(),
+		"source":       s.event.Source,
+		"types":        toInterfaceSlice(s.event.Types),
+		"typeMatching": string(typeMatching),
+	}
+}
+
+// sink returns the address of the Function's Service in the format required by Eventing
+func (s *Subscription) sink() string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", s.function.GetName(), s.function.GetNamespace(), FunctionServicePort(s.function))
+}
@@ -0,0 +1,76 @@
+package resources
+
+import (
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNewSubscription(t *testing.T) {
+	f := &serverlessv1alpha2.Function{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-function-name",
+			Namespace: "test-function-namespace",
+			UID:       "test-uid",
+		},
+	}
+	t.Run("create proper subscription", func(t *testing.T) {
+		event := serverlessv1alpha2.FunctionEventSubscription{
+			Name:   "orders",
+			Source: "commerce",
+			Types:  []string{"order.created.v1", "order.updated.v1"},
+		}
+		expectedSubscription := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "eventing.kyma-project.io/v1alpha2",
+			"kind":       "Subscription",
+			"metadata": map[string]interface{}{
+				"name":      "test-function-name-orders",
+				"namespace": "test-function-namespace",
+				"labels": map[string]interface{}{
+					"serverless.kyma-project.io/function-name": "test-function-name",
+					"serverless.kyma-project.io/managed-by":    "function-controller",
+					"serverless.kyma-project.io/uuid":          "test-uid",
+					"serverless.kyma-project.io/resource":      "subscription",
+				},
+			},
+			"spec": map[string]interface{}{
+				"sink":         "http://test-function-name.test-function-namespace.svc.cluster.local:80",
+				"source":       "commerce",
+				"types":        []interface{}{"order.created.v1", "order.updated.v1"},
+				"typeMatching": "standard",
+			},
+		}}
+
+		r := NewSubscription(f, &config.FunctionConfig{}, event)
+
+		require.NotNil(t, r)
+		require.Equal(t, expectedSubscription, r.Unstructured)
+	})
+	t.Run("send events to the function port of headless service", func(t *testing.T) {
+		fn := f.DeepCopy()
+		fn.Spec.Service = &serverlessv1alpha2.FunctionService{Headless: true}
+		event := serverlessv1alpha2.FunctionEventSubscription{Name: "orders", Source: "commerce", Types: []string{"order.created.v1"}}
+
+		r := NewSubscription(fn, &config.FunctionConfig{}, event)
+
+		require.Equal(t, "http://test-function-name.test-function-namespace.svc.cluster.local:8080", r.Object["spec"].(map[string]interface{})["sink"])
+	})
+	t.Run("create subscriptions for all events", func(t *testing.T) {
+		fn := f.DeepCopy()
+		fn.Spec.Events = []serverlessv1alpha2.FunctionEventSubscription{
+			{Name: "orders", Source: "commerce", Types: []string{"order.created.v1"}},
+			{Name: "payments", Source: "payment-gateway", Types: []string{"payment.received"}, TypeMatching: serverlessv1alpha2.EventTypeMatchingExact},
+		}
+
+		r := NewSubscriptions(fn, &config.FunctionConfig{})
+
+		require.Len(t, r, 2)
+		require.Equal(t, "test-function-name-orders", r[0].GetName())
+		require.Equal(t, "test-function-name-payments", r[1].GetName())
+		require.Equal(t, "exact", r[1].Object["spec"].(map[string]interface{})["typeMatching"])
+	})
+}
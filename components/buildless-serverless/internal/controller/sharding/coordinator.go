@@ -0,0 +1,339 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+const (
+	KeyNamespace = "namespace"
+	KeyLabel     = "label"
+
+	leaseGroupLabel = "serverless.kyma-project.io/shard-group"
+	leaseTypeLabel  = "serverless.kyma-project.io/shard-lease-type"
+	leaseTypeMember = "member"
+	leaseTypeShard  = "shard"
+)
+
+// Coordinator holds Leases of a disjoint slice of function shards.
+// Every replica renews its member Lease and takes shards assigned to it by the sorted list of live members,
+// so shards are rebalanced when replicas come and go. A nil Coordinator owns all functions
+type Coordinator struct {
+	client    client.Client
+	log       *zap.SugaredLogger
+	cfg       config.ShardingConfig
+	identity  string
+	events    chan event.GenericEvent
+	now       func() time.Time
+	mu        sync.RWMutex
+	renewedAt map[int]time.Time
+}
+
+func NewCoordinator(c client.Client, cfg config.ShardingConfig, identity string, log *zap.SugaredLogger) *Coordinator {
+	return &Coordinator{
+		client:    c,
+		log:       log,
+		cfg:       cfg,
+		identity:  identity,
+		events:    make(chan event.GenericEvent, 1024),
+		now:       time.Now,
+		renewedAt: map[int]time.Time{},
+	}
+}
+
+// Events returns functions of newly acquired shards which have to be reconciled by this replica
+func (c *Coordinator) Events() <-chan event.GenericEvent {
+	return c.events
+}
+
+// NeedLeaderElection returns false because every replica holds its own shards
+func (c *Coordinator) NeedLeaderElection() bool {
+	return false
+}
+
+// Start renews Leases until the context is cancelled and releases them afterwards
+func (c *Coordinator) Start(ctx context.Context) error {
+	c.log.Infof("starting shard coordinator for %d shards as %s", c.cfg.Shards, c.identity)
+	for {
+		if err := c.sync(ctx); err != nil {
+			c.log.Warnf("failed to sync shard leases: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			// release leases without waiting for their expiration so other replicas can take shards over right away
+			c.release(context.Background())
+			return nil
+		case <-time.After(c.cfg.RenewPeriod):
+		}
+	}
+}
+
+// OwnsFunction returns true if the function belongs to the shard held by this replica
+func (c *Coordinator) OwnsFunction(f *serverlessv1alpha2.Function) bool {
+	if c == nil {
+		return true
+	}
+	return c.ownsShard(c.shardOf(shardKeyValue(f, c.cfg.Key)))
+}
+
+// OwnsObject returns true if the object may concern a function from the shard held by this replica,
+// objects owned by functions can be filtered only when shards are chosen by the namespace
+func (c *Coordinator) OwnsObject(obj client.Object) bool {
+	if c == nil {
+		return true
+	}
+	if f, ok := obj.(*serverlessv1alpha2.Function); ok {
+		return c.OwnsFunction(f)
+	}
+	if c.cfg.Key != KeyNamespace {
+		return true
+	}
+	return c.ownsShard(c.shardOf(obj.GetNamespace()))
+}
+
+func shardKeyValue(f *serverlessv1alpha2.Function, key string) string {
+	if key == KeyLabel {
+		if value, ok := f.GetLabels()[serverlessv1alpha2.FunctionShardLabel]; ok {
+			return value
+		}
+	}
+	// functions without the shard label fall back to the namespace
+	return f.GetNamespace()
+}
+
+func (c *Coordinator) shardOf(value string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(value))
+	return int(h.Sum32() % uint32(c.cfg.Shards))
+}
+
+func (c *Coordinator) ownsShard(shard int) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	renewedAt, ok := c.renewedAt[shard]
+	// stop reconciling when the lease could not be renewed, another replica may have taken it over
+	return ok && c.now().Before(renewedAt.Add(c.cfg.LeaseDuration))
+}
+
+func (c *Coordinator) sync(ctx context.Context) error {
+	if err := c.renewMember(ctx); err != nil {
+		return errors.Wrap(err, "while renewing member lease")
+	}
+
+	members, err := c.liveMembers(ctx)
+	if err != nil {
+		return errors.Wrap(err, "while listing member leases")
+	}
+
+	for shard := range c.cfg.Shards {
+		if assignedMember(members, shard) != c.identity {
+			if err := c.releaseShard(ctx, shard); err != nil {
+				c.log.Warnf("failed to release shard %d: %s", shard, err)
+			}
+			continue
+		}
+
+		if err := c.acquireShard(ctx, shard); err != nil {
+			c.log.Warnf("failed to acquire shard %d: %s", shard, err)
+		}
+	}
+	return nil
+}
+
+// assignedMember spreads shards evenly over members sorted by their identities
+func assignedMember(members []string, shard int) string {
+	return members[shard%len(members)]
+}
+
+func (c *Coordinator) renewMember(ctx context.Context) error {
+	lease, err := c.getOrCreateLease(ctx, c.memberLeaseName(), leaseTypeMember)
+	if err != nil {
+		return err
+	}
+	c.hold(lease)
+	return c.client.Update(ctx, lease)
+}
+
+func (c *Coordinator) liveMembers(ctx context.Context) ([]string, error) {
+	leases := coordinationv1.LeaseList{}
+	err := c.client.List(ctx, &leases,
+		client.InNamespace(c.cfg.LeaseNamespace),
+		client.MatchingLabels{leaseGroupLabel: c.cfg.LeasePrefix, leaseTypeLabel: leaseTypeMember},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// this replica is a member even if the list doesn't include its just created lease yet
+	members := []string{c.identity}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+		if c.expired(*lease) {
+			// remove leftovers of replicas which didn't shut down gracefully
+			if err := client.IgnoreNotFound(c.client.Delete(ctx, lease)); err != nil {
+				c.log.Warnf("failed to delete expired member lease %s: %s", lease.Name, err)
+			}
+			continue
+		}
+		if holder == "" || holder == c.identity {
+			continue
+		}
+		members = append(members, holder)
+	}
+	slices.Sort(members)
+	return members, nil
+}
+
+func (c *Coordinator) acquireShard(ctx context.Context, shard int) error {
+	lease, err := c.getOrCreateLease(ctx, c.shardLeaseName(shard), leaseTypeShard)
+	if err != nil {
+		return err
+	}
+
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	if holder != "" && holder != c.identity && !c.expired(*lease) {
+		// the previous holder didn't release the shard yet
+		return nil
+	}
+
+	c.hold(lease)
+	if err := c.client.Update(ctx, lease); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	_, owned := c.renewedAt[shard]
+	c.renewedAt[shard] = lease.Spec.RenewTime.Time
+	c.mu.Unlock()
+
+	if !owned {
+		c.log.Infof("acquired shard %d", shard)
+		go c.enqueueShard(ctx, shard)
+	}
+	return nil
+}
+
+func (c *Coordinator) releaseShard(ctx context.Context, shard int) error {
+	c.mu.Lock()
+	_, owned := c.renewedAt[shard]
+	delete(c.renewedAt, shard)
+	c.mu.Unlock()
+
+	if !owned {
+		return nil
+	}
+
+	lease := &coordinationv1.Lease{}
+	err := c.client.Get(ctx, client.ObjectKey{Namespace: c.cfg.LeaseNamespace, Name: c.shardLeaseName(shard)}, lease)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != c.identity {
+		return nil
+	}
+
+	c.log.Infof("releasing shard %d", shard)
+	lease.Spec.HolderIdentity = nil
+	return c.client.Update(ctx, lease)
+}
+
+// release gives up all shards and removes the member lease
+func (c *Coordinator) release(ctx context.Context) {
+	for shard := range c.cfg.Shards {
+		if err := c.releaseShard(ctx, shard); err != nil {
+			c.log.Warnf("failed to release shard %d: %s", shard, err)
+		}
+	}
+
+	member := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: c.cfg.LeaseNamespace, Name: c.memberLeaseName()}}
+	if err := client.IgnoreNotFound(c.client.Delete(ctx, member)); err != nil {
+		c.log.Warnf("failed to delete member lease: %s", err)
+	}
+}
+
+// enqueueShard requests reconciliation of functions from the shard taken over from another replica
+func (c *Coordinator) enqueueShard(ctx context.Context, shard int) {
+	functions := serverlessv1alpha2.FunctionList{}
+	if err := c.client.List(ctx, &functions); err != nil {
+		c.log.Warnf("failed to list functions of shard %d: %s", shard, err)
+		return
+	}
+
+	for i := range functions.Items {
+		f := &functions.Items[i]
+		if c.shardOf(shardKeyValue(f, c.cfg.Key)) != shard {
+			continue
+		}
+		select {
+		case c.events <- event.GenericEvent{Object: f}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *Coordinator) getOrCreateLease(ctx context.Context, name, leaseType string) (*coordinationv1.Lease, error) {
+	lease := &coordinationv1.Lease{}
+	err := c.client.Get(ctx, client.ObjectKey{Namespace: c.cfg.LeaseNamespace, Name: name}, lease)
+	if err == nil || !k8serrors.IsNotFound(err) {
+		return lease, err
+	}
+
+	lease = &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.cfg.LeaseNamespace,
+			Labels: map[string]string{
+				leaseGroupLabel: c.cfg.LeasePrefix,
+				leaseTypeLabel:  leaseType,
+			},
+		},
+	}
+	return lease, c.client.Create(ctx, lease)
+}
+
+// hold sets this replica as the holder of the lease and renews it
+func (c *Coordinator) hold(lease *coordinationv1.Lease) {
+	now := metav1.NewMicroTime(c.now())
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != c.identity {
+		lease.Spec.HolderIdentity = ptr.To(c.identity)
+		lease.Spec.AcquireTime = &now
+		lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+	}
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(c.cfg.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+}
+
+func (c *Coordinator) expired(lease coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil {
+		return true
+	}
+	duration := time.Duration(ptr.Deref(lease.Spec.LeaseDurationSeconds, 0)) * time.Second
+	return c.now().After(lease.Spec.RenewTime.Add(duration))
+}
+
+func (c *Coordinator) memberLeaseName() string {
+	return fmt.Sprintf("%s-member-%s", c.cfg.LeasePrefix, c.identity)
+}
+
+func (c *Coordinator) shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-%d", c.cfg.LeasePrefix, shard)
+}
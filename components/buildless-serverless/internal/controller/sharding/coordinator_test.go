@@ -0,0 +1,190 @@
+package sharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCoordinator(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	cfg := config.ShardingConfig{
+		Shards:         4,
+		Key:            KeyNamespace,
+		LeaseNamespace: "kyma-system",
+		LeasePrefix:    "serverless-controller-shard",
+		LeaseDuration:  15 * time.Second,
+		RenewPeriod:    5 * time.Second,
+	}
+	fixClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		require.NoError(t, clientgoscheme.AddToScheme(scheme))
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+	fixCoordinator := func(c client.Client, identity string) *Coordinator {
+		coordinator := NewCoordinator(c, cfg, identity, zap.NewNop().Sugar())
+		coordinator.now = func() time.Time { return now }
+		return coordinator
+	}
+	ownedShards := func(c *Coordinator) []int {
+		shards := []int{}
+		for shard := range cfg.Shards {
+			if c.ownsShard(shard) {
+				shards = append(shards, shard)
+			}
+		}
+		return shards
+	}
+
+	t.Run("take all shards as the only replica", func(t *testing.T) {
+		// Arrange
+		c := fixCoordinator(fixClient(), "pensive-pascal")
+
+		// Act
+		err := c.sync(context.Background())
+
+		// Assert
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 2, 3}, ownedShards(c))
+	})
+	t.Run("rebalance shards when replica joins", func(t *testing.T) {
+		// Arrange
+		k8sClient := fixClient()
+		first := fixCoordinator(k8sClient, "amazing-agnesi")
+		second := fixCoordinator(k8sClient, "bold-babbage")
+		require.NoError(t, first.sync(context.Background()))
+
+		// Act
+		// second replica waits until the first one releases its shards
+		require.NoError(t, second.sync(context.Background()))
+		require.Empty(t, ownedShards(second))
+		require.NoError(t, first.sync(context.Background()))
+		require.NoError(t, second.sync(context.Background()))
+
+		// Assert
+		require.Equal(t, []int{0, 2}, ownedShards(first))
+		require.Equal(t, []int{1, 3}, ownedShards(second))
+	})
+	t.Run("take over shards of replica which is gone", func(t *testing.T) {
+		// Arrange
+		k8sClient := fixClient()
+		first := fixCoordinator(k8sClient, "amazing-agnesi")
+		second := fixCoordinator(k8sClient, "bold-babbage")
+		require.NoError(t, first.sync(context.Background()))
+		require.NoError(t, second.sync(context.Background()))
+		require.NoError(t, first.sync(context.Background()))
+		require.NoError(t, second.sync(context.Background()))
+		now = now.Add(time.Minute)
+
+		// Act
+		// the first replica didn't renew its leases
+		require.NoError(t, second.sync(context.Background()))
+
+		// Assert
+		require.Equal(t, []int{0, 1, 2, 3}, ownedShards(second))
+		members := coordinationv1.LeaseList{}
+		require.NoError(t, k8sClient.List(context.Background(), &members, client.MatchingLabels{leaseTypeLabel: leaseTypeMember}))
+		require.Len(t, members.Items, 1)
+	})
+	t.Run("release shards on shutdown", func(t *testing.T) {
+		// Arrange
+		k8sClient := fixClient()
+		c := fixCoordinator(k8sClient, "pensive-pascal")
+		require.NoError(t, c.sync(context.Background()))
+
+		// Act
+		c.release(context.Background())
+
+		// Assert
+		require.Empty(t, ownedShards(c))
+		lease := coordinationv1.Lease{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{Namespace: "kyma-system", Name: "serverless-controller-shard-0"}, &lease))
+		require.Nil(t, lease.Spec.HolderIdentity)
+	})
+	t.Run("stop owning shard when lease is not renewed", func(t *testing.T) {
+		// Arrange
+		c := fixCoordinator(fixClient(), "pensive-pascal")
+		require.NoError(t, c.sync(context.Background()))
+
+		// Act
+		now = now.Add(cfg.LeaseDuration)
+
+		// Assert
+		require.Empty(t, ownedShards(c))
+	})
+	t.Run("enqueue functions of acquired shard", func(t *testing.T) {
+		// Arrange
+		f := &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{Name: "nifty-noether-name", Namespace: "youthful-yalow-ns"},
+		}
+		c := fixCoordinator(fixClient(f), "pensive-pascal")
+
+		// Act
+		require.NoError(t, c.sync(context.Background()))
+
+		// Assert
+		select {
+		case e := <-c.Events():
+			require.Equal(t, "nifty-noether-name", e.Object.GetName())
+		case <-time.After(time.Second):
+			require.Fail(t, "function was not enqueued")
+		}
+	})
+}
+
+func TestCoordinator_OwnsObject(t *testing.T) {
+	fixFunction := func(namespace string, labels map[string]string) *serverlessv1alpha2.Function {
+		return &serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{Name: "vibrant-villani-name", Namespace: namespace, Labels: labels},
+		}
+	}
+	fixCoordinator := func(key string, namespace string) *Coordinator {
+		c := NewCoordinator(nil, config.ShardingConfig{Shards: 8, Key: key, LeaseDuration: time.Minute}, "pensive-pascal", zap.NewNop().Sugar())
+		c.renewedAt[c.shardOf(namespace)] = time.Now()
+		return c
+	}
+
+	t.Run("own function from namespace of held shard", func(t *testing.T) {
+		c := fixCoordinator(KeyNamespace, "youthful-yalow-ns")
+
+		require.True(t, c.OwnsFunction(fixFunction("youthful-yalow-ns", nil)))
+		require.True(t, c.OwnsObject(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "youthful-yalow-ns"}}))
+	})
+	t.Run("skip function from other shard", func(t *testing.T) {
+		c := fixCoordinator(KeyNamespace, "youthful-yalow-ns")
+		other := "compassionate-curran-ns"
+		require.NotEqual(t, c.shardOf("youthful-yalow-ns"), c.shardOf(other))
+
+		require.False(t, c.OwnsObject(fixFunction(other, nil)))
+		require.False(t, c.OwnsObject(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: other}}))
+	})
+	t.Run("choose shard by label", func(t *testing.T) {
+		c := fixCoordinator(KeyLabel, "sharp-shirley")
+		other := "compassionate-curran-ns"
+		require.NotEqual(t, c.shardOf("sharp-shirley"), c.shardOf(other))
+
+		require.True(t, c.OwnsFunction(fixFunction(other, map[string]string{serverlessv1alpha2.FunctionShardLabel: "sharp-shirley"})))
+		require.False(t, c.OwnsFunction(fixFunction(other, nil)))
+		// owned resources don't carry the shard label
+		require.True(t, c.OwnsObject(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: other}}))
+	})
+	t.Run("own everything when sharding is disabled", func(t *testing.T) {
+		var c *Coordinator
+
+		require.True(t, c.OwnsFunction(fixFunction("youthful-yalow-ns", nil)))
+		require.True(t, c.OwnsObject(&appsv1.Deployment{}))
+	})
+}
@@ -28,6 +28,7 @@ func sFnAdjustStatus(_ context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl
 	s.FunctionResourceProfile = m.State.BuiltDeployment.ResourceProfile()
 	s.ContainerSecurityContext = m.State.BuiltDeployment.ContainerSecurityContext()
 	s.PodSecurityContext = m.State.BuiltDeployment.PodSecurityContext()
+	s.Env = m.State.BuiltDeployment.EffectiveEnvs()
 
 	if m.State.Function.HasGitSources() {
 		s.GitRepository = &serverlessv1alpha2.GitRepositoryStatus{
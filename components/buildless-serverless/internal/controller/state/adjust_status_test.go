@@ -75,6 +75,11 @@ func Test_sFnAdjustStatus(t *testing.T) {
 		// UUID is unset because it is fake object
 		require.Contains(t, m.State.Function.Status.PodSelector, "serverless.kyma-project.io/uuid=")
 		require.Equal(t, "charming-dubinsky", m.State.Function.Status.FunctionResourceProfile)
+		// effective envs without values
+		require.Contains(t, m.State.Function.Status.Env, serverlessv1alpha2.EnvStatus{
+			Name:   "FUNC_NAME",
+			Source: serverlessv1alpha2.EnvSourceSystem,
+		})
 	})
 	t.Run("status is set and requeue after long time from config for git function", func(t *testing.T) {
 		// Arrange
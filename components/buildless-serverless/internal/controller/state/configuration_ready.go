@@ -3,9 +3,10 @@ package state
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
-	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,8 +14,9 @@ import (
 )
 
 const (
-	configurationReadyMessage      = "Function configured"
-	warningRuntimeDeprecatedFormat = "Warning: function configured, runtime %s is deprecated and will be removed in the future"
+	configurationReadyMessage = "Function configured"
+	runtimeDeprecatedFormat   = "Function configured, runtime %s is deprecated and will be removed in the future"
+	envOverrideDeprecatedFmt  = "Function configured, overriding %s set by Serverless is deprecated and will be rejected in the future, overridable envs: %s"
 )
 
 func sFnConfigurationReady(_ context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
@@ -23,7 +25,12 @@ func sFnConfigurationReady(_ context.Context, m *fsm.StateMachine) (fsm.StateFn,
 
 	if m.State.Function.Spec.Runtime.IsRuntimeDeprecated() {
 		// warn users when runtime is deprecated
-		msg = fmt.Sprintf(warningRuntimeDeprecatedFormat, m.State.Function.Spec.Runtime)
+		msg = fmt.Sprintf(runtimeDeprecatedFormat, m.State.Function.Spec.Runtime)
+		reason = serverlessv1alpha2.ConditionReasonRuntimeDeprecated
+	} else if overrides := resources.DeprecatedEnvOverrides(&m.State.Function, &m.FunctionConfig); len(overrides) > 0 {
+		// warn users overriding system envs which were accepted before the override policy was introduced
+		msg = fmt.Sprintf(envOverrideDeprecatedFmt, strings.Join(overrides, ", "), resources.OverridableEnvs)
+		reason = serverlessv1alpha2.ConditionReasonEnvOverrideDeprecated
 	}
 
 	m.State.Function.UpdateCondition(
@@ -31,7 +38,7 @@ func sFnConfigurationReady(_ context.Context, m *fsm.StateMachine) (fsm.StateFn,
 		metav1.ConditionTrue,
 		reason,
 		msg)
-	metrics.PublishStateReachTime(m.State.Function, serverlessv1alpha2.ConditionConfigurationReady)
+	m.Metrics.PublishStateReachTime(m.State.Function, serverlessv1alpha2.ConditionConfigurationReady)
 
-	return nextState(sFnHandleDeployment)
+	return nextState(sFnHandleServiceAccount)
 }
@@ -7,6 +7,7 @@ import (
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -26,7 +27,7 @@ func Test_sFnConfigurationReady(t *testing.T) {
 		require.Nil(t, result)
 		// with expected next state
 		require.NotNil(t, next)
-		requireEqualFunc(t, sFnHandleDeployment, next)
+		requireEqualFunc(t, sFnHandleServiceAccount, next)
 		// function has proper condition
 		requireContainsCondition(t, m.State.Function.Status,
 			serverlessv1alpha2.ConditionConfigurationReady,
@@ -56,12 +57,46 @@ func Test_sFnConfigurationReady(t *testing.T) {
 		require.Nil(t, result)
 		// with expected next state
 		require.NotNil(t, next)
-		requireEqualFunc(t, sFnHandleDeployment, next)
+		requireEqualFunc(t, sFnHandleServiceAccount, next)
 		// function has proper condition
 		requireContainsCondition(t, m.State.Function.Status,
 			serverlessv1alpha2.ConditionConfigurationReady,
 			metav1.ConditionTrue,
-			serverlessv1alpha2.ConditionReasonFunctionSpecValidated,
-			"Warning: function configured, runtime nodejs20 is deprecated and will be removed in the future")
+			serverlessv1alpha2.ConditionReasonRuntimeDeprecated,
+			"Function configured, runtime nodejs20 is deprecated and will be removed in the future")
+	})
+	t.Run("should set warning on deprecated env override and go to the next state", func(t *testing.T) {
+		// Arrange
+		// machine with function overriding system envs
+		m := fsm.StateMachine{State: fsm.SystemState{
+			Function: serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					Runtime: serverlessv1alpha2.NodeJs22,
+					Env: []corev1.EnvVar{
+						{Name: "FUNC_NAME", Value: "quirky-quimby"},
+						{Name: "TRACE_COLLECTOR_ENDPOINT", Value: "http://collector:4318"},
+						{Name: "HANDLER_PATH", Value: "./quirky-quimby.js"},
+					},
+				},
+			},
+		}}
+
+		// Act
+		next, result, err := sFnConfigurationReady(context.Background(), &m)
+
+		// Assert
+		// no errors
+		require.Nil(t, err)
+		// without stopping processing
+		require.Nil(t, result)
+		// with expected next state
+		require.NotNil(t, next)
+		requireEqualFunc(t, sFnHandleServiceAccount, next)
+		// function has proper condition
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionConfigurationReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonEnvOverrideDeprecated,
+			"Function configured, overriding FUNC_NAME, HANDLER_PATH set by Serverless is deprecated and will be rejected in the future, overridable envs: [TRACE_COLLECTOR_ENDPOINT PUBLISHER_PROXY_ADDRESS PYTHONUNBUFFERED]")
 	})
 }
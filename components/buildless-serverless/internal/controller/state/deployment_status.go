@@ -6,7 +6,6 @@ import (
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
-	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/metrics"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v2"
 	appsv1 "k8s.io/api/apps/v1"
@@ -40,11 +39,28 @@ func sFnDeploymentStatus(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn,
 			metav1.ConditionTrue,
 			serverlessv1alpha2.ConditionReasonDeploymentReady,
 			fmt.Sprintf("Deployment %s is ready", deploymentName))
-		metrics.PublishStateReachTime(m.State.Function, serverlessv1alpha2.ConditionRunning)
+		m.Metrics.PublishStateReachTime(m.State.Function, serverlessv1alpha2.ConditionRunning)
+		m.State.Function.Status.Diagnostics = nil
 
 		return nextState(sFnAdjustStatus)
 	}
 
+	updateDiagnostics(ctx, m)
+
+	// dependencies can't be installed
+	if diagnostic := dependencyInstallFailure(m.State.Function.Status.Diagnostics); diagnostic != nil {
+		m.Log.Info(fmt.Sprintf("dependency installation failed in pod %q", diagnostic.Pod))
+
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonDependencyInstallFailed,
+			fmt.Sprintf("Dependency installation failed in pod %s: \n%s", diagnostic.Pod, diagnostic.LogExcerpt))
+
+		// requeue, because the installation may succeed when the package registry is back
+		return requeue()
+	}
+
 	// unhealthy deployment
 	if hasDeploymentConditionFalseStatusWithReason(deployment.Status.Conditions, appsv1.DeploymentAvailable, MinimumReplicasUnavailable) {
 		m.Log.Info(fmt.Sprintf("deployment unhealthy: %q", deploymentName))
@@ -88,6 +104,32 @@ func sFnDeploymentStatus(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn,
 	return stop()
 }
 
+// updateDiagnostics reports failures found in the Function's Pods,
+// diagnostics are best effort and never stop the reconciliation
+func updateDiagnostics(ctx context.Context, m *fsm.StateMachine) {
+	if m.PodInspector == nil {
+		return
+	}
+	diagnostics, err := m.PodInspector.Inspect(ctx, &m.State.Function)
+	if err != nil {
+		m.Log.Warnf("while inspecting function pods: %s", err.Error())
+		return
+	}
+	if len(diagnostics) == 0 {
+		diagnostics = nil
+	}
+	m.State.Function.Status.Diagnostics = diagnostics
+}
+
+func dependencyInstallFailure(diagnostics []serverlessv1alpha2.FunctionDiagnostic) *serverlessv1alpha2.FunctionDiagnostic {
+	for i := range diagnostics {
+		if diagnostics[i].Reason == serverlessv1alpha2.DiagnosticReasonDependencyInstallFailed {
+			return &diagnostics[i]
+		}
+	}
+	return nil
+}
+
 const (
 	// Progressing:
 	// NewRSAvailableReason is added in a deployment when its newest replica set is made available
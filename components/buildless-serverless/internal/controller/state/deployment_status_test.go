@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/diagnostics/automock"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
@@ -15,6 +17,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -238,6 +241,112 @@ func Test_sFnDeploymentStatus(t *testing.T) {
 			serverlessv1alpha2.ConditionReasonDeploymentFailed,
 			expectedMsg)
 	})
+	t.Run("when deployment is not ready should report pod diagnostics", func(t *testing.T) {
+		// Arrange
+		// our function
+		f := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sleepy-tesla-name",
+				Namespace: "vibrant-noether-ns"}}
+		// deployment which will be returned from kubernetes
+		deployment := appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sleepy-tesla-name",
+				Namespace: "vibrant-noether-ns",
+				Labels:    f.InternalFunctionLabels()},
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue}}}}
+		// scheme and fake client
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, appsv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&deployment).Build()
+		// inspector which finds failing container
+		diagnostic := serverlessv1alpha2.FunctionDiagnostic{
+			Pod:        "sleepy-tesla-name-7b5d9",
+			Container:  "install",
+			Reason:     serverlessv1alpha2.DiagnosticReasonDependencyInstallFailed,
+			ExitCode:   ptr.To[int32](1),
+			LogExcerpt: "npm error 404 Not Found - GET https://registry.npmjs.org/not-existing",
+		}
+		inspectorMock := automock.NewPodInspector(t)
+		inspectorMock.On("Inspect", mock.Anything, mock.Anything).
+			Return([]serverlessv1alpha2.FunctionDiagnostic{diagnostic}, nil).Once()
+		// machine with our function
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:          zap.NewNop().Sugar(),
+			Client:       k8sClient,
+			Scheme:       scheme,
+			PodInspector: inspectorMock}
+
+		// Act
+		next, result, err := sFnDeploymentStatus(context.Background(), &m)
+
+		// Assert
+		// no errors
+		require.Nil(t, err)
+		// we expect stop and requeue
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		// function has diagnostics
+		require.Equal(t, []serverlessv1alpha2.FunctionDiagnostic{diagnostic}, m.State.Function.Status.Diagnostics)
+		// function has condition with the package manager output
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonDependencyInstallFailed,
+			"Dependency installation failed in pod sleepy-tesla-name-7b5d9: \nnpm error 404 Not Found - GET https://registry.npmjs.org/not-existing")
+	})
+	t.Run("when deployment is ready should clear diagnostics", func(t *testing.T) {
+		// Arrange
+		// our function with diagnostics from the previous reconciliation
+		f := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nifty-lamarr-name",
+				Namespace: "upbeat-hypatia-ns"},
+			Status: serverlessv1alpha2.FunctionStatus{
+				Diagnostics: []serverlessv1alpha2.FunctionDiagnostic{{
+					Pod:       "nifty-lamarr-name-5c7f8",
+					Container: "function",
+					Reason:    serverlessv1alpha2.DiagnosticReasonOOMKilled,
+				}}}}
+		// deployment which will be returned from kubernetes
+		deployment := appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nifty-lamarr-name",
+				Namespace: "upbeat-hypatia-ns",
+				Labels:    f.InternalFunctionLabels()},
+			Status: appsv1.DeploymentStatus{
+				Conditions: []appsv1.DeploymentCondition{
+					{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue, Reason: MinimumReplicasAvailable},
+					{Type: appsv1.DeploymentProgressing, Status: corev1.ConditionTrue, Reason: NewRSAvailableReason}}}}
+		// scheme and fake client
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, appsv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&deployment).Build()
+		// machine with our function, inspector is not called for ready deployment
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:          zap.NewNop().Sugar(),
+			Client:       k8sClient,
+			Scheme:       scheme,
+			PodInspector: automock.NewPodInspector(t)}
+
+		// Act
+		next, result, err := sFnDeploymentStatus(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnAdjustStatus, next)
+		require.Nil(t, m.State.Function.Status.Diagnostics)
+	})
 	t.Run("when deployment not exists should requeue", func(t *testing.T) {
 		// Arrange
 		// scheme and fake client without deployment
@@ -4,13 +4,16 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -117,8 +120,9 @@ func updateDeploymentIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterD
 }
 
 func deploymentChanged(a *appsv1.Deployment, b *appsv1.Deployment) bool {
-	if len(a.Spec.Template.Spec.Containers) != 1 ||
-		len(b.Spec.Template.Spec.Containers) != 1 {
+	// the first container runs the Function, the rest are sidecars defined by the user
+	if len(a.Spec.Template.Spec.Containers) == 0 ||
+		len(b.Spec.Template.Spec.Containers) == 0 {
 		return true
 	}
 
@@ -135,10 +139,22 @@ func deploymentChanged(a *appsv1.Deployment, b *appsv1.Deployment) bool {
 	commandChanged := !reflect.DeepEqual(aContainer.Command, bContainer.Command)
 	resourcesChanged := !equalResources(aContainer.Resources, bContainer.Resources)
 	envChanged := !reflect.DeepEqual(aContainer.Env, bContainer.Env)
+	envFromChanged := !reflect.DeepEqual(aContainer.EnvFrom, bContainer.EnvFrom)
 	volumeMountsChanged := !reflect.DeepEqual(aContainer.VolumeMounts, bContainer.VolumeMounts)
+	volumesChanged := !equalVolumes(a.Spec.Template.Spec.Volumes, b.Spec.Template.Spec.Volumes)
 	portsChanged := !reflect.DeepEqual(aContainer.Ports, bContainer.Ports)
 	podSecurityContextChanged := !reflect.DeepEqual(a.Spec.Template.Spec.SecurityContext, b.Spec.Template.Spec.SecurityContext)
 	containerSecurityContextChanged := !reflect.DeepEqual(aContainer.SecurityContext, bContainer.SecurityContext)
+	topologySpreadConstraintsChanged := !equalTopologySpreadConstraints(a.Spec.Template.Spec.TopologySpreadConstraints, b.Spec.Template.Spec.TopologySpreadConstraints)
+	schedulingChanged := !equalScheduling(a.Spec.Template.Spec, b.Spec.Template.Spec)
+	probesChanged := !equalProbes(aContainer.StartupProbe, bContainer.StartupProbe) ||
+		!equalProbes(aContainer.ReadinessProbe, bContainer.ReadinessProbe) ||
+		!equalProbes(aContainer.LivenessProbe, bContainer.LivenessProbe)
+	lifecycleChanged := !equalLifecycle(aContainer.Lifecycle, bContainer.Lifecycle)
+	terminationGracePeriodChanged := terminationGracePeriodSeconds(a.Spec.Template.Spec) != terminationGracePeriodSeconds(b.Spec.Template.Spec)
+	sidecarsChanged := !equalContainers(a.Spec.Template.Spec.Containers[1:], b.Spec.Template.Spec.Containers[1:])
+	serviceAccountChanged := a.Spec.Template.Spec.ServiceAccountName != b.Spec.Template.Spec.ServiceAccountName ||
+		!ptr.Equal(a.Spec.Template.Spec.AutomountServiceAccountToken, b.Spec.Template.Spec.AutomountServiceAccountToken)
 
 	return imageChanged ||
 		labelsChanged ||
@@ -148,27 +164,41 @@ func deploymentChanged(a *appsv1.Deployment, b *appsv1.Deployment) bool {
 		commandChanged ||
 		resourcesChanged ||
 		envChanged ||
+		envFromChanged ||
 		volumeMountsChanged ||
+		volumesChanged ||
 		portsChanged ||
 		podSecurityContextChanged ||
 		containerSecurityContextChanged ||
+		topologySpreadConstraintsChanged ||
+		schedulingChanged ||
+		probesChanged ||
+		lifecycleChanged ||
+		terminationGracePeriodChanged ||
+		sidecarsChanged ||
+		serviceAccountChanged ||
 		initContainerChanged(a, b)
 }
 
 func initContainerChanged(a *appsv1.Deployment, b *appsv1.Deployment) bool {
-	// there are no init containers for inline function and one init container for git function
-	// when count of init containers is not equal function type has been changed
-	if len(a.Spec.Template.Spec.InitContainers) > 1 ||
-		len(b.Spec.Template.Spec.InitContainers) > 1 ||
-		len(a.Spec.Template.Spec.InitContainers) != len(b.Spec.Template.Spec.InitContainers) {
+	// git function starts with the init container fetching sources, the rest are init containers defined by the user
+	// when count of init containers is not equal function type or user init containers have been changed
+	aContainers := a.Spec.Template.Spec.InitContainers
+	bContainers := b.Spec.Template.Spec.InitContainers
+	if len(aContainers) != len(bContainers) {
 		return true
 	}
-	if len(a.Spec.Template.Spec.InitContainers) == 0 {
+	if len(aContainers) == 0 {
 		return false
 	}
-	aContainer := a.Spec.Template.Spec.InitContainers[0]
-	bContainer := b.Spec.Template.Spec.InitContainers[0]
+	if aContainers[0].Name == resources.GitInitContainerName && bContainers[0].Name == resources.GitInitContainerName {
+		return gitInitContainerChanged(aContainers[0], bContainers[0]) ||
+			!equalContainers(aContainers[1:], bContainers[1:])
+	}
+	return !equalContainers(aContainers, bContainers)
+}
 
+func gitInitContainerChanged(aContainer, bContainer corev1.Container) bool {
 	imageChanged := aContainer.Image != bContainer.Image
 	workingDirChanged := !reflect.DeepEqual(aContainer.WorkingDir, bContainer.WorkingDir)
 	commandChanged := !reflect.DeepEqual(aContainer.Command, bContainer.Command)
@@ -181,6 +211,78 @@ func initContainerChanged(a *appsv1.Deployment, b *appsv1.Deployment) bool {
 		volumeMountsChanged
 }
 
+// equalContainers compares containers defined by the user, so all their fields are compared
+func equalContainers(a, b []corev1.Container) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		aContainer, bContainer := withContainerDefaults(a[i]), withContainerDefaults(b[i])
+		if !equalResources(aContainer.Resources, bContainer.Resources) {
+			return false
+		}
+		aContainer.Resources, bContainer.Resources = corev1.ResourceRequirements{}, corev1.ResourceRequirements{}
+		if !reflect.DeepEqual(aContainer, bContainer) {
+			return false
+		}
+	}
+	return true
+}
+
+// withContainerDefaults sets fields defaulted by the API server for containers
+func withContainerDefaults(container corev1.Container) corev1.Container {
+	result := *container.DeepCopy()
+	if result.TerminationMessagePath == "" {
+		result.TerminationMessagePath = corev1.TerminationMessagePathDefault
+	}
+	if result.TerminationMessagePolicy == "" {
+		result.TerminationMessagePolicy = corev1.TerminationMessageReadFile
+	}
+	if result.ImagePullPolicy == "" {
+		result.ImagePullPolicy = defaultImagePullPolicy(result.Image)
+	}
+	for i := range result.Ports {
+		if result.Ports[i].Protocol == "" {
+			result.Ports[i].Protocol = corev1.ProtocolTCP
+		}
+	}
+	for i := range result.Env {
+		fieldRef := result.Env[i].ValueFrom
+		if fieldRef != nil && fieldRef.FieldRef != nil && fieldRef.FieldRef.APIVersion == "" {
+			fieldRef.FieldRef.APIVersion = "v1"
+		}
+	}
+	for _, probe := range []**corev1.Probe{&result.StartupProbe, &result.ReadinessProbe, &result.LivenessProbe} {
+		if *probe != nil {
+			*probe = ptr.To(withProbeDefaults(**probe))
+		}
+	}
+	if result.Lifecycle != nil {
+		if result.Lifecycle.PreStop != nil {
+			result.Lifecycle.PreStop.HTTPGet = withHTTPGetDefaults(result.Lifecycle.PreStop.HTTPGet)
+		}
+		if result.Lifecycle.PostStart != nil {
+			result.Lifecycle.PostStart.HTTPGet = withHTTPGetDefaults(result.Lifecycle.PostStart.HTTPGet)
+		}
+	}
+	return result
+}
+
+func defaultImagePullPolicy(image string) corev1.PullPolicy {
+	if strings.Contains(image, "@") {
+		return corev1.PullIfNotPresent
+	}
+	// the tag is placed after the last colon which is not a part of the registry host
+	tag := ""
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		tag = image[i+1:]
+	}
+	if tag == "" || tag == "latest" {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
 func updateDeployment(ctx context.Context, m *fsm.StateMachine, clusterDeployment *appsv1.Deployment) (requeueNeeded bool, err error) {
 	if errUpdate := m.Client.Update(ctx, clusterDeployment); errUpdate != nil {
 		m.Log.Error(errUpdate, "Failed to update Deployment", "Deployment.Namespace", clusterDeployment.GetNamespace(), "Deployment.Name", clusterDeployment.GetName())
@@ -200,6 +302,135 @@ func updateDeployment(ctx context.Context, m *fsm.StateMachine, clusterDeploymen
 	return true, nil
 }
 
+func equalTopologySpreadConstraints(a, b []corev1.TopologySpreadConstraint) bool {
+	// nil and empty lists are equal
+	if len(a) == 0 && len(b) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func equalScheduling(a, b corev1.PodSpec) bool {
+	tolerationsEqual := (len(a.Tolerations) == 0 && len(b.Tolerations) == 0) ||
+		reflect.DeepEqual(a.Tolerations, b.Tolerations)
+
+	return mapsEqual(a.NodeSelector, b.NodeSelector) &&
+		tolerationsEqual &&
+		reflect.DeepEqual(a.Affinity, b.Affinity) &&
+		a.PriorityClassName == b.PriorityClassName &&
+		ptr.Equal(a.RuntimeClassName, b.RuntimeClassName)
+}
+
+// equalProbes compares probes ignoring values defaulted by the api-server
+func equalProbes(a, b *corev1.Probe) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return reflect.DeepEqual(withProbeDefaults(*a), withProbeDefaults(*b))
+}
+
+func withProbeDefaults(probe corev1.Probe) corev1.Probe {
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = 1
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = 10
+	}
+	if probe.SuccessThreshold == 0 {
+		probe.SuccessThreshold = 1
+	}
+	if probe.FailureThreshold == 0 {
+		probe.FailureThreshold = 3
+	}
+	probe.HTTPGet = withHTTPGetDefaults(probe.HTTPGet)
+	return probe
+}
+
+func withHTTPGetDefaults(httpGet *corev1.HTTPGetAction) *corev1.HTTPGetAction {
+	if httpGet == nil || httpGet.Scheme != "" {
+		return httpGet
+	}
+	result := *httpGet
+	result.Scheme = corev1.URISchemeHTTP
+	return &result
+}
+
+func equalLifecycle(a, b *corev1.Lifecycle) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return equalLifecycleHandler(a.PreStop, b.PreStop) &&
+		equalLifecycleHandler(a.PostStart, b.PostStart)
+}
+
+func equalLifecycleHandler(a, b *corev1.LifecycleHandler) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	aHandler, bHandler := *a, *b
+	aHandler.HTTPGet = withHTTPGetDefaults(aHandler.HTTPGet)
+	bHandler.HTTPGet = withHTTPGetDefaults(bHandler.HTTPGet)
+	return reflect.DeepEqual(aHandler, bHandler)
+}
+
+func equalVolumes(a, b []corev1.Volume) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !reflect.DeepEqual(withVolumeDefaults(a[i]), withVolumeDefaults(b[i])) {
+			return false
+		}
+	}
+	return true
+}
+
+// withVolumeDefaults sets fields defaulted by the API server for volume sources used by the Function
+func withVolumeDefaults(volume corev1.Volume) corev1.Volume {
+	source := volume.VolumeSource
+	if source.EmptyDir != nil && source.EmptyDir.SizeLimit != nil {
+		// quantities are stored in the canonical form
+		emptyDir := *source.EmptyDir
+		emptyDir.SizeLimit = ptr.To(resource.MustParse(source.EmptyDir.SizeLimit.String()))
+		source.EmptyDir = &emptyDir
+	}
+	if source.Secret != nil && source.Secret.DefaultMode == nil {
+		secret := *source.Secret
+		secret.DefaultMode = ptr.To(corev1.SecretVolumeSourceDefaultMode)
+		source.Secret = &secret
+	}
+	if source.ConfigMap != nil && source.ConfigMap.DefaultMode == nil {
+		configMap := *source.ConfigMap
+		configMap.DefaultMode = ptr.To(corev1.ConfigMapVolumeSourceDefaultMode)
+		source.ConfigMap = &configMap
+	}
+	if source.Projected != nil {
+		projected := *source.Projected
+		if projected.DefaultMode == nil {
+			projected.DefaultMode = ptr.To(corev1.ProjectedVolumeSourceDefaultMode)
+		}
+		projected.Sources = make([]corev1.VolumeProjection, len(source.Projected.Sources))
+		for i, projection := range source.Projected.Sources {
+			if projection.ServiceAccountToken != nil && projection.ServiceAccountToken.ExpirationSeconds == nil {
+				token := *projection.ServiceAccountToken
+				token.ExpirationSeconds = ptr.To[int64](3600)
+				projection.ServiceAccountToken = &token
+			}
+			projected.Sources[i] = projection
+		}
+		source.Projected = &projected
+	}
+	volume.VolumeSource = source
+	return volume
+}
+
+func terminationGracePeriodSeconds(podSpec corev1.PodSpec) int64 {
+	if podSpec.TerminationGracePeriodSeconds == nil {
+		return corev1.DefaultTerminationGracePeriodSeconds
+	}
+	return *podSpec.TerminationGracePeriodSeconds
+}
+
 func equalResources(a, b corev1.ResourceRequirements) bool {
 	return a.Requests.Memory().Equal(*b.Requests.Memory()) &&
 		a.Requests.Cpu().Equal(*b.Requests.Cpu()) &&
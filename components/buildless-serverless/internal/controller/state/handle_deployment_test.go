@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -733,6 +734,29 @@ func Test_deploymentChanged(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "when envFrom are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									EnvFrom: []corev1.EnvFromSource{{
+										ConfigMapRef: &corev1.ConfigMapEnvSource{
+											LocalObjectReference: corev1.LocalObjectReference{Name: "relaxed-blackwell"}}}}}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									EnvFrom: []corev1.EnvFromSource{{
+										Prefix: "relaxed-blackwell",
+										ConfigMapRef: &corev1.ConfigMapEnvSource{
+											LocalObjectReference: corev1.LocalObjectReference{Name: "relaxed-blackwell"}}}}}}}}}},
+			},
+			want: true,
+		},
 		{
 			name: "when volumeMounts are different should return true",
 			args: args{
@@ -763,6 +787,91 @@ func Test_deploymentChanged(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "when volumes are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Volumes: []corev1.Volume{{
+									Name: "sharp-lamport",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{Name: "sharp-lamport"},
+											Items:                []corev1.KeyToPath{{Key: "sharp-lamport", Path: "sharp-lamport"}}}}}},
+								Containers: []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Volumes: []corev1.Volume{{
+									Name: "sharp-lamport",
+									VolumeSource: corev1.VolumeSource{
+										ConfigMap: &corev1.ConfigMapVolumeSource{
+											LocalObjectReference: corev1.LocalObjectReference{Name: "sharp-lamport"},
+											Items:                []corev1.KeyToPath{{Key: "sharp-lamport", Path: "quirky-curie"}}}}}},
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when volumes differ only by defaulted fields should return false",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Volumes: []corev1.Volume{
+									{
+										Name: "nifty-babbage",
+										VolumeSource: corev1.VolumeSource{
+											EmptyDir: &corev1.EmptyDirVolumeSource{
+												SizeLimit: ptr.To(resource.MustParse("1536Mi"))}}},
+									{
+										Name: "vibrant-moser",
+										VolumeSource: corev1.VolumeSource{
+											Secret: &corev1.SecretVolumeSource{
+												SecretName:  "vibrant-moser",
+												DefaultMode: ptr.To[int32](0644)}}},
+									{
+										Name: "busy-kepler",
+										VolumeSource: corev1.VolumeSource{
+											Projected: &corev1.ProjectedVolumeSource{
+												Sources: []corev1.VolumeProjection{{
+													ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+														Audience:          "busy-kepler",
+														ExpirationSeconds: ptr.To[int64](3600),
+														Path:              "token"}}},
+												DefaultMode: ptr.To[int32](0644)}}}},
+								Containers: []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Volumes: []corev1.Volume{
+									{
+										Name: "nifty-babbage",
+										VolumeSource: corev1.VolumeSource{
+											EmptyDir: &corev1.EmptyDirVolumeSource{
+												SizeLimit: ptr.To(resource.MustParse("1.5Gi"))}}},
+									{
+										Name: "vibrant-moser",
+										VolumeSource: corev1.VolumeSource{
+											Secret: &corev1.SecretVolumeSource{
+												SecretName: "vibrant-moser"}}},
+									{
+										Name: "busy-kepler",
+										VolumeSource: corev1.VolumeSource{
+											Projected: &corev1.ProjectedVolumeSource{
+												Sources: []corev1.VolumeProjection{{
+													ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+														Audience: "busy-kepler",
+														Path:     "token"}}}}}}},
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: false,
+		},
 		{
 			name: "when ports are different should return true",
 			args: args{
@@ -791,6 +900,266 @@ func Test_deploymentChanged(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "when topologySpreadConstraints are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								TopologySpreadConstraints: []corev1.TopologySpreadConstraint{{
+									MaxSkew:     1,
+									TopologyKey: "kubernetes.io/hostname"}},
+								Containers: []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when topologySpreadConstraints are nil and empty should return false",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								TopologySpreadConstraints: []corev1.TopologySpreadConstraint{},
+								Containers:                []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: false,
+		},
+		{
+			name: "when nodeSelectors are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								NodeSelector: map[string]string{"pool": "sharp-shamir"},
+								Containers:   []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when tolerations are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Tolerations: []corev1.Toleration{{Key: "sleepy-shannon", Operator: corev1.TolerationOpExists}},
+								Containers:  []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when affinities are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Affinity:   &corev1.Affinity{PodAntiAffinity: &corev1.PodAntiAffinity{}},
+								Containers: []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when priorityClassNames are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								PriorityClassName: "stoic-stallman",
+								Containers:        []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when runtimeClassNames are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								RuntimeClassName: ptr.To("suspicious-sutherland"),
+								Containers:       []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when probes are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									LivenessProbe: &corev1.Probe{
+										PeriodSeconds:    5,
+										FailureThreshold: 3}}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									LivenessProbe: &corev1.Probe{
+										PeriodSeconds:    20,
+										FailureThreshold: 3}}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when probes differ only by defaulted fields should return false",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									StartupProbe: &corev1.Probe{
+										ProbeHandler: corev1.ProbeHandler{
+											HTTPGet: &corev1.HTTPGetAction{
+												Path: "/healthz"}},
+										PeriodSeconds: 5}}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									StartupProbe: &corev1.Probe{
+										ProbeHandler: corev1.ProbeHandler{
+											HTTPGet: &corev1.HTTPGetAction{
+												Path:   "/healthz",
+												Scheme: corev1.URISchemeHTTP}},
+										PeriodSeconds:    5,
+										TimeoutSeconds:   1,
+										SuccessThreshold: 1,
+										FailureThreshold: 3}}}}}}},
+			},
+			want: false,
+		},
+		{
+			name: "when preStop hooks are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{
+									Lifecycle: &corev1.Lifecycle{
+										PreStop: &corev1.LifecycleHandler{
+											Sleep: &corev1.SleepAction{Seconds: 5}}}}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when terminationGracePeriodSeconds are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								TerminationGracePeriodSeconds: ptr.To[int64](300),
+								Containers:                    []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when terminationGracePeriodSeconds is defaulted should return false",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								TerminationGracePeriodSeconds: ptr.To[int64](30),
+								Containers:                    []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: false,
+		},
+		{
+			name: "when serviceAccountName is different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								ServiceAccountName: "vigilant-villani-sa",
+								Containers:         []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when automountServiceAccountToken is different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								AutomountServiceAccountToken: ptr.To(false),
+								Containers:                   []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
 		{
 			name: "when (some) not compared fields are different should return false",
 			args: args{
@@ -815,13 +1184,8 @@ func Test_deploymentChanged(t *testing.T) {
 								Namespace:    "dazzling-tharp",
 								UID:          "dazzling-tharp"},
 							Spec: corev1.PodSpec{
-								Volumes: []corev1.Volume{{
-									Name: "dazzling-tharp",
-									VolumeSource: corev1.VolumeSource{
-										EmptyDir: &corev1.EmptyDirVolumeSource{
-											Medium: corev1.StorageMediumMemory}}}},
 								InitContainers: []corev1.Container{{
-									Name: "dazzling-tharp"}},
+									Name: "init"}},
 								Containers: []corev1.Container{{
 									Name:            "dazzling-tharp",
 									Args:            []string{"dazzling-tharp"},
@@ -830,11 +1194,10 @@ func Test_deploymentChanged(t *testing.T) {
 										RunAsUser:    ptr.To[int64](579),
 										RunAsGroup:   ptr.To[int64](579),
 										RunAsNonRoot: ptr.To[bool](true)}}},
-								NodeName:          "dazzling-tharp",
-								Hostname:          "dazzling-tharp",
-								Subdomain:         "dazzling-tharp",
-								PriorityClassName: "dazzling-tharp",
-								Priority:          ptr.To[int32](579)}},
+								NodeName:  "dazzling-tharp",
+								Hostname:  "dazzling-tharp",
+								Subdomain: "dazzling-tharp",
+								Priority:  ptr.To[int32](579)}},
 						MinReadySeconds: 579},
 					Status: appsv1.DeploymentStatus{
 						Replicas: 579,
@@ -863,13 +1226,8 @@ func Test_deploymentChanged(t *testing.T) {
 								Namespace:    "thirsty-jemison",
 								UID:          "thirsty-jemison"},
 							Spec: corev1.PodSpec{
-								Volumes: []corev1.Volume{{
-									Name: "thirsty-jemison",
-									VolumeSource: corev1.VolumeSource{
-										EmptyDir: &corev1.EmptyDirVolumeSource{
-											Medium: corev1.StorageMediumHugePages}}}},
 								InitContainers: []corev1.Container{{
-									Name: "thirsty-jemison"}},
+									Name: "init"}},
 								Containers: []corev1.Container{{
 									Name:            "thirsty-jemison",
 									Args:            []string{"thirsty-jemison"},
@@ -878,11 +1236,10 @@ func Test_deploymentChanged(t *testing.T) {
 										RunAsUser:    ptr.To[int64](579),
 										RunAsGroup:   ptr.To[int64](579),
 										RunAsNonRoot: ptr.To[bool](true)}}},
-								NodeName:          "thirsty-jemison",
-								Hostname:          "thirsty-jemison",
-								Subdomain:         "thirsty-jemison",
-								PriorityClassName: "thirsty-jemison",
-								Priority:          ptr.To[int32](246)}},
+								NodeName:  "thirsty-jemison",
+								Hostname:  "thirsty-jemison",
+								Subdomain: "thirsty-jemison",
+								Priority:  ptr.To[int32](246)}},
 						MinReadySeconds: 246},
 					Status: appsv1.DeploymentStatus{
 						Replicas: 246,
@@ -893,6 +1250,153 @@ func Test_deploymentChanged(t *testing.T) {
 			},
 			want: false,
 		},
+		{
+			name: "when sidecars are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Name: "function"},
+									{Name: "gifted-newton", Image: "gifted-newton:1.0", Args: []string{"gifted-newton"}}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Name: "function"},
+									{Name: "gifted-newton", Image: "gifted-newton:1.0", Args: []string{"kind-lovelace"}}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when sidecar is removed should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Name: "function"},
+									{Name: "gifted-newton", Image: "gifted-newton:1.0"}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Name: "function"}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when sidecars differ only by defaulted fields should return false",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Name: "function"},
+									{
+										Name:                     "gifted-newton",
+										Image:                    "registry:5000/gifted-newton",
+										ImagePullPolicy:          corev1.PullAlways,
+										TerminationMessagePath:   "/dev/termination-log",
+										TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+										Ports: []corev1.ContainerPort{{
+											ContainerPort: 9090,
+											Protocol:      corev1.ProtocolTCP}},
+										Resources: corev1.ResourceRequirements{
+											Limits: corev1.ResourceList{
+												corev1.ResourceMemory: resource.MustParse("1536Mi")}},
+										Env: []corev1.EnvVar{{
+											Name: "gifted-newton",
+											ValueFrom: &corev1.EnvVarSource{
+												FieldRef: &corev1.ObjectFieldSelector{
+													APIVersion: "v1",
+													FieldPath:  "metadata.name"}}}},
+										ReadinessProbe: &corev1.Probe{
+											ProbeHandler: corev1.ProbeHandler{
+												HTTPGet: &corev1.HTTPGetAction{
+													Path:   "/ready",
+													Port:   intstr.FromInt32(9090),
+													Scheme: corev1.URISchemeHTTP}},
+											TimeoutSeconds:   1,
+											PeriodSeconds:    10,
+											SuccessThreshold: 1,
+											FailureThreshold: 3}}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{Name: "function"},
+									{
+										Name:  "gifted-newton",
+										Image: "registry:5000/gifted-newton",
+										Ports: []corev1.ContainerPort{{
+											ContainerPort: 9090}},
+										Resources: corev1.ResourceRequirements{
+											Limits: corev1.ResourceList{
+												corev1.ResourceMemory: resource.MustParse("1.5Gi")}},
+										Env: []corev1.EnvVar{{
+											Name: "gifted-newton",
+											ValueFrom: &corev1.EnvVarSource{
+												FieldRef: &corev1.ObjectFieldSelector{
+													FieldPath: "metadata.name"}}}},
+										ReadinessProbe: &corev1.Probe{
+											ProbeHandler: corev1.ProbeHandler{
+												HTTPGet: &corev1.HTTPGetAction{
+													Path: "/ready",
+													Port: intstr.FromInt32(9090)}}}}}}}}},
+			},
+			want: false,
+		},
+		{
+			name: "when user init containers are different should return true",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								InitContainers: []corev1.Container{
+									{Name: "init", Image: "stoic-euler"},
+									{Name: "upbeat-ride", Image: "upbeat-ride:1.0", Command: []string{"upbeat-ride"}}},
+								Containers: []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								InitContainers: []corev1.Container{
+									{Name: "init", Image: "stoic-euler"},
+									{Name: "upbeat-ride", Image: "upbeat-ride:1.0", Command: []string{"eager-hertz"}}},
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when user init containers are equal should return false",
+			args: args{
+				a: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								InitContainers: []corev1.Container{
+									{Name: "init", Image: "stoic-euler", ImagePullPolicy: corev1.PullIfNotPresent},
+									{Name: "upbeat-ride", Image: "upbeat-ride:1.0", ImagePullPolicy: corev1.PullIfNotPresent}},
+								Containers: []corev1.Container{{}}}}}},
+				b: &appsv1.Deployment{
+					Spec: appsv1.DeploymentSpec{
+						Template: corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								InitContainers: []corev1.Container{
+									{Name: "init", Image: "stoic-euler"},
+									{Name: "upbeat-ride", Image: "upbeat-ride:1.0"}},
+								Containers: []corev1.Container{{}}}}}},
+			},
+			want: false,
+		},
 		{
 			name: "when there are no init containers should return false",
 			args: args{
@@ -0,0 +1,324 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func sFnHandleExpose(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	clusterAPIRule, apiRuleInstalled, errGet := getExposeObject(ctx, m, resources.APIRuleGVK)
+	if errGet != nil {
+		return stopWithError(errGet)
+	}
+	clusterHTTPRoute, httpRouteInstalled, errGet := getExposeObject(ctx, m, resources.HTTPRouteGVK)
+	if errGet != nil {
+		return stopWithError(errGet)
+	}
+
+	f := &m.State.Function
+	if !resources.ExposeEnabled(f) {
+		f.Status.URL = ""
+		for _, clusterObj := range []*unstructured.Unstructured{clusterAPIRule, clusterHTTPRoute} {
+			if clusterObj != nil && metav1.IsControlledBy(clusterObj, f) {
+				result, errDelete := deleteExposeObject(ctx, m, clusterObj)
+				return nil, result, errDelete
+			}
+		}
+		return nextState(sFnHandleSchedules)
+	}
+
+	var builtObj, clusterObj, staleObj *unstructured.Unstructured
+	switch {
+	case apiRuleInstalled:
+		builtObj = resources.NewAPIRule(f, &m.FunctionConfig).Unstructured
+		clusterObj, staleObj = clusterAPIRule, clusterHTTPRoute
+	case httpRouteInstalled && exposeAuthStrategy(f) == serverlessv1alpha2.ExposeAuthJWT:
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"JWT authentication requires API Gateway, but the APIRule CustomResourceDefinition is not installed")
+		return stop()
+	case httpRouteInstalled:
+		if _, gatewayName := resources.ExposeHTTPRouteGateway(f, &m.FunctionConfig); gatewayName == "" {
+			f.UpdateCondition(
+				serverlessv1alpha2.ConditionRunning,
+				metav1.ConditionFalse,
+				serverlessv1alpha2.ConditionReasonExposeFailed,
+				"Exposing the Function with an HTTPRoute requires a gateway, set spec.expose.gateway or exposeHTTPRouteGateway in the Serverless configuration")
+			return stop()
+		}
+		builtObj = resources.NewHTTPRoute(f, &m.FunctionConfig).Unstructured
+		clusterObj, staleObj = clusterHTTPRoute, clusterAPIRule
+	default:
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"Exposing the Function requires the APIRule or HTTPRoute CustomResourceDefinition to be installed")
+		return stop()
+	}
+
+	if staleObj != nil && metav1.IsControlledBy(staleObj, f) {
+		// the Function was exposed with the other kind before (for example API Gateway has been installed since)
+		result, errDelete := deleteExposeObject(ctx, m, staleObj)
+		return nil, result, errDelete
+	}
+
+	if clusterObj == nil {
+		result, errCreate := createExposeObject(ctx, m, builtObj)
+		if errCreate == nil && apiRuleInstalled {
+			f.Status.URL = resources.ExposeURL(f, &m.FunctionConfig)
+		}
+		return nil, result, errCreate
+	}
+
+	if !metav1.IsControlledBy(clusterObj, f) {
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			fmt.Sprintf("%s %s already exists and is not managed by the Function", clusterObj.GetKind(), clusterObj.GetName()))
+		return stop()
+	}
+
+	requeueNeeded, errUpdate := updateExposeObjectIfNeeded(ctx, m, clusterObj, builtObj)
+	if errUpdate != nil {
+		return stopWithError(errUpdate)
+	}
+	if apiRuleInstalled {
+		f.Status.URL = resources.ExposeURL(f, &m.FunctionConfig)
+	}
+	if requeueNeeded {
+		return requeue()
+	}
+	if !apiRuleInstalled {
+		return publishHTTPRouteURL(m, clusterObj)
+	}
+	return nextState(sFnHandleSchedules)
+}
+
+// publishHTTPRouteURL sets the Function's URL only after a gateway accepted the HTTPRoute
+func publishHTTPRouteURL(m *fsm.StateMachine, httpRoute *unstructured.Unstructured) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+	f.Status.URL = ""
+
+	accepted := httpRouteAcceptedCondition(httpRoute)
+	if accepted == nil {
+		// the gateway hasn't processed the HTTPRoute yet, the URL is published in one of the next reconciliations
+		return nextState(sFnHandleSchedules)
+	}
+	if accepted.Status != metav1.ConditionTrue {
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			fmt.Sprintf("HTTPRoute %s is not accepted by the gateway: %s", httpRoute.GetName(), accepted.Message))
+		return stop()
+	}
+
+	f.Status.URL = resources.ExposeURL(f, &m.FunctionConfig)
+	return nextState(sFnHandleSchedules)
+}
+
+// httpRouteAcceptedCondition returns the Accepted condition reported in the HTTPRoute's status.parents,
+// a positive condition is preferred and nil is returned when no gateway reported it
+func httpRouteAcceptedCondition(httpRoute *unstructured.Unstructured) *metav1.Condition {
+	parents, _, _ := unstructured.NestedSlice(httpRoute.Object, "status", "parents")
+	var result *metav1.Condition
+	for _, parent := range parents {
+		parentMap, ok := parent.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditions, _, _ := unstructured.NestedSlice(parentMap, "conditions")
+		for _, condition := range conditions {
+			conditionMap, ok := condition.(map[string]interface{})
+			if !ok || conditionMap["type"] != httpRouteConditionAccepted {
+				continue
+			}
+			status, _ := conditionMap["status"].(string)
+			message, _ := conditionMap["message"].(string)
+			if status == string(metav1.ConditionTrue) {
+				return &metav1.Condition{Type: httpRouteConditionAccepted, Status: metav1.ConditionTrue, Message: message}
+			}
+			if result == nil {
+				result = &metav1.Condition{Type: httpRouteConditionAccepted, Status: metav1.ConditionStatus(status), Message: message}
+			}
+		}
+	}
+	return result
+}
+
+func exposeAuthStrategy(f *serverlessv1alpha2.Function) serverlessv1alpha2.ExposeAuthStrategy {
+	if f.Spec.Expose.Auth == nil {
+		return serverlessv1alpha2.ExposeAuthNoAuth
+	}
+	return f.Spec.Expose.Auth.Strategy
+}
+
+// getExposeObject returns the object of the given kind named after the Function and information
+// whether the kind is known to the cluster (its CustomResourceDefinition is installed)
+func getExposeObject(ctx context.Context, m *fsm.StateMachine, gvk schema.GroupVersionKind) (*unstructured.Unstructured, bool, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	f := m.State.Function
+	err := m.Client.Get(ctx, client.ObjectKey{
+		Namespace: f.GetNamespace(),
+		Name:      f.GetName(),
+	}, obj)
+
+	if err == nil {
+		return obj, true, nil
+	}
+	if meta.IsNoMatchError(err) {
+		return nil, false, nil
+	}
+	if !errors.IsNotFound(err) {
+		m.Log.Error(err, fmt.Sprintf("unable to fetch %s for Function", gvk.Kind))
+		return nil, true, err
+	}
+	return nil, true, nil
+}
+
+func createExposeObject(ctx context.Context, m *fsm.StateMachine, obj *unstructured.Unstructured) (*ctrl.Result, error) {
+	kind := obj.GetKind()
+	m.Log.Info(fmt.Sprintf("creating a new %s", kind), "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+
+	// Set the ownerRef for the object, ensuring that the object
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, obj, m.Scheme); err != nil {
+		m.Log.Error(err, fmt.Sprintf("failed to set controller reference for new %s", kind), "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			fmt.Sprintf("%s %s create failed: %s", kind, obj.GetName(), err.Error()))
+		return nil, err
+	}
+
+	if err := m.Client.Create(ctx, obj); err != nil {
+		m.Log.Error(err, fmt.Sprintf("failed to create new %s", kind), "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			fmt.Sprintf("%s %s create failed: %s", kind, obj.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonExposeCreated,
+		fmt.Sprintf("%s %s created", kind, obj.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+func updateExposeObjectIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterObj, builtObj *unstructured.Unstructured) (requeueNeeded bool, err error) {
+	if !exposeObjectChanged(clusterObj, builtObj) {
+		return false, nil
+	}
+
+	kind := clusterObj.GetKind()
+	clusterObj.Object["spec"] = builtObj.Object["spec"]
+	clusterObj.SetLabels(builtObj.GetLabels())
+
+	if err := m.Client.Update(ctx, clusterObj); err != nil {
+		m.Log.Error(err, fmt.Sprintf("Failed to update %s", kind), "Namespace", clusterObj.GetNamespace(), "Name", clusterObj.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			fmt.Sprintf("%s %s update failed: %s", kind, clusterObj.GetName(), err.Error()))
+		return false, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonExposeUpdated,
+		fmt.Sprintf("%s %s updated", kind, clusterObj.GetName()))
+	// Requeue the request to ensure the object is updated
+	return true, nil
+}
+
+// httpRouteConditionAccepted is the Gateway API condition reporting whether the gateway attached the route
+const httpRouteConditionAccepted = "Accepted"
+
+// exposeDefaultedKeys are set by the Gateway API server on HTTPRoute's parentRefs (group, kind) and backendRefs (group, kind, weight)
+var exposeDefaultedKeys = map[string]bool{"group": true, "kind": true, "weight": true}
+
+// exposeObjectChanged compares only fields set by the controller, fields defaulted by the API server are ignored
+func exposeObjectChanged(clusterObj, builtObj *unstructured.Unstructured) bool {
+	return !equalIgnoringDefaults(clusterObj.Object["spec"], builtObj.Object["spec"]) ||
+		!mapsEqual(clusterObj.GetLabels(), builtObj.GetLabels())
+}
+
+// equalIgnoringDefaults returns true if cluster equals built, keys from exposeDefaultedKeys are compared only when they are built
+func equalIgnoringDefaults(cluster, built interface{}) bool {
+	switch builtValue := built.(type) {
+	case map[string]interface{}:
+		clusterValue, ok := cluster.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key := range clusterValue {
+			if _, isBuilt := builtValue[key]; !isBuilt && !exposeDefaultedKeys[key] {
+				return false
+			}
+		}
+		for key, value := range builtValue {
+			if !equalIgnoringDefaults(clusterValue[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		clusterValue, ok := cluster.([]interface{})
+		if !ok || len(clusterValue) != len(builtValue) {
+			return false
+		}
+		for i := range builtValue {
+			if !equalIgnoringDefaults(clusterValue[i], builtValue[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(cluster, built)
+	}
+}
+
+func deleteExposeObject(ctx context.Context, m *fsm.StateMachine, obj *unstructured.Unstructured) (*ctrl.Result, error) {
+	kind := obj.GetKind()
+	m.Log.Info(fmt.Sprintf("deleting %s", kind), "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+	if err := m.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		m.Log.Error(err, fmt.Sprintf("Failed to delete %s", kind), "Namespace", obj.GetNamespace(), "Name", obj.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			fmt.Sprintf("%s %s delete failed: %s", kind, obj.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonExposeDeleted,
+		fmt.Sprintf("%s %s deleted", kind, obj.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
@@ -0,0 +1,516 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleExpose(t *testing.T) {
+	exposedFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "keen-knuth-name",
+				Namespace: "loving-lovelace-ns",
+				UID:       "keen-knuth-uid"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Expose: &serverlessv1alpha2.FunctionExpose{
+					Host: "keen-knuth"}}}
+	}
+	fnConfig := config.FunctionConfig{
+		ExposeGateway:          "kyma-system/kyma-gateway",
+		ExposeHTTPRouteGateway: "gateway-system/public-gateway",
+		ExposeDomain:           "example.com",
+	}
+	// kindNotInstalled simulates cluster without CustomResourceDefinitions of the given kinds
+	kindNotInstalled := func(gvks ...schema.GroupVersionKind) interceptor.Funcs {
+		return interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				for _, gvk := range gvks {
+					if obj.GetObjectKind().GroupVersionKind() == gvk {
+						return &meta.NoKindMatchError{}
+					}
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}
+	}
+	t.Run("when expose is not configured and nothing exists should clear url and go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "musing-mirzakhani-name",
+						Namespace: "naughty-napier-ns"},
+					Status: serverlessv1alpha2.FunctionStatus{
+						URL: "https://musing-mirzakhani.example.com"}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSchedules, next)
+		require.Empty(t, m.State.Function.Status.URL)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when api rule does not exist should create it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: exposedFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonExposeCreated,
+			"APIRule keen-knuth-name created")
+		require.Equal(t, "https://keen-knuth.example.com", m.State.Function.Status.URL)
+		appliedAPIRule := &unstructured.Unstructured{}
+		appliedAPIRule.SetGroupVersionKind(resources.APIRuleGVK)
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "keen-knuth-name",
+			Namespace: "loving-lovelace-ns",
+		}, appliedAPIRule)
+		require.NoError(t, getErr)
+		require.True(t, metav1.IsControlledBy(appliedAPIRule, &m.State.Function))
+	})
+	t.Run("when api gateway is not installed should create http route and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(kindNotInstalled(resources.APIRuleGVK)).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: exposedFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonExposeCreated,
+			"HTTPRoute keen-knuth-name created")
+		appliedHTTPRoute := &unstructured.Unstructured{}
+		appliedHTTPRoute.SetGroupVersionKind(resources.HTTPRouteGVK)
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "keen-knuth-name",
+			Namespace: "loving-lovelace-ns",
+		}, appliedHTTPRoute)
+		require.NoError(t, getErr)
+	})
+	t.Run("when only http route is available and jwt is required should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(kindNotInstalled(resources.APIRuleGVK)).Build()
+		f := exposedFunction()
+		f.Spec.Expose.Auth = &serverlessv1alpha2.ExposeAuth{
+			Strategy: serverlessv1alpha2.ExposeAuthJWT,
+			JWT: &serverlessv1alpha2.ExposeJWT{
+				Issuer:  "https://issuer.example.com",
+				JwksUri: "https://issuer.example.com/jwks"}}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"JWT authentication requires API Gateway, but the APIRule CustomResourceDefinition is not installed")
+	})
+	t.Run("when neither api rule nor http route is installed should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).
+			WithInterceptorFuncs(kindNotInstalled(resources.APIRuleGVK, resources.HTTPRouteGVK)).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: exposedFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"Exposing the Function requires the APIRule or HTTPRoute CustomResourceDefinition to be installed")
+	})
+	t.Run("when api rule exists and we need changes should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := exposedFunction()
+		clusterAPIRule := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"hosts": []interface{}{"old-host.example.com"}}}}
+		clusterAPIRule.SetGroupVersionKind(resources.APIRuleGVK)
+		clusterAPIRule.SetName("keen-knuth-name")
+		clusterAPIRule.SetNamespace("loving-lovelace-ns")
+		require.NoError(t, controllerutil.SetControllerReference(&f, clusterAPIRule, scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterAPIRule).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonExposeUpdated,
+			"APIRule keen-knuth-name updated")
+		require.Equal(t, "https://keen-knuth.example.com", m.State.Function.Status.URL)
+		updatedAPIRule := &unstructured.Unstructured{}
+		updatedAPIRule.SetGroupVersionKind(resources.APIRuleGVK)
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(clusterAPIRule), updatedAPIRule))
+		hosts, _, _ := unstructured.NestedStringSlice(updatedAPIRule.Object, "spec", "hosts")
+		require.Equal(t, []string{"keen-knuth.example.com"}, hosts)
+	})
+	t.Run("when http route exists with fields defaulted by gateway api should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := exposedFunction()
+		clusterHTTPRoute := resources.NewHTTPRoute(&f, &fnConfig).Unstructured
+		require.NoError(t, controllerutil.SetControllerReference(&f, clusterHTTPRoute, scheme))
+		parentRefs, _, _ := unstructured.NestedSlice(clusterHTTPRoute.Object, "spec", "parentRefs")
+		parentRefs[0].(map[string]interface{})["group"] = "gateway.networking.k8s.io"
+		parentRefs[0].(map[string]interface{})["kind"] = "Gateway"
+		rules, _, _ := unstructured.NestedSlice(clusterHTTPRoute.Object, "spec", "rules")
+		backendRef := rules[0].(map[string]interface{})["backendRefs"].([]interface{})[0].(map[string]interface{})
+		backendRef["group"] = ""
+		backendRef["kind"] = "Service"
+		backendRef["weight"] = int64(1)
+		updateWasCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterHTTPRoute).WithInterceptorFuncs(interceptor.Funcs{
+			Get: kindNotInstalled(resources.APIRuleGVK).Get,
+			Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updateWasCalled = true
+				return nil
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSchedules, next)
+		require.False(t, updateWasCalled)
+		require.Empty(t, m.State.Function.Status.Conditions)
+		require.Empty(t, m.State.Function.Status.URL)
+	})
+	t.Run("when http route exists with methods which are no longer set should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := exposedFunction()
+		f.Spec.Expose.Methods = []string{"GET"}
+		clusterHTTPRoute := resources.NewHTTPRoute(&f, &fnConfig).Unstructured
+		require.NoError(t, controllerutil.SetControllerReference(&f, clusterHTTPRoute, scheme))
+		f.Spec.Expose.Methods = nil
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterHTTPRoute).WithInterceptorFuncs(kindNotInstalled(resources.APIRuleGVK)).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonExposeUpdated,
+			"HTTPRoute keen-knuth-name updated")
+	})
+	t.Run("when http route gateway is not configured should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(kindNotInstalled(resources.APIRuleGVK)).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: exposedFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme,
+			FunctionConfig: config.FunctionConfig{
+				ExposeGateway: "kyma-system/kyma-gateway",
+				ExposeDomain:  "example.com"}}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"Exposing the Function with an HTTPRoute requires a gateway, set spec.expose.gateway or exposeHTTPRouteGateway in the Serverless configuration")
+	})
+	t.Run("when http route is accepted by the gateway should publish url and go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := exposedFunction()
+		clusterHTTPRoute := resources.NewHTTPRoute(&f, &fnConfig).Unstructured
+		require.NoError(t, controllerutil.SetControllerReference(&f, clusterHTTPRoute, scheme))
+		require.NoError(t, unstructured.SetNestedSlice(clusterHTTPRoute.Object, []interface{}{
+			map[string]interface{}{
+				"parentRef": map[string]interface{}{"name": "public-gateway", "namespace": "gateway-system"},
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Accepted", "status": "True", "reason": "Accepted"},
+					map[string]interface{}{"type": "ResolvedRefs", "status": "True", "reason": "ResolvedRefs"},
+				},
+			},
+		}, "status", "parents"))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterHTTPRoute).WithInterceptorFuncs(kindNotInstalled(resources.APIRuleGVK)).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSchedules, next)
+		require.Equal(t, "https://keen-knuth.example.com", m.State.Function.Status.URL)
+	})
+	t.Run("when http route is not accepted by the gateway should clear url and stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := exposedFunction()
+		f.Status.URL = "https://keen-knuth.example.com"
+		clusterHTTPRoute := resources.NewHTTPRoute(&f, &fnConfig).Unstructured
+		require.NoError(t, controllerutil.SetControllerReference(&f, clusterHTTPRoute, scheme))
+		require.NoError(t, unstructured.SetNestedSlice(clusterHTTPRoute.Object, []interface{}{
+			map[string]interface{}{
+				"parentRef": map[string]interface{}{"name": "public-gateway", "namespace": "gateway-system"},
+				"conditions": []interface{}{
+					map[string]interface{}{"type": "Accepted", "status": "False", "reason": "NotAllowedByListeners", "message": "hostname does not match any listener"},
+				},
+			},
+		}, "status", "parents"))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterHTTPRoute).WithInterceptorFuncs(kindNotInstalled(resources.APIRuleGVK)).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		require.Empty(t, m.State.Function.Status.URL)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"HTTPRoute keen-knuth-name is not accepted by the gateway: hostname does not match any listener")
+	})
+	t.Run("when api rule exists and is not managed by the function should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		clusterAPIRule := &unstructured.Unstructured{Object: map[string]interface{}{}}
+		clusterAPIRule.SetGroupVersionKind(resources.APIRuleGVK)
+		clusterAPIRule.SetName("keen-knuth-name")
+		clusterAPIRule.SetNamespace("loving-lovelace-ns")
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterAPIRule).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: exposedFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonExposeFailed,
+			"APIRule keen-knuth-name already exists and is not managed by the Function")
+	})
+	t.Run("when api gateway has been installed should delete http route and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := exposedFunction()
+		clusterHTTPRoute := resources.NewHTTPRoute(&f, &fnConfig).Unstructured
+		require.NoError(t, controllerutil.SetControllerReference(&f, clusterHTTPRoute, scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterHTTPRoute).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonExposeDeleted,
+			"HTTPRoute keen-knuth-name deleted")
+		deletedHTTPRoute := &unstructured.Unstructured{}
+		deletedHTTPRoute.SetGroupVersionKind(resources.HTTPRouteGVK)
+		getErr := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(clusterHTTPRoute), deletedHTTPRoute)
+		require.True(t, errors.IsNotFound(getErr))
+	})
+	t.Run("when expose has been removed should delete api rule and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := exposedFunction()
+		clusterAPIRule := resources.NewAPIRule(&f, &fnConfig).Unstructured
+		require.NoError(t, controllerutil.SetControllerReference(&f, clusterAPIRule, scheme))
+		f.Spec.Expose = nil
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterAPIRule).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleExpose(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonExposeDeleted,
+			"APIRule keen-knuth-name deleted")
+	})
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"time"
 
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/git"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -20,6 +23,11 @@ func sFnHandleGitSources(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn,
 	}
 
 	gitRepository := m.State.Function.Spec.Source.GitRepository
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.String("git.url", redactGitURL(gitRepository.URL)),
+		attribute.String("git.reference", gitRepository.Reference),
+	)
 
 	if m.State.Function.HasGitAuth() {
 		gitAuth, err := git.NewGitAuth(ctx, m.Client, &m.State.Function)
@@ -40,8 +48,14 @@ func sFnHandleGitSources(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn,
 	result := m.GitChecker.CollectOrder(orderID)
 	if result == nil {
 		// Commit check is still in progress, requeue the reconciliation
+		span.SetAttributes(attribute.Bool("git.lookup.pending", true))
 		return requeueAfter(250 * time.Millisecond)
 	}
+	span.SetAttributes(
+		attribute.Bool("git.lookup.pending", false),
+		attribute.Int64("git.lookup.duration_ms", result.Duration.Milliseconds()),
+		attribute.String("git.commit", result.Commit),
+	)
 
 	if result.Error != nil {
 		m.State.Function.UpdateCondition(
@@ -65,6 +79,17 @@ func sFnHandleGitSources(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn,
 	return nextState(sFnConfigurationReady)
 }
 
+// redactGitURL removes credentials which can be embedded in the repository URL,
+// SSH URLs like git@github.com:org/repo.git can't be parsed and contain only the user name
+func redactGitURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.User = nil
+	return u.String()
+}
+
 func prepareErrorMessage(repoUrl string, err error) string {
 	if errors.Is(err, transport.ErrAuthenticationRequired) {
 		return fmt.Sprintf("Authentication required for Git repository: %s ", repoUrl)
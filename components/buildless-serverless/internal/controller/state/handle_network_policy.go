@@ -0,0 +1,60 @@
+package state
+
+import (
+	"context"
+	"reflect"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func sFnHandleNetworkPolicy(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	return reconcileOwnedObject(ctx, m, ownedObject[*networkingv1.NetworkPolicy]{
+		kind:  "NetworkPolicy",
+		empty: func() *networkingv1.NetworkPolicy { return &networkingv1.NetworkPolicy{} },
+		enabled: func(m *fsm.StateMachine) bool {
+			return resources.NetworkPolicyEnabled(&m.State.Function)
+		},
+		build: func(m *fsm.StateMachine) *networkingv1.NetworkPolicy {
+			return resources.NewNetworkPolicy(&m.State.Function, &m.FunctionConfig).NetworkPolicy
+		},
+		changed: networkPolicyChanged,
+		apply: func(clusterNP, builtNP *networkingv1.NetworkPolicy) {
+			clusterNP.Spec = builtNP.Spec
+		},
+		createdReason: serverlessv1alpha2.ConditionReasonNetworkPolicyCreated,
+		updatedReason: serverlessv1alpha2.ConditionReasonNetworkPolicyUpdated,
+		deletedReason: serverlessv1alpha2.ConditionReasonNetworkPolicyDeleted,
+		failedReason:  serverlessv1alpha2.ConditionReasonNetworkPolicyFailed,
+		next:          sFnHandleExpose,
+	})
+}
+
+func networkPolicyChanged(a, b *networkingv1.NetworkPolicy) bool {
+	return !reflect.DeepEqual(withNetworkPolicyDefaults(a.Spec), withNetworkPolicyDefaults(b.Spec))
+}
+
+// withNetworkPolicyDefaults returns a copy of the spec with ports protocol defaulted by the API server
+func withNetworkPolicyDefaults(spec networkingv1.NetworkPolicySpec) networkingv1.NetworkPolicySpec {
+	spec = *spec.DeepCopy()
+	for i := range spec.Ingress {
+		defaultNetworkPolicyPorts(spec.Ingress[i].Ports)
+	}
+	for i := range spec.Egress {
+		defaultNetworkPolicyPorts(spec.Egress[i].Ports)
+	}
+	return spec
+}
+
+func defaultNetworkPolicyPorts(ports []networkingv1.NetworkPolicyPort) {
+	for i := range ports {
+		if ports[i].Protocol == nil {
+			ports[i].Protocol = ptr.To(corev1.ProtocolTCP)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleNetworkPolicy(t *testing.T) {
+	isolatedFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nifty-noether-name",
+				Namespace: "nervous-nash-ns",
+				UID:       "nifty-noether-uid"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				NetworkPolicy: &serverlessv1alpha2.FunctionNetworkPolicy{}}}
+	}
+	fnConfig := config.FunctionConfig{
+		FunctionTraceCollectorEndpoint: "http://tracing.nervous-nash-ns.svc.cluster.local:4318/v1/traces",
+		PackageRegistryEgressCIDRs:     []string{"0.0.0.0/0"},
+	}
+	t.Run("when network policy is not configured and does not exist should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, networkingv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "naughty-napier-name",
+						Namespace: "nostalgic-neumann-ns"}}},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleNetworkPolicy(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when network policy does not exist should create it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, networkingv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: isolatedFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleNetworkPolicy(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonNetworkPolicyCreated,
+			"NetworkPolicy nifty-noether-name created")
+		appliedNP := &networkingv1.NetworkPolicy{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "nifty-noether-name",
+			Namespace: "nervous-nash-ns",
+		}, appliedNP)
+		require.NoError(t, getErr)
+		require.Len(t, appliedNP.Spec.Egress, 3)
+		require.True(t, metav1.IsControlledBy(appliedNP, &m.State.Function))
+	})
+	t.Run("when network policy exists and we do not need changes should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, networkingv1.AddToScheme(scheme))
+		f := isolatedFunction()
+		f.Spec.NetworkPolicy.Ingress = []networkingv1.NetworkPolicyIngressRule{{
+			// protocol is defaulted by the API server
+			Ports: []networkingv1.NetworkPolicyPort{{Port: ptr.To(intstr.FromInt32(8080))}},
+		}}
+		np := resources.NewNetworkPolicy(&f, &fnConfig).NetworkPolicy
+		np = np.DeepCopy()
+		np.Spec.Ingress[0].Ports[0].Protocol = ptr.To(corev1.ProtocolTCP)
+		require.NoError(t, controllerutil.SetControllerReference(&f, np, scheme))
+		updateWasCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(np).WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updateWasCalled = true
+				return nil
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleNetworkPolicy(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleExpose, next)
+		require.False(t, updateWasCalled)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when network policy exists and we need changes should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, networkingv1.AddToScheme(scheme))
+		f := isolatedFunction()
+		np := resources.NewNetworkPolicy(&f, &fnConfig).NetworkPolicy
+		require.NoError(t, controllerutil.SetControllerReference(&f, np, scheme))
+		f.Spec.NetworkPolicy.Egress = []networkingv1.NetworkPolicyEgressRule{{
+			To: []networkingv1.NetworkPolicyPeer{{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}}},
+		}}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(np).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleNetworkPolicy(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonNetworkPolicyUpdated,
+			"NetworkPolicy nifty-noether-name updated")
+		updatedNP := &networkingv1.NetworkPolicy{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "nifty-noether-name",
+			Namespace: "nervous-nash-ns",
+		}, updatedNP)
+		require.NoError(t, getErr)
+		require.Len(t, updatedNP.Spec.Egress, 4)
+		require.Equal(t, "10.0.0.0/8", updatedNP.Spec.Egress[3].To[0].IPBlock.CIDR)
+	})
+}
@@ -0,0 +1,44 @@
+package state
+
+import (
+	"context"
+	"reflect"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	policyv1 "k8s.io/api/policy/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func sFnHandlePodDisruptionBudget(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	return reconcileOwnedObject(ctx, m, ownedObject[*policyv1.PodDisruptionBudget]{
+		kind:  "PodDisruptionBudget",
+		empty: func() *policyv1.PodDisruptionBudget { return &policyv1.PodDisruptionBudget{} },
+		enabled: func(m *fsm.StateMachine) bool {
+			return resources.PodDisruptionBudgetEnabled(&m.State.Function)
+		},
+		build: func(m *fsm.StateMachine) *policyv1.PodDisruptionBudget {
+			return resources.NewPodDisruptionBudget(&m.State.Function).PodDisruptionBudget
+		},
+		changed:       podDisruptionBudgetChanged,
+		apply:         applyPodDisruptionBudget,
+		createdReason: serverlessv1alpha2.ConditionReasonPodDisruptionBudgetCreated,
+		updatedReason: serverlessv1alpha2.ConditionReasonPodDisruptionBudgetUpdated,
+		deletedReason: serverlessv1alpha2.ConditionReasonPodDisruptionBudgetDeleted,
+		failedReason:  serverlessv1alpha2.ConditionReasonPodDisruptionBudgetFailed,
+		next:          sFnHandleNetworkPolicy,
+	})
+}
+
+func podDisruptionBudgetChanged(a, b *policyv1.PodDisruptionBudget) bool {
+	return !reflect.DeepEqual(a.Spec.Selector, b.Spec.Selector) ||
+		!reflect.DeepEqual(a.Spec.MinAvailable, b.Spec.MinAvailable) ||
+		!reflect.DeepEqual(a.Spec.MaxUnavailable, b.Spec.MaxUnavailable)
+}
+
+func applyPodDisruptionBudget(clusterPDB, builtPDB *policyv1.PodDisruptionBudget) {
+	clusterPDB.Spec.Selector = builtPDB.Spec.Selector
+	clusterPDB.Spec.MinAvailable = builtPDB.Spec.MinAvailable
+	clusterPDB.Spec.MaxUnavailable = builtPDB.Spec.MaxUnavailable
+}
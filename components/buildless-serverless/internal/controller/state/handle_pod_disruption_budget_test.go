@@ -0,0 +1,134 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandlePodDisruptionBudget(t *testing.T) {
+	multiReplicaFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "hopeful-hopper-name",
+				Namespace: "hungry-hawking-ns",
+				UID:       "hopeful-hopper-uid"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Replicas: ptr.To[int32](3)}}
+	}
+	t.Run("when function runs single replica and pdb does not exist should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, policyv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "inspiring-ishizaka-name",
+						Namespace: "intelligent-idris-ns"}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandlePodDisruptionBudget(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleNetworkPolicy, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when pdb does not exist should create it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, policyv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: multiReplicaFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandlePodDisruptionBudget(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonPodDisruptionBudgetCreated,
+			"PodDisruptionBudget hopeful-hopper-name created")
+		appliedPDB := &policyv1.PodDisruptionBudget{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "hopeful-hopper-name",
+			Namespace: "hungry-hawking-ns",
+		}, appliedPDB)
+		require.NoError(t, getErr)
+		require.Equal(t, ptr.To(intstr.FromInt32(1)), appliedPDB.Spec.MaxUnavailable)
+		require.True(t, metav1.IsControlledBy(appliedPDB, &m.State.Function))
+	})
+	t.Run("when pdb exists and we need changes should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, policyv1.AddToScheme(scheme))
+		f := multiReplicaFunction()
+		pdb := resources.NewPodDisruptionBudget(&f).PodDisruptionBudget
+		require.NoError(t, controllerutil.SetControllerReference(&f, pdb, scheme))
+		minAvailable := intstr.FromInt32(2)
+		f.Spec.PodDisruptionBudget = &serverlessv1alpha2.PodDisruptionBudget{
+			MinAvailable: &minAvailable}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(pdb).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandlePodDisruptionBudget(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonPodDisruptionBudgetUpdated,
+			"PodDisruptionBudget hopeful-hopper-name updated")
+		updatedPDB := &policyv1.PodDisruptionBudget{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "hopeful-hopper-name",
+			Namespace: "hungry-hawking-ns",
+		}, updatedPDB)
+		require.NoError(t, getErr)
+		require.Equal(t, &minAvailable, updatedPDB.Spec.MinAvailable)
+		require.Nil(t, updatedPDB.Spec.MaxUnavailable)
+	})
+}
@@ -0,0 +1,43 @@
+package state
+
+import (
+	"context"
+	"reflect"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	rbacv1 "k8s.io/api/rbac/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func sFnHandleRoleBinding(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	return reconcileOwnedObject(ctx, m, ownedObject[*rbacv1.RoleBinding]{
+		kind:  "RoleBinding",
+		empty: func() *rbacv1.RoleBinding { return &rbacv1.RoleBinding{} },
+		enabled: func(m *fsm.StateMachine) bool {
+			// the RoleBinding is built only when the dedicated ServiceAccount references a role
+			return resources.NewServiceAccount(&m.State.Function).RoleBinding != nil
+		},
+		build: func(m *fsm.StateMachine) *rbacv1.RoleBinding {
+			return resources.NewServiceAccount(&m.State.Function).RoleBinding
+		},
+		changed: roleBindingChanged,
+		apply: func(clusterRB, builtRB *rbacv1.RoleBinding) {
+			clusterRB.Subjects = builtRB.Subjects
+		},
+		// roleRef is immutable, the binding is recreated in the next reconciliation
+		recreate: func(clusterRB, builtRB *rbacv1.RoleBinding) bool {
+			return clusterRB.RoleRef != builtRB.RoleRef
+		},
+		createdReason: serverlessv1alpha2.ConditionReasonRoleBindingCreated,
+		updatedReason: serverlessv1alpha2.ConditionReasonRoleBindingUpdated,
+		deletedReason: serverlessv1alpha2.ConditionReasonRoleBindingDeleted,
+		failedReason:  serverlessv1alpha2.ConditionReasonRoleBindingFailed,
+		next:          sFnHandleDeployment,
+	})
+}
+
+func roleBindingChanged(a, b *rbacv1.RoleBinding) bool {
+	return !reflect.DeepEqual(a.Subjects, b.Subjects)
+}
@@ -0,0 +1,169 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	rbacv1 "k8s.io/api/rbac/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleRoleBinding(t *testing.T) {
+	boundFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "practical-pare-name",
+				Namespace: "pedantic-pike-ns",
+				UID:       "practical-pare-uid"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{
+					RoleRef: &serverlessv1alpha2.ServiceAccountRoleRef{
+						Kind: "Role",
+						Name: "practical-pare-role"}}}}
+	}
+	t.Run("when role is not referenced and role binding does not exist should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, rbacv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		f := boundFunction()
+		f.Spec.ServiceAccount.RoleRef = nil
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleRoleBinding(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleDeployment, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when role binding does not exist should create it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, rbacv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: boundFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleRoleBinding(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonRoleBindingCreated,
+			"RoleBinding practical-pare-name created")
+		appliedRB := &rbacv1.RoleBinding{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "practical-pare-name",
+			Namespace: "pedantic-pike-ns",
+		}, appliedRB)
+		require.NoError(t, getErr)
+		require.Equal(t, "practical-pare-role", appliedRB.RoleRef.Name)
+		require.True(t, metav1.IsControlledBy(appliedRB, &m.State.Function))
+	})
+	t.Run("when role binding references another role should delete it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, rbacv1.AddToScheme(scheme))
+		f := boundFunction()
+		rb := resources.NewServiceAccount(&f).RoleBinding
+		require.NoError(t, controllerutil.SetControllerReference(&f, rb, scheme))
+		f.Spec.ServiceAccount.RoleRef.Name = "pensive-payne-role"
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rb).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleRoleBinding(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonRoleBindingDeleted,
+			"RoleBinding practical-pare-name deleted")
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "practical-pare-name",
+			Namespace: "pedantic-pike-ns",
+		}, &rbacv1.RoleBinding{})
+		require.True(t, k8serrors.IsNotFound(getErr))
+	})
+	t.Run("when role binding exists and we need changes should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, rbacv1.AddToScheme(scheme))
+		f := boundFunction()
+		rb := resources.NewServiceAccount(&f).RoleBinding
+		require.NoError(t, controllerutil.SetControllerReference(&f, rb, scheme))
+		rb.Subjects = nil
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(rb).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleRoleBinding(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonRoleBindingUpdated,
+			"RoleBinding practical-pare-name updated")
+		updatedRB := &rbacv1.RoleBinding{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "practical-pare-name",
+			Namespace: "pedantic-pike-ns",
+		}, updatedRB)
+		require.NoError(t, getErr)
+		require.Len(t, updatedRB.Subjects, 1)
+		require.Equal(t, "practical-pare-name", updatedRB.Subjects[0].Name)
+	})
+}
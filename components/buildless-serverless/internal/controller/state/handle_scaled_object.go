@@ -0,0 +1,48 @@
+package state
+
+import (
+	"context"
+	"reflect"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func sFnHandleScaledObject(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	return reconcileOwnedObject(ctx, m, ownedObject[*unstructured.Unstructured]{
+		kind: "ScaledObject",
+		empty: func() *unstructured.Unstructured {
+			scaledObject := &unstructured.Unstructured{}
+			scaledObject.SetGroupVersionKind(resources.ScaledObjectGVK)
+			return scaledObject
+		},
+		enabled: func(m *fsm.StateMachine) bool {
+			return m.State.Function.HasEventScaling()
+		},
+		build: func(m *fsm.StateMachine) *unstructured.Unstructured {
+			return resources.NewScaledObject(&m.State.Function).Unstructured
+		},
+		changed: scaledObjectChanged,
+		apply: func(clusterScaledObject, builtScaledObject *unstructured.Unstructured) {
+			clusterScaledObject.Object["spec"] = builtScaledObject.Object["spec"]
+		},
+		notInstalledMessage: "Event scaling requires KEDA, but the ScaledObject CustomResourceDefinition is not installed",
+		createdReason:       serverlessv1alpha2.ConditionReasonScaledObjectCreated,
+		updatedReason:       serverlessv1alpha2.ConditionReasonScaledObjectUpdated,
+		deletedReason:       serverlessv1alpha2.ConditionReasonScaledObjectDeleted,
+		failedReason:        serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+		next:                sFnDeploymentStatus,
+	})
+}
+
+// scaledObjectChanged compares only fields set by the controller, KEDA defaults other fields of the ScaledObject.
+// Triggers are not defaulted, they are compared exactly so values removed from the event scaling are removed from the ScaledObject
+func scaledObjectChanged(clusterScaledObject, builtScaledObject *unstructured.Unstructured) bool {
+	clusterTriggers, _, _ := unstructured.NestedFieldNoCopy(clusterScaledObject.Object, "spec", "triggers")
+	builtTriggers, _, _ := unstructured.NestedFieldNoCopy(builtScaledObject.Object, "spec", "triggers")
+	return !reflect.DeepEqual(clusterTriggers, builtTriggers) ||
+		specChanged(clusterScaledObject, builtScaledObject, "minReplicaCount", "maxReplicaCount", "cooldownPeriod")
+}
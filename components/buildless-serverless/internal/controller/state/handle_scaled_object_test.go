@@ -0,0 +1,275 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleScaledObject(t *testing.T) {
+	eventScalingFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "dreamy-dijkstra-name",
+				Namespace: "eager-euler-ns",
+				UID:       "dreamy-dijkstra-uid"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				EventScaling: &serverlessv1alpha2.EventScaling{
+					NatsJetStream: &serverlessv1alpha2.NatsJetStreamEventSource{
+						MonitoringEndpoint: "elastic-elion-endpoint:8222",
+						Stream:             "elegant-engelbart-stream",
+						Consumer:           "epic-easley-consumer"}}}}
+	}
+	t.Run("when event scaling is not configured and scaled object does not exist should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "festive-fermat-name",
+						Namespace: "funny-feynman-ns"}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnDeploymentStatus, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when event scaling is not configured and keda is not installed should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return &meta.NoKindMatchError{}
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "gifted-goldberg-name",
+						Namespace: "gracious-galois-ns"}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnDeploymentStatus, next)
+	})
+	t.Run("when event scaling is configured and keda is not installed should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return &meta.NoKindMatchError{}
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: eventScalingFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScaledObjectFailed,
+			"Event scaling requires KEDA, but the ScaledObject CustomResourceDefinition is not installed")
+	})
+	t.Run("when scaled object does not exist should create it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: eventScalingFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonScaledObjectCreated,
+			"ScaledObject dreamy-dijkstra-name created")
+		// scaled object has been applied to k8s
+		appliedScaledObject := &unstructured.Unstructured{}
+		appliedScaledObject.SetGroupVersionKind(resources.ScaledObjectGVK)
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "dreamy-dijkstra-name",
+			Namespace: "eager-euler-ns",
+		}, appliedScaledObject)
+		require.NoError(t, getErr)
+		require.True(t, metav1.IsControlledBy(appliedScaledObject, &m.State.Function))
+		scaleTargetName, _, _ := unstructured.NestedString(appliedScaledObject.Object, "spec", "scaleTargetRef", "name")
+		require.Equal(t, "dreamy-dijkstra-name", scaleTargetName)
+	})
+	t.Run("when scaled object exists and we need changes should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := eventScalingFunction()
+		clusterScaledObject := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{}}}
+		clusterScaledObject.SetGroupVersionKind(resources.ScaledObjectGVK)
+		clusterScaledObject.SetName("dreamy-dijkstra-name")
+		clusterScaledObject.SetNamespace("eager-euler-ns")
+		require.NoError(t, controllerutil.SetControllerReference(&f, clusterScaledObject, scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterScaledObject).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonScaledObjectUpdated,
+			"ScaledObject dreamy-dijkstra-name updated")
+		updatedScaledObject := &unstructured.Unstructured{}
+		updatedScaledObject.SetGroupVersionKind(resources.ScaledObjectGVK)
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "dreamy-dijkstra-name",
+			Namespace: "eager-euler-ns",
+		}, updatedScaledObject)
+		require.NoError(t, getErr)
+		triggers, _, _ := unstructured.NestedSlice(updatedScaledObject.Object, "spec", "triggers")
+		require.Len(t, triggers, 1)
+	})
+	t.Run("when scaled object exists with fields defaulted by keda should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := eventScalingFunction()
+		clusterScaledObject := resources.NewScaledObject(&f).Unstructured
+		require.NoError(t, unstructured.SetNestedField(clusterScaledObject.Object, int64(30), "spec", "pollingInterval"))
+		require.NoError(t, controllerutil.SetControllerReference(&f, clusterScaledObject, scheme))
+		updateCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterScaledObject).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					updateCalled = true
+					return client.Update(ctx, obj, opts...)
+				},
+			}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleScaledObject(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnDeploymentStatus, next)
+		require.False(t, updateCalled)
+	})
+	t.Run("when optional field is removed from event scaling should update scaled object", func(t *testing.T) {
+		// Arrange
+		f := eventScalingFunction()
+		clusterScaledObject := resources.NewScaledObject(&f).Unstructured
+		require.NoError(t, unstructured.SetNestedField(clusterScaledObject.Object, int64(3), "spec", "minReplicaCount"))
+
+		// Act
+		changed := scaledObjectChanged(clusterScaledObject, resources.NewScaledObject(&f).Unstructured)
+
+		// Assert
+		require.True(t, changed)
+	})
+	t.Run("when authentication ref is removed from event scaling should update scaled object", func(t *testing.T) {
+		// Arrange
+		f := eventScalingFunction()
+		f.Spec.EventScaling.AuthenticationRef = "elated-edison-auth"
+		clusterScaledObject := resources.NewScaledObject(&f).Unstructured
+		f.Spec.EventScaling.AuthenticationRef = ""
+
+		// Act
+		changed := scaledObjectChanged(clusterScaledObject, resources.NewScaledObject(&f).Unstructured)
+
+		// Assert
+		require.True(t, changed)
+	})
+	t.Run("when trigger metadata is removed from event scaling should update scaled object", func(t *testing.T) {
+		// Arrange
+		f := eventScalingFunction()
+		clusterScaledObject := resources.NewScaledObject(&f).Unstructured
+		require.NoError(t, unstructured.SetNestedField(clusterScaledObject.Object,
+			[]interface{}{map[string]interface{}{
+				"type": "nats-jetstream",
+				"metadata": map[string]interface{}{
+					"natsServerMonitoringEndpoint": "elastic-elion-endpoint:8222",
+					"account":                      "$G",
+					"stream":                       "elegant-engelbart-stream",
+					"consumer":                     "epic-easley-consumer",
+					"lagThreshold":                 "10",
+					"activationLagThreshold":       "5"}}},
+			"spec", "triggers"))
+
+		// Act
+		changed := scaledObjectChanged(clusterScaledObject, resources.NewScaledObject(&f).Unstructured)
+
+		// Assert
+		require.True(t, changed)
+	})
+}
@@ -0,0 +1,262 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func sFnHandleSchedules(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	clusterCronJobs, errList := listScheduleCronJobs(ctx, m)
+	if errList != nil {
+		return stopWithError(errList)
+	}
+
+	builtCronJobs := resources.NewCronJobs(&m.State.Function, &m.FunctionConfig)
+
+	requeueNeeded := false
+	for _, clusterCronJob := range clusterCronJobs {
+		if findCronJob(builtCronJobs, clusterCronJob.GetName()) != nil {
+			continue
+		}
+		if errDelete := deleteScheduleCronJob(ctx, m, clusterCronJob); errDelete != nil {
+			return stopWithError(errDelete)
+		}
+		requeueNeeded = true
+	}
+
+	for _, builtCronJob := range builtCronJobs {
+		clusterCronJob := findCronJob(clusterCronJobs, builtCronJob.GetName())
+		if clusterCronJob == nil {
+			// the name may be taken by a CronJob not listed for the Function
+			existingCronJob, errGet := getScheduleCronJob(ctx, m, builtCronJob.GetName())
+			if errGet != nil {
+				return stopWithError(errGet)
+			}
+			if existingCronJob != nil && !metav1.IsControlledBy(existingCronJob, &m.State.Function) {
+				m.State.Function.UpdateCondition(
+					serverlessv1alpha2.ConditionRunning,
+					metav1.ConditionFalse,
+					serverlessv1alpha2.ConditionReasonScheduleFailed,
+					fmt.Sprintf("CronJob %s already exists and is not managed by the Function", existingCronJob.GetName()))
+				return stop()
+			}
+			clusterCronJob = existingCronJob
+		}
+		if clusterCronJob == nil {
+			if errCreate := createScheduleCronJob(ctx, m, builtCronJob); errCreate != nil {
+				return stopWithError(errCreate)
+			}
+			requeueNeeded = true
+			continue
+		}
+
+		updated, errUpdate := updateScheduleCronJobIfNeeded(ctx, m, clusterCronJob, builtCronJob)
+		if errUpdate != nil {
+			return stopWithError(errUpdate)
+		}
+		requeueNeeded = requeueNeeded || updated
+	}
+
+	m.State.Function.Status.Schedules = schedulesStatus(&m.State.Function, clusterCronJobs)
+	if requeueNeeded {
+		return requeue()
+	}
+	return nextState(sFnHandleSubscriptions)
+}
+
+// listScheduleCronJobs returns CronJobs created for the Function's schedules
+func listScheduleCronJobs(ctx context.Context, m *fsm.StateMachine) ([]*batchv1.CronJob, error) {
+	f := &m.State.Function
+	cronJobList := &batchv1.CronJobList{}
+	err := m.Client.List(ctx, cronJobList,
+		client.InNamespace(f.GetNamespace()),
+		client.MatchingLabels(resources.ScheduleLabels(f)),
+	)
+	if err != nil {
+		m.Log.Error(err, "unable to list CronJobs for Function")
+		return nil, err
+	}
+
+	cronJobs := []*batchv1.CronJob{}
+	for i := range cronJobList.Items {
+		if metav1.IsControlledBy(&cronJobList.Items[i], f) {
+			cronJobs = append(cronJobs, &cronJobList.Items[i])
+		}
+	}
+	return cronJobs, nil
+}
+
+// getScheduleCronJob returns the CronJob with the given name or nil when it doesn't exist
+func getScheduleCronJob(ctx context.Context, m *fsm.StateMachine, name string) (*batchv1.CronJob, error) {
+	cronJob := &batchv1.CronJob{}
+	err := m.Client.Get(ctx, client.ObjectKey{
+		Namespace: m.State.Function.GetNamespace(),
+		Name:      name,
+	}, cronJob)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch CronJob for Function")
+		return nil, err
+	}
+	return cronJob, nil
+}
+
+func findCronJob(cronJobs []*batchv1.CronJob, name string) *batchv1.CronJob {
+	for _, cronJob := range cronJobs {
+		if cronJob.GetName() == name {
+			return cronJob
+		}
+	}
+	return nil
+}
+
+func createScheduleCronJob(ctx context.Context, m *fsm.StateMachine, cronJob *batchv1.CronJob) error {
+	m.Log.Info("creating a new CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+
+	// Set the ownerRef for the CronJob, ensuring that the CronJob
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, cronJob, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScheduleFailed,
+			fmt.Sprintf("CronJob %s create failed: %s", cronJob.GetName(), err.Error()))
+		return err
+	}
+
+	if err := m.Client.Create(ctx, cronJob); err != nil {
+		m.Log.Error(err, "failed to create new CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScheduleFailed,
+			fmt.Sprintf("CronJob %s create failed: %s", cronJob.GetName(), err.Error()))
+		return err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonScheduleCreated,
+		fmt.Sprintf("CronJob %s created", cronJob.GetName()))
+
+	return nil
+}
+
+func updateScheduleCronJobIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterCronJob, builtCronJob *batchv1.CronJob) (requeueNeeded bool, err error) {
+	if !cronJobChanged(clusterCronJob, builtCronJob) {
+		return false, nil
+	}
+
+	clusterCronJob.Spec.Schedule = builtCronJob.Spec.Schedule
+	clusterCronJob.Spec.TimeZone = builtCronJob.Spec.TimeZone
+	clusterCronJob.Spec.Suspend = builtCronJob.Spec.Suspend
+	clusterCronJob.Spec.ConcurrencyPolicy = builtCronJob.Spec.ConcurrencyPolicy
+	clusterCronJob.Spec.JobTemplate = builtCronJob.Spec.JobTemplate
+	clusterCronJob.SetLabels(builtCronJob.GetLabels())
+
+	if err := m.Client.Update(ctx, clusterCronJob); err != nil {
+		m.Log.Error(err, "Failed to update CronJob", "CronJob.Namespace", clusterCronJob.GetNamespace(), "CronJob.Name", clusterCronJob.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScheduleFailed,
+			fmt.Sprintf("CronJob %s update failed: %s", clusterCronJob.GetName(), err.Error()))
+		return false, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonScheduleUpdated,
+		fmt.Sprintf("CronJob %s updated", clusterCronJob.GetName()))
+	// Requeue the request to ensure the CronJob is updated
+	return true, nil
+}
+
+func cronJobChanged(a, b *batchv1.CronJob) bool {
+	aContainers := a.Spec.JobTemplate.Spec.Template.Spec.Containers
+	bContainers := b.Spec.JobTemplate.Spec.Template.Spec.Containers
+	if len(aContainers) != 1 || len(bContainers) != 1 {
+		return true
+	}
+
+	scheduleChanged := a.Spec.Schedule != b.Spec.Schedule ||
+		!reflect.DeepEqual(a.Spec.TimeZone, b.Spec.TimeZone) ||
+		!reflect.DeepEqual(a.Spec.Suspend, b.Spec.Suspend) ||
+		a.Spec.ConcurrencyPolicy != b.Spec.ConcurrencyPolicy
+	imageChanged := aContainers[0].Image != bContainers[0].Image
+	commandChanged := !reflect.DeepEqual(aContainers[0].Command, bContainers[0].Command) ||
+		!reflect.DeepEqual(aContainers[0].Args, bContainers[0].Args)
+	podLabelsChanged := !mapsEqual(a.Spec.JobTemplate.Spec.Template.GetLabels(), b.Spec.JobTemplate.Spec.Template.GetLabels())
+
+	return scheduleChanged ||
+		imageChanged ||
+		commandChanged ||
+		podLabelsChanged ||
+		!mapsEqual(a.GetLabels(), b.GetLabels())
+}
+
+func deleteScheduleCronJob(ctx context.Context, m *fsm.StateMachine, cronJob *batchv1.CronJob) error {
+	m.Log.Info("deleting CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+	// remove Jobs and Pods of the removed schedule together with the CronJob
+	err := m.Client.Delete(ctx, cronJob, client.PropagationPolicy(metav1.DeletePropagationBackground))
+	if client.IgnoreNotFound(err) != nil {
+		m.Log.Error(err, "Failed to delete CronJob", "CronJob.Namespace", cronJob.GetNamespace(), "CronJob.Name", cronJob.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScheduleFailed,
+			fmt.Sprintf("CronJob %s delete failed: %s", cronJob.GetName(), err.Error()))
+		return err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonScheduleDeleted,
+		fmt.Sprintf("CronJob %s deleted", cronJob.GetName()))
+
+	return nil
+}
+
+// schedulesStatus returns results of the schedules based on the status of their CronJobs
+func schedulesStatus(f *serverlessv1alpha2.Function, cronJobs []*batchv1.CronJob) []serverlessv1alpha2.ScheduleStatus {
+	var result []serverlessv1alpha2.ScheduleStatus
+	for _, schedule := range f.Spec.Schedules {
+		status := serverlessv1alpha2.ScheduleStatus{Name: schedule.Name}
+		if cronJob := findCronJob(cronJobs, resources.ScheduleCronJobName(f, schedule.Name)); cronJob != nil {
+			status.LastScheduleTime = cronJob.Status.LastScheduleTime
+			status.LastSuccessfulTime = cronJob.Status.LastSuccessfulTime
+			status.LastResult = scheduleResult(cronJob)
+		}
+		result = append(result, status)
+	}
+	return result
+}
+
+func scheduleResult(cronJob *batchv1.CronJob) serverlessv1alpha2.ScheduleResult {
+	switch {
+	case len(cronJob.Status.Active) > 0:
+		return serverlessv1alpha2.ScheduleResultRunning
+	case cronJob.Status.LastScheduleTime == nil:
+		return ""
+	case cronJob.Status.LastSuccessfulTime != nil &&
+		!cronJob.Status.LastSuccessfulTime.Before(cronJob.Status.LastScheduleTime):
+		return serverlessv1alpha2.ScheduleResultSucceeded
+	default:
+		// the last scheduled Job finished without success
+		return serverlessv1alpha2.ScheduleResultFailed
+	}
+}
@@ -0,0 +1,376 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleSchedules(t *testing.T) {
+	scheduledFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "serene-sammet-name",
+				Namespace: "stoic-shannon-ns",
+				UID:       "serene-sammet-uid"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Schedules: []serverlessv1alpha2.FunctionSchedule{
+					{Name: "nightly", Schedule: "0 2 * * *"}}}}
+	}
+	fnConfig := config.FunctionConfig{
+		Images: config.ImagesConfig{RepoFetcher: "sleepy-swanson-image"},
+	}
+	ownedCronJob := func(t *testing.T, scheme *runtime.Scheme, f *serverlessv1alpha2.Function, schedule serverlessv1alpha2.FunctionSchedule) *batchv1.CronJob {
+		cj := resources.NewCronJob(f, &fnConfig, schedule).CronJob
+		require.NoError(t, controllerutil.SetControllerReference(f, cj, scheme))
+		return cj
+	}
+	t.Run("when schedules are not configured should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, batchv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "suspicious-stonebraker-name",
+						Namespace: "stoic-shannon-ns"}}},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSubscriptions, next)
+		require.Empty(t, m.State.Function.Status.Schedules)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when cron job does not exist should create it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, batchv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: scheduledFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonScheduleCreated,
+			"CronJob serene-sammet-name-nightly created")
+		require.Equal(t, []serverlessv1alpha2.ScheduleStatus{{Name: "nightly"}}, m.State.Function.Status.Schedules)
+		appliedCronJob := &batchv1.CronJob{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "serene-sammet-name-nightly",
+			Namespace: "stoic-shannon-ns",
+		}, appliedCronJob)
+		require.NoError(t, getErr)
+		require.Equal(t, "0 2 * * *", appliedCronJob.Spec.Schedule)
+		require.True(t, metav1.IsControlledBy(appliedCronJob, &m.State.Function))
+	})
+	t.Run("when cron job exists and we do not need changes should record the last result and go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, batchv1.AddToScheme(scheme))
+		f := scheduledFunction()
+		cj := ownedCronJob(t, scheme, &f, f.Spec.Schedules[0])
+		lastSchedule := metav1.NewTime(time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC))
+		lastSuccess := metav1.NewTime(time.Date(2025, 1, 1, 2, 0, 5, 0, time.UTC))
+		cj.Status = batchv1.CronJobStatus{
+			LastScheduleTime:   &lastSchedule,
+			LastSuccessfulTime: &lastSuccess,
+		}
+		updateWasCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cj).WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updateWasCalled = true
+				return nil
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleSubscriptions, next)
+		require.False(t, updateWasCalled)
+		require.Empty(t, m.State.Function.Status.Conditions)
+		require.Len(t, m.State.Function.Status.Schedules, 1)
+		scheduleStatus := m.State.Function.Status.Schedules[0]
+		require.Equal(t, "nightly", scheduleStatus.Name)
+		require.Equal(t, serverlessv1alpha2.ScheduleResultSucceeded, scheduleStatus.LastResult)
+		require.True(t, lastSchedule.Equal(scheduleStatus.LastScheduleTime))
+		require.True(t, lastSuccess.Equal(scheduleStatus.LastSuccessfulTime))
+	})
+	t.Run("when cron job exists and we need changes should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, batchv1.AddToScheme(scheme))
+		f := scheduledFunction()
+		cj := ownedCronJob(t, scheme, &f, f.Spec.Schedules[0])
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cj).Build()
+		f.Spec.Schedules[0].Schedule = "*/30 * * * *"
+		f.Spec.Schedules[0].Payload = `{"sync":true}`
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonScheduleUpdated,
+			"CronJob serene-sammet-name-nightly updated")
+		updatedCronJob := &batchv1.CronJob{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "serene-sammet-name-nightly",
+			Namespace: "stoic-shannon-ns",
+		}, updatedCronJob)
+		require.NoError(t, getErr)
+		require.Equal(t, "*/30 * * * *", updatedCronJob.Spec.Schedule)
+		require.Contains(t, updatedCronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Args, `{"sync":true}`)
+	})
+	t.Run("when schedule has been removed should delete its cron job and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, batchv1.AddToScheme(scheme))
+		f := scheduledFunction()
+		nightlyCronJob := ownedCronJob(t, scheme, &f, f.Spec.Schedules[0])
+		hourlyCronJob := ownedCronJob(t, scheme, &f, serverlessv1alpha2.FunctionSchedule{Name: "hourly", Schedule: "@hourly"})
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(nightlyCronJob, hourlyCronJob).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonScheduleDeleted,
+			"CronJob serene-sammet-name-hourly deleted")
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "serene-sammet-name-hourly",
+			Namespace: "stoic-shannon-ns",
+		}, &batchv1.CronJob{})
+		require.True(t, k8serrors.IsNotFound(getErr))
+		getErr = k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "serene-sammet-name-nightly",
+			Namespace: "stoic-shannon-ns",
+		}, &batchv1.CronJob{})
+		require.NoError(t, getErr)
+	})
+	t.Run("when cron job with the same name is not managed by the function should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, batchv1.AddToScheme(scheme))
+		// the CronJob of the "nightly" schedule of the "serene-sammet" Function is named the same
+		otherFunction := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "serene",
+				Namespace: "stoic-shannon-ns",
+				UID:       "serene-uid"}}
+		foreignCronJob := ownedCronJob(t, scheme, &otherFunction, serverlessv1alpha2.FunctionSchedule{Name: "sammet-name-nightly", Schedule: "@hourly"})
+		createWasCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreignCronJob).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				createWasCalled = true
+				return nil
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: scheduledFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		require.False(t, createWasCalled)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScheduleFailed,
+			"CronJob serene-sammet-name-nightly already exists and is not managed by the Function")
+	})
+	t.Run("when cron job can't be created should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, batchv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				return errors.New("strange-sinoussi-error")
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: scheduledFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), &m)
+
+		// Assert
+		require.NotNil(t, err)
+		require.ErrorContains(t, err, "strange-sinoussi-error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonScheduleFailed,
+			"CronJob serene-sammet-name-nightly create failed: strange-sinoussi-error")
+	})
+	t.Run("when cannot list cron jobs from kubernetes should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, batchv1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, client client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				return errors.New("silly-sutherland-error")
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: scheduledFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSchedules(context.Background(), &m)
+
+		// Assert
+		require.NotNil(t, err)
+		require.ErrorContains(t, err, "silly-sutherland-error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+	})
+}
+
+func Test_scheduleResult(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2025, 1, 1, 1, 0, 0, 0, time.UTC))
+	later := metav1.NewTime(time.Date(2025, 1, 1, 2, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name   string
+		status batchv1.CronJobStatus
+		want   serverlessv1alpha2.ScheduleResult
+	}{
+		{
+			name:   "never scheduled",
+			status: batchv1.CronJobStatus{},
+			want:   "",
+		},
+		{
+			name:   "job is running",
+			status: batchv1.CronJobStatus{Active: []corev1.ObjectReference{{Name: "job"}}, LastScheduleTime: &later},
+			want:   serverlessv1alpha2.ScheduleResultRunning,
+		},
+		{
+			name:   "last job succeeded",
+			status: batchv1.CronJobStatus{LastScheduleTime: &earlier, LastSuccessfulTime: &later},
+			want:   serverlessv1alpha2.ScheduleResultSucceeded,
+		},
+		{
+			name:   "last job failed after previous success",
+			status: batchv1.CronJobStatus{LastScheduleTime: &later, LastSuccessfulTime: &earlier},
+			want:   serverlessv1alpha2.ScheduleResultFailed,
+		},
+		{
+			name:   "job never succeeded",
+			status: batchv1.CronJobStatus{LastScheduleTime: &later},
+			want:   serverlessv1alpha2.ScheduleResultFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, scheduleResult(&batchv1.CronJob{Status: tt.status}))
+		})
+	}
+}
@@ -16,25 +16,38 @@ import (
 )
 
 func sFnHandleService(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
-	builtService := resources.NewService(&m.State.Function).Service
-
 	clusterService, errGet := getService(ctx, m)
 	if errGet != nil {
 		return stopWithError(errGet)
 	}
 	if clusterService == nil {
+		builtService := resources.NewService(&m.State.Function).Service
 		result, errCreate := createService(ctx, m, builtService)
+		if errCreate == nil {
+			m.State.Function.CopyServiceAnnotationsToStatus()
+		}
 		return nil, result, errCreate
 	}
 
+	builtService := resources.NewService(&m.State.Function,
+		resources.ServiceCurrentAnnotations(clusterService.GetAnnotations()),
+	).Service
+
+	if isHeadlessService(clusterService) != isHeadlessService(builtService) {
+		// clusterIP is immutable, the service is recreated in the next reconciliation
+		result, errDelete := deleteService(ctx, m, clusterService)
+		return nil, result, errDelete
+	}
+
 	requeueNeeded, errUpdate := updateServiceIfNeeded(ctx, m, clusterService, builtService)
 	if errUpdate != nil {
 		return stopWithError(errUpdate)
 	}
+	m.State.Function.CopyServiceAnnotationsToStatus()
 	if requeueNeeded {
 		return requeue()
 	}
-	return nextState(sFnDeploymentStatus)
+	return nextState(sFnHandlePodDisruptionBudget)
 }
 
 func getService(ctx context.Context, m *fsm.StateMachine) (*corev1.Service, error) {
@@ -95,19 +108,77 @@ func updateServiceIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterServ
 	}
 
 	// manually change fields that interest us, as clusterIP is immutable
-	clusterService.Spec.Ports = builtService.Spec.Ports
+	clusterService.Spec.Ports = withNodePorts(builtService.Spec.Ports, clusterService.Spec.Ports, builtService.Spec.Type)
 	clusterService.Spec.Selector = builtService.Spec.Selector
 	clusterService.Spec.Type = builtService.Spec.Type
+	clusterService.Spec.SessionAffinity = builtService.Spec.SessionAffinity
+	if serviceSessionAffinity(builtService) == corev1.ServiceAffinityNone {
+		// session affinity config is allowed only for the ClientIP affinity
+		clusterService.Spec.SessionAffinityConfig = nil
+	}
 	clusterService.ObjectMeta.Labels = builtService.GetLabels()
+	clusterService.ObjectMeta.Annotations = builtService.GetAnnotations()
 	return updateService(ctx, m, clusterService)
 }
 
 func serviceChanged(a *corev1.Service, b *corev1.Service) bool {
 	return !mapsEqual(a.Spec.Selector, b.Spec.Selector) ||
 		!mapsEqual(a.Labels, b.Labels) ||
-		len(a.Spec.Ports) != 1 ||
-		len(b.Spec.Ports) != 1 ||
-		a.Spec.Ports[0].String() != b.Spec.Ports[0].String()
+		!mapsEqual(a.Annotations, b.Annotations) ||
+		serviceType(a) != serviceType(b) ||
+		serviceSessionAffinity(a) != serviceSessionAffinity(b) ||
+		!servicePortsEqual(a.Spec.Ports, b.Spec.Ports)
+}
+
+// servicePortsEqual compares ports ignoring node ports allocated by kubernetes
+func servicePortsEqual(a, b []corev1.ServicePort) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name ||
+			a[i].Port != b[i].Port ||
+			a[i].TargetPort != b[i].TargetPort ||
+			a[i].Protocol != b[i].Protocol {
+			return false
+		}
+	}
+	return true
+}
+
+// withNodePorts keeps node ports already allocated for the ports, so they don't change on every update
+func withNodePorts(builtPorts, clusterPorts []corev1.ServicePort, svcType corev1.ServiceType) []corev1.ServicePort {
+	if svcType != corev1.ServiceTypeNodePort && svcType != corev1.ServiceTypeLoadBalancer {
+		return builtPorts
+	}
+	nodePorts := map[string]int32{}
+	for _, port := range clusterPorts {
+		nodePorts[port.Name] = port.NodePort
+	}
+	result := make([]corev1.ServicePort, 0, len(builtPorts))
+	for _, port := range builtPorts {
+		port.NodePort = nodePorts[port.Name]
+		result = append(result, port)
+	}
+	return result
+}
+
+func serviceType(s *corev1.Service) corev1.ServiceType {
+	if s.Spec.Type == "" {
+		return corev1.ServiceTypeClusterIP
+	}
+	return s.Spec.Type
+}
+
+func serviceSessionAffinity(s *corev1.Service) corev1.ServiceAffinity {
+	if s.Spec.SessionAffinity == "" {
+		return corev1.ServiceAffinityNone
+	}
+	return s.Spec.SessionAffinity
+}
+
+func isHeadlessService(s *corev1.Service) bool {
+	return s.Spec.ClusterIP == corev1.ClusterIPNone
 }
 
 func updateService(ctx context.Context, m *fsm.StateMachine, clusterService *corev1.Service) (requeueNeeded bool, err error) {
@@ -128,3 +199,23 @@ func updateService(ctx context.Context, m *fsm.StateMachine, clusterService *cor
 	// Requeue the request to ensure the Deployment is updated
 	return true, nil
 }
+
+func deleteService(ctx context.Context, m *fsm.StateMachine, service *corev1.Service) (*ctrl.Result, error) {
+	m.Log.Info("deleting Service", "Service.Namespace", service.GetNamespace(), "Service.Name", service.GetName())
+	if err := m.Client.Delete(ctx, service); client.IgnoreNotFound(err) != nil {
+		m.Log.Error(err, "Failed to delete Service", "Service.Namespace", service.GetNamespace(), "Service.Name", service.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonServiceFailed,
+			fmt.Sprintf("Service %s delete failed: %s", service.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonServiceDeleted,
+		fmt.Sprintf("Service %s deleted", service.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
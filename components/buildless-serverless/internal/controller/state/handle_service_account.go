@@ -0,0 +1,30 @@
+package state
+
+import (
+	"context"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func sFnHandleServiceAccount(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	// only labels of the ServiceAccount are managed, tokens and image pull secrets are added by the cluster
+	return reconcileOwnedObject(ctx, m, ownedObject[*corev1.ServiceAccount]{
+		kind:  "ServiceAccount",
+		empty: func() *corev1.ServiceAccount { return &corev1.ServiceAccount{} },
+		enabled: func(m *fsm.StateMachine) bool {
+			return resources.DedicatedServiceAccountEnabled(&m.State.Function)
+		},
+		build: func(m *fsm.StateMachine) *corev1.ServiceAccount {
+			return resources.NewServiceAccount(&m.State.Function).ServiceAccount
+		},
+		createdReason: serverlessv1alpha2.ConditionReasonServiceAccountCreated,
+		updatedReason: serverlessv1alpha2.ConditionReasonServiceAccountUpdated,
+		deletedReason: serverlessv1alpha2.ConditionReasonServiceAccountDeleted,
+		failedReason:  serverlessv1alpha2.ConditionReasonServiceAccountFailed,
+		next:          sFnHandleRoleBinding,
+	})
+}
@@ -0,0 +1,130 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleServiceAccount(t *testing.T) {
+	dedicatedSAFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kind-kowalevski-name",
+				Namespace: "keen-kepler-ns",
+				UID:       "kind-kowalevski-uid"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{}}}
+	}
+	t.Run("when dedicated service account is not configured should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, corev1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "modest-mayer-name",
+						Namespace: "magical-meitner-ns"},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						ServiceAccountName: "modest-mayer-sa"}}},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleServiceAccount(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleRoleBinding, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when service account does not exist should create it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, corev1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: dedicatedSAFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleServiceAccount(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonServiceAccountCreated,
+			"ServiceAccount kind-kowalevski-name created")
+		appliedSA := &corev1.ServiceAccount{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "kind-kowalevski-name",
+			Namespace: "keen-kepler-ns",
+		}, appliedSA)
+		require.NoError(t, getErr)
+		require.True(t, metav1.IsControlledBy(appliedSA, &m.State.Function))
+	})
+	t.Run("when service account exists and we need changes should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, corev1.AddToScheme(scheme))
+		f := dedicatedSAFunction()
+		sa := resources.NewServiceAccount(&f).ServiceAccount
+		require.NoError(t, controllerutil.SetControllerReference(&f, sa, scheme))
+		sa.Labels = map[string]string{"outdated": "label"}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sa).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleServiceAccount(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonServiceAccountUpdated,
+			"ServiceAccount kind-kowalevski-name updated")
+		updatedSA := &corev1.ServiceAccount{}
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "kind-kowalevski-name",
+			Namespace: "keen-kepler-ns",
+		}, updatedSA)
+		require.NoError(t, getErr)
+		require.Equal(t, f.FunctionLabels(), updatedSA.Labels)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -212,7 +213,7 @@ func Test_sFnHandleService(t *testing.T) {
 		require.Nil(t, result)
 		// with expected next state
 		require.NotNil(t, next)
-		requireEqualFunc(t, sFnDeploymentStatus, next)
+		requireEqualFunc(t, sFnHandlePodDisruptionBudget, next)
 		// service has not been created or updated
 		require.False(t, createOrUpdateWasCalled)
 		// function conditions remain unchanged
@@ -322,6 +323,114 @@ func Test_sFnHandleService(t *testing.T) {
 			serverlessv1alpha2.ConditionReasonServiceFailed,
 			"Service youthful-gates-name update failed: quirky-elion error message")
 	})
+	t.Run("when service exposure changes should update service and keep annotations of other components", func(t *testing.T) {
+		// Arrange
+		f := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "gallant-goldberg-name",
+				Namespace: "hopeful-hodgkin-ns"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Service: &serverlessv1alpha2.FunctionService{
+					Type:            corev1.ServiceTypeLoadBalancer,
+					SessionAffinity: corev1.ServiceAffinityClientIP,
+					Annotations:     map[string]string{"lb.example.com/internal": "true"},
+					Ports: []serverlessv1alpha2.FunctionServicePort{{
+						Name: "metrics",
+						Port: 9090}}}},
+			Status: serverlessv1alpha2.FunctionStatus{
+				ServiceAnnotations: map[string]string{"lb.example.com/removed": "true"}}}
+		svc := resources.NewService(&serverlessv1alpha2.Function{ObjectMeta: f.ObjectMeta}).Service
+		svc.Annotations = map[string]string{
+			"lb.example.com/removed": "true",
+			"other.example.com/id":   "hungry-hawking"}
+		svc.Spec.Ports[0].NodePort = 31234
+		// scheme and fake client
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, corev1.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc).Build()
+		// machine with our function
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleService(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonServiceUpdated,
+			"Service gallant-goldberg-name updated")
+		// current annotations are remembered to allow removing them later
+		require.Equal(t, map[string]string{"lb.example.com/internal": "true"}, m.State.Function.Status.ServiceAnnotations)
+		updatedSvc := &corev1.Service{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(svc), updatedSvc))
+		require.Equal(t, corev1.ServiceTypeLoadBalancer, updatedSvc.Spec.Type)
+		require.Equal(t, corev1.ServiceAffinityClientIP, updatedSvc.Spec.SessionAffinity)
+		require.Equal(t, map[string]string{
+			"lb.example.com/internal": "true",
+			"other.example.com/id":    "hungry-hawking"}, updatedSvc.Annotations)
+		require.Len(t, updatedSvc.Spec.Ports, 2)
+		// allocated node port is kept
+		require.Equal(t, int32(31234), updatedSvc.Spec.Ports[0].NodePort)
+		require.Equal(t, "metrics", updatedSvc.Spec.Ports[1].Name)
+		require.Equal(t, intstr.FromInt32(9090), updatedSvc.Spec.Ports[1].TargetPort)
+	})
+	t.Run("when headless mode changes should delete service and requeue", func(t *testing.T) {
+		// Arrange
+		f := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "nifty-noether-name",
+				Namespace: "optimistic-ohm-ns"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Service: &serverlessv1alpha2.FunctionService{
+					Headless: true}}}
+		svc := resources.NewService(&serverlessv1alpha2.Function{ObjectMeta: f.ObjectMeta}).Service
+		svc.Spec.ClusterIP = "10.0.0.12"
+		// scheme and fake client
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, corev1.AddToScheme(scheme))
+		updateWasCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(svc).WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updateWasCalled = true
+				return nil
+			},
+		}).Build()
+		// machine with our function
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := sFnHandleService(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		require.False(t, updateWasCalled)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonServiceDeleted,
+			"Service nifty-noether-name deleted")
+		// service has been deleted from k8s
+		getErr := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(svc), &corev1.Service{})
+		require.True(t, k8serrors.IsNotFound(getErr))
+	})
 }
 
 func Test_serviceChanged(t *testing.T) {
@@ -389,8 +498,7 @@ func Test_serviceChanged(t *testing.T) {
 			want: true,
 		},
 		{
-			// TODO: why?
-			name: "when more than one port should return true",
+			name: "when more than one port are equal should return false",
 			args: args{
 				a: &corev1.Service{
 					Spec: corev1.ServiceSpec{
@@ -403,16 +511,93 @@ func Test_serviceChanged(t *testing.T) {
 							{Name: "eager-khorana"},
 							{Name: "laughing-swartz"}}}},
 			},
+			want: false,
+		},
+		{
+			name: "when number of ports is different should return true",
+			args: args{
+				a: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{
+							{Name: "eager-khorana"}}}},
+				b: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{
+							{Name: "eager-khorana"},
+							{Name: "laughing-swartz"}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when only node ports are different should return false",
+			args: args{
+				a: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{{
+							Name:     "vibrant-hopper",
+							Port:     int32(80),
+							NodePort: int32(31234)}}}},
+				b: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{{
+							Name: "vibrant-hopper",
+							Port: int32(80)}}}},
+			},
+			want: false,
+		},
+		{
+			name: "when annotations are different should return true",
+			args: args{
+				a: &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: map[string]string{"nash": "gifted"}},
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{{Name: "festive-williams"}}}},
+				b: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{{Name: "festive-williams"}}}},
+			},
 			want: true,
 		},
 		{
-			// TODO: why?
-			name: "when less than one port should return true",
+			name: "when types are different should return true",
 			args: args{
 				a: &corev1.Service{
-					Spec: corev1.ServiceSpec{}},
+					Spec: corev1.ServiceSpec{
+						Type:  corev1.ServiceTypeClusterIP,
+						Ports: []corev1.ServicePort{{Name: "festive-williams"}}}},
 				b: &corev1.Service{
-					Spec: corev1.ServiceSpec{}},
+					Spec: corev1.ServiceSpec{
+						Type:  corev1.ServiceTypeLoadBalancer,
+						Ports: []corev1.ServicePort{{Name: "festive-williams"}}}},
+			},
+			want: true,
+		},
+		{
+			name: "when types are defaulted by kubernetes should return false",
+			args: args{
+				a: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Type:            corev1.ServiceTypeClusterIP,
+						SessionAffinity: corev1.ServiceAffinityNone,
+						Ports:           []corev1.ServicePort{{Name: "festive-williams"}}}},
+				b: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						Ports: []corev1.ServicePort{{Name: "festive-williams"}}}},
+			},
+			want: false,
+		},
+		{
+			name: "when session affinities are different should return true",
+			args: args{
+				a: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						SessionAffinity: corev1.ServiceAffinityNone,
+						Ports:           []corev1.ServicePort{{Name: "festive-williams"}}}},
+				b: &corev1.Service{
+					Spec: corev1.ServiceSpec{
+						SessionAffinity: corev1.ServiceAffinityClientIP,
+						Ports:           []corev1.ServicePort{{Name: "festive-williams"}}}},
 			},
 			want: true,
 		},
@@ -517,7 +702,7 @@ func Test_serviceChanged(t *testing.T) {
 						CreationTimestamp:          metav1.Time{Time: time.Date(789, 7, 8, 9, 7, 8, 9, &time.Location{})},
 						DeletionTimestamp:          &metav1.Time{Time: time.Date(789, 7, 8, 9, 7, 8, 9, &time.Location{})},
 						DeletionGracePeriodSeconds: ptr.To[int64](789),
-						Annotations:                map[string]string{"bartik": "pedantic"},
+						Annotations:                map[string]string{"nash": "gifted"},
 						OwnerReferences:            []metav1.OwnerReference{{Name: "pedantic-bartik"}},
 						Finalizers:                 []string{"pedantic-bartik"},
 						ManagedFields:              []metav1.ManagedFieldsEntry{{Operation: "pedantic-bartik"}}},
@@ -525,9 +710,9 @@ func Test_serviceChanged(t *testing.T) {
 						Ports:                    []corev1.ServicePort{{Name: "festive-williams"}},
 						ClusterIP:                "pedantic-bartik",
 						ClusterIPs:               []string{"pedantic-bartik"},
-						Type:                     "pedantic-bartik",
+						Type:                     "gifted-nash",
 						ExternalIPs:              []string{"pedantic-bartik"},
-						SessionAffinity:          "pedantic-bartik",
+						SessionAffinity:          "gifted-nash",
 						LoadBalancerSourceRanges: []string{"pedantic-bartik"},
 						ExternalName:             "pedantic-bartik",
 						ExternalTrafficPolicy:    "pedantic-bartik",
@@ -0,0 +1,294 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func sFnHandleSubscriptions(ctx context.Context, m *fsm.StateMachine) (fsm.StateFn, *ctrl.Result, error) {
+	f := &m.State.Function
+	clusterSubscriptions, installed, errList := listSubscriptions(ctx, m)
+	if errList != nil {
+		return stopWithError(errList)
+	}
+
+	if !installed {
+		if len(f.Spec.Events) == 0 {
+			meta.RemoveStatusCondition(&f.Status.Conditions, string(serverlessv1alpha2.ConditionSubscriptionsReady))
+			return nextState(sFnHandleScaledObject)
+		}
+		// the Function can run without the events, so the missing Eventing doesn't stop processing
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			"Consuming events requires the Subscription CustomResourceDefinition to be installed")
+		return nextState(sFnHandleScaledObject)
+	}
+
+	builtSubscriptions := resources.NewSubscriptions(f, &m.FunctionConfig)
+
+	requeueNeeded := false
+	for _, clusterSubscription := range clusterSubscriptions {
+		if findUnstructured(builtSubscriptions, clusterSubscription.GetName()) != nil {
+			continue
+		}
+		if errDelete := deleteSubscription(ctx, m, clusterSubscription); errDelete != nil {
+			return stopWithError(errDelete)
+		}
+		requeueNeeded = true
+	}
+
+	for _, builtSubscription := range builtSubscriptions {
+		clusterSubscription := findUnstructured(clusterSubscriptions, builtSubscription.GetName())
+		if clusterSubscription == nil {
+			// the name may be taken by a Subscription not listed for the Function
+			existingSubscription, errGet := getSubscription(ctx, m, builtSubscription.GetName())
+			if errGet != nil {
+				return stopWithError(errGet)
+			}
+			if existingSubscription != nil && !metav1.IsControlledBy(existingSubscription, f) {
+				// the Function can run without the events, so the conflict doesn't stop processing
+				f.UpdateCondition(
+					serverlessv1alpha2.ConditionSubscriptionsReady,
+					metav1.ConditionFalse,
+					serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+					fmt.Sprintf("Subscription %s already exists and is not managed by the Function", existingSubscription.GetName()))
+				return nextState(sFnHandleScaledObject)
+			}
+			clusterSubscription = existingSubscription
+		}
+		if clusterSubscription == nil {
+			if errCreate := createSubscription(ctx, m, builtSubscription); errCreate != nil {
+				return stopWithError(errCreate)
+			}
+			requeueNeeded = true
+			continue
+		}
+
+		updated, errUpdate := updateSubscriptionIfNeeded(ctx, m, clusterSubscription, builtSubscription)
+		if errUpdate != nil {
+			return stopWithError(errUpdate)
+		}
+		requeueNeeded = requeueNeeded || updated
+	}
+
+	if requeueNeeded {
+		return requeue()
+	}
+
+	if len(f.Spec.Events) == 0 {
+		meta.RemoveStatusCondition(&f.Status.Conditions, string(serverlessv1alpha2.ConditionSubscriptionsReady))
+		return nextState(sFnHandleScaledObject)
+	}
+	updateSubscriptionsReadyCondition(f, clusterSubscriptions)
+	return nextState(sFnHandleScaledObject)
+}
+
+// listSubscriptions returns Subscriptions created for the Function's events and information
+// whether the kind is known to the cluster (its CustomResourceDefinition is installed)
+func listSubscriptions(ctx context.Context, m *fsm.StateMachine) ([]*unstructured.Unstructured, bool, error) {
+	f := &m.State.Function
+	subscriptionList := &unstructured.UnstructuredList{}
+	subscriptionList.SetGroupVersionKind(resources.SubscriptionGVK.GroupVersion().WithKind(resources.SubscriptionGVK.Kind + "List"))
+	err := m.Client.List(ctx, subscriptionList,
+		client.InNamespace(f.GetNamespace()),
+		client.MatchingLabels(resources.SubscriptionLabels(f)),
+	)
+	if meta.IsNoMatchError(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to list Subscriptions for Function")
+		return nil, true, err
+	}
+
+	subscriptions := []*unstructured.Unstructured{}
+	for i := range subscriptionList.Items {
+		if metav1.IsControlledBy(&subscriptionList.Items[i], f) {
+			subscriptions = append(subscriptions, &subscriptionList.Items[i])
+		}
+	}
+	return subscriptions, true, nil
+}
+
+// getSubscription returns the Subscription with the given name or nil when it doesn't exist
+func getSubscription(ctx context.Context, m *fsm.StateMachine, name string) (*unstructured.Unstructured, error) {
+	subscription := &unstructured.Unstructured{}
+	subscription.SetGroupVersionKind(resources.SubscriptionGVK)
+	err := m.Client.Get(ctx, client.ObjectKey{
+		Namespace: m.State.Function.GetNamespace(),
+		Name:      name,
+	}, subscription)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		m.Log.Error(err, "unable to fetch Subscription for Function")
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func findUnstructured(objs []*unstructured.Unstructured, name string) *unstructured.Unstructured {
+	for _, obj := range objs {
+		if obj.GetName() == name {
+			return obj
+		}
+	}
+	return nil
+}
+
+func createSubscription(ctx context.Context, m *fsm.StateMachine, subscription *unstructured.Unstructured) error {
+	m.Log.Info("creating a new Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+
+	// Set the ownerRef for the Subscription, ensuring that the Subscription
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, subscription, m.Scheme); err != nil {
+		m.Log.Error(err, "failed to set controller reference for new Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			fmt.Sprintf("Subscription %s create failed: %s", subscription.GetName(), err.Error()))
+		return err
+	}
+
+	if err := m.Client.Create(ctx, subscription); err != nil {
+		m.Log.Error(err, "failed to create new Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			fmt.Sprintf("Subscription %s create failed: %s", subscription.GetName(), err.Error()))
+		return err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionSubscriptionsReady,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonSubscriptionCreated,
+		fmt.Sprintf("Subscription %s created", subscription.GetName()))
+
+	return nil
+}
+
+func updateSubscriptionIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterSubscription, builtSubscription *unstructured.Unstructured) (requeueNeeded bool, err error) {
+	if !subscriptionChanged(clusterSubscription, builtSubscription) {
+		return false, nil
+	}
+
+	// keep fields defaulted by Eventing, like the spec.config
+	clusterSpec, _, _ := unstructured.NestedMap(clusterSubscription.Object, "spec")
+	if clusterSpec == nil {
+		clusterSpec = map[string]interface{}{}
+	}
+	for key, value := range builtSubscription.Object["spec"].(map[string]interface{}) {
+		clusterSpec[key] = value
+	}
+	clusterSubscription.Object["spec"] = clusterSpec
+	clusterSubscription.SetLabels(builtSubscription.GetLabels())
+
+	if err := m.Client.Update(ctx, clusterSubscription); err != nil {
+		m.Log.Error(err, "Failed to update Subscription", "Subscription.Namespace", clusterSubscription.GetNamespace(), "Subscription.Name", clusterSubscription.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			fmt.Sprintf("Subscription %s update failed: %s", clusterSubscription.GetName(), err.Error()))
+		return false, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionSubscriptionsReady,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonSubscriptionUpdated,
+		fmt.Sprintf("Subscription %s updated", clusterSubscription.GetName()))
+	// Requeue the request to ensure the Subscription is updated
+	return true, nil
+}
+
+// subscriptionChanged compares only fields set by the controller
+func subscriptionChanged(clusterSubscription, builtSubscription *unstructured.Unstructured) bool {
+	clusterSpec, _, _ := unstructured.NestedMap(clusterSubscription.Object, "spec")
+	for key, value := range builtSubscription.Object["spec"].(map[string]interface{}) {
+		if !reflect.DeepEqual(clusterSpec[key], value) {
+			return true
+		}
+	}
+	return !mapsEqual(clusterSubscription.GetLabels(), builtSubscription.GetLabels())
+}
+
+func deleteSubscription(ctx context.Context, m *fsm.StateMachine, subscription *unstructured.Unstructured) error {
+	m.Log.Info("deleting Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+	if err := m.Client.Delete(ctx, subscription); client.IgnoreNotFound(err) != nil {
+		m.Log.Error(err, "Failed to delete Subscription", "Subscription.Namespace", subscription.GetNamespace(), "Subscription.Name", subscription.GetName())
+		m.State.Function.UpdateCondition(
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			fmt.Sprintf("Subscription %s delete failed: %s", subscription.GetName(), err.Error()))
+		return err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionSubscriptionsReady,
+		metav1.ConditionUnknown,
+		serverlessv1alpha2.ConditionReasonSubscriptionDeleted,
+		fmt.Sprintf("Subscription %s deleted", subscription.GetName()))
+
+	return nil
+}
+
+// updateSubscriptionsReadyCondition sets the condition based on the readiness reported by Eventing
+func updateSubscriptionsReadyCondition(f *serverlessv1alpha2.Function, subscriptions []*unstructured.Unstructured) {
+	notReady := []string{}
+	for _, subscription := range subscriptions {
+		ready, _, _ := unstructured.NestedBool(subscription.Object, "status", "ready")
+		if !ready {
+			notReady = append(notReady, fmt.Sprintf("%s: %s", subscription.GetName(), subscriptionNotReadyMessage(subscription)))
+		}
+	}
+
+	if len(notReady) > 0 {
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionsNotReady,
+			fmt.Sprintf("Subscriptions are not ready: %s", strings.Join(notReady, "; ")))
+		return
+	}
+	f.UpdateCondition(
+		serverlessv1alpha2.ConditionSubscriptionsReady,
+		metav1.ConditionTrue,
+		serverlessv1alpha2.ConditionReasonSubscriptionsReady,
+		"Subscriptions are ready")
+}
+
+// subscriptionNotReadyMessage returns the message of the first not fulfilled Subscription's condition
+func subscriptionNotReadyMessage(subscription *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(subscription.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok || condition["status"] == string(metav1.ConditionTrue) {
+			continue
+		}
+		if message, _ := condition["message"].(string); message != "" {
+			return message
+		}
+		if reason, _ := condition["reason"].(string); reason != "" {
+			return reason
+		}
+	}
+	return "waiting for Eventing"
+}
@@ -0,0 +1,386 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_sFnHandleSubscriptions(t *testing.T) {
+	subscribedFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "eager-euler-name",
+				Namespace: "elated-easley-ns",
+				UID:       "eager-euler-uid"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Events: []serverlessv1alpha2.FunctionEventSubscription{
+					{Name: "orders", Source: "commerce", Types: []string{"order.created.v1"}}}}}
+	}
+	fnConfig := config.FunctionConfig{}
+	ownedSubscription := func(t *testing.T, scheme *runtime.Scheme, f *serverlessv1alpha2.Function, event serverlessv1alpha2.FunctionEventSubscription) *unstructured.Unstructured {
+		s := resources.NewSubscription(f, &fnConfig, event).Unstructured
+		require.NoError(t, controllerutil.SetControllerReference(f, s, scheme))
+		return s
+	}
+	// subscriptionNotInstalled simulates cluster without the Subscription CustomResourceDefinition
+	subscriptionNotInstalled := interceptor.Funcs{
+		List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+			return &meta.NoKindMatchError{}
+		},
+	}
+	t.Run("when events are not configured and eventing is not installed should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(subscriptionNotInstalled).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "elegant-elion-name",
+						Namespace: "elated-easley-ns"}}},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleScaledObject, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when events are configured and eventing is not installed should report it and go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(subscriptionNotInstalled).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: subscribedFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleScaledObject, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			"Consuming events requires the Subscription CustomResourceDefinition to be installed")
+	})
+	t.Run("when subscription does not exist should create it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: subscribedFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonSubscriptionCreated,
+			"Subscription eager-euler-name-orders created")
+		appliedSubscription := &unstructured.Unstructured{}
+		appliedSubscription.SetGroupVersionKind(resources.SubscriptionGVK)
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "eager-euler-name-orders",
+			Namespace: "elated-easley-ns",
+		}, appliedSubscription)
+		require.NoError(t, getErr)
+		require.True(t, metav1.IsControlledBy(appliedSubscription, &m.State.Function))
+	})
+	t.Run("when subscription with the same name is not managed by the function should report it and go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		// the Subscription of the "orders" event of the "eager-euler" Function is named the same
+		otherFunction := serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "eager",
+				Namespace: "elated-easley-ns",
+				UID:       "eager-uid"}}
+		foreignSubscription := ownedSubscription(t, scheme, &otherFunction, serverlessv1alpha2.FunctionEventSubscription{Name: "euler-name-orders", Source: "billing", Types: []string{"invoice.paid.v1"}})
+		createWasCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(foreignSubscription).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				createWasCalled = true
+				return nil
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: subscribedFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleScaledObject, next)
+		require.False(t, createWasCalled)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionFailed,
+			"Subscription eager-euler-name-orders already exists and is not managed by the Function")
+	})
+	t.Run("when subscription is ready should set condition and go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := subscribedFunction()
+		s := ownedSubscription(t, scheme, &f, f.Spec.Events[0])
+		// config is defaulted by Eventing
+		require.NoError(t, unstructured.SetNestedField(s.Object, map[string]interface{}{"maxInFlightMessages": "10"}, "spec", "config"))
+		require.NoError(t, unstructured.SetNestedField(s.Object, true, "status", "ready"))
+		updateWasCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(s).WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updateWasCalled = true
+				return nil
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleScaledObject, next)
+		require.False(t, updateWasCalled)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionTrue,
+			serverlessv1alpha2.ConditionReasonSubscriptionsReady,
+			"Subscriptions are ready")
+	})
+	t.Run("when subscription is not ready should report its condition and go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := subscribedFunction()
+		s := ownedSubscription(t, scheme, &f, f.Spec.Events[0])
+		require.NoError(t, unstructured.SetNestedField(s.Object, map[string]interface{}{
+			"ready": false,
+			"conditions": []interface{}{
+				map[string]interface{}{
+					"type":    "Subscribed",
+					"status":  "False",
+					"reason":  "NATS Subscription not active",
+					"message": "consumer not found",
+				},
+			},
+		}, "status"))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(s).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleScaledObject, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionFalse,
+			serverlessv1alpha2.ConditionReasonSubscriptionsNotReady,
+			"Subscriptions are not ready: eager-euler-name-orders: consumer not found")
+	})
+	t.Run("when subscription exists and we need changes should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := subscribedFunction()
+		s := ownedSubscription(t, scheme, &f, f.Spec.Events[0])
+		require.NoError(t, unstructured.SetNestedField(s.Object, map[string]interface{}{"maxInFlightMessages": "10"}, "spec", "config"))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(s).Build()
+		f.Spec.Events[0].Types = []string{"order.created.v1", "order.cancelled.v1"}
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonSubscriptionUpdated,
+			"Subscription eager-euler-name-orders updated")
+		updatedSubscription := &unstructured.Unstructured{}
+		updatedSubscription.SetGroupVersionKind(resources.SubscriptionGVK)
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "eager-euler-name-orders",
+			Namespace: "elated-easley-ns",
+		}, updatedSubscription)
+		require.NoError(t, getErr)
+		types, _, _ := unstructured.NestedStringSlice(updatedSubscription.Object, "spec", "types")
+		require.Equal(t, []string{"order.created.v1", "order.cancelled.v1"}, types)
+		maxInFlight, _, _ := unstructured.NestedString(updatedSubscription.Object, "spec", "config", "maxInFlightMessages")
+		require.Equal(t, "10", maxInFlight)
+	})
+	t.Run("when events have been removed should delete the subscription and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		f := subscribedFunction()
+		s := ownedSubscription(t, scheme, &f, f.Spec.Events[0])
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(s).Build()
+		f.Spec.Events = nil
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.NotNil(t, result)
+		require.Equal(t, ctrl.Result{Requeue: true}, *result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonSubscriptionDeleted,
+			"Subscription eager-euler-name-orders deleted")
+		deletedSubscription := &unstructured.Unstructured{}
+		deletedSubscription.SetGroupVersionKind(resources.SubscriptionGVK)
+		getErr := k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "eager-euler-name-orders",
+			Namespace: "elated-easley-ns",
+		}, deletedSubscription)
+		require.True(t, k8serrors.IsNotFound(getErr))
+	})
+	t.Run("when events have been removed and subscriptions are deleted should remove the condition", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		f := subscribedFunction()
+		f.Spec.Events = nil
+		f.UpdateCondition(
+			serverlessv1alpha2.ConditionSubscriptionsReady,
+			metav1.ConditionUnknown,
+			serverlessv1alpha2.ConditionReasonSubscriptionDeleted,
+			"Subscription eager-euler-name-orders deleted")
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: f},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnHandleScaledObject, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when cannot list subscriptions from kubernetes should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, client client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				return errors.New("ecstatic-engelbart-error")
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State: fsm.SystemState{
+				Function: subscribedFunction()},
+			Log:            zap.NewNop().Sugar(),
+			Client:         k8sClient,
+			Scheme:         scheme,
+			FunctionConfig: fnConfig}
+
+		// Act
+		next, result, err := sFnHandleSubscriptions(context.Background(), &m)
+
+		// Assert
+		require.NotNil(t, err)
+		require.ErrorContains(t, err, "ecstatic-engelbart-error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+	})
+}
@@ -26,9 +26,11 @@ func Test_sFnMetricsStart(t *testing.T) {
 					Inline: &serverlessv1alpha2.InlineSource{
 						Source: "dazzling-matsumoto"}}},
 			Status: serverlessv1alpha2.FunctionStatus{}}
+		collector := metrics.NewCollector(false)
 		m := fsm.StateMachine{
 			State: fsm.SystemState{
-				Function: f}}
+				Function: f},
+			Metrics: collector}
 
 		// Act
 		next, result, err := sFnMetricsStart(context.Background(), &m)
@@ -42,7 +44,7 @@ func Test_sFnMetricsStart(t *testing.T) {
 		require.NotNil(t, next)
 		requireEqualFunc(t, sFnValidateFunction, next)
 		// metrics are set
-		require.Equal(t, float64(1), testutil.ToFloat64(metrics.ReconciliationsTotal))
-		require.Equal(t, float64(1), testutil.ToFloat64(metrics.FunctionsTotal))
+		require.Equal(t, float64(1), testutil.ToFloat64(collector.ReconciliationsTotal))
+		require.Equal(t, float64(1), testutil.ToFloat64(collector.FunctionsTotal))
 	})
 }
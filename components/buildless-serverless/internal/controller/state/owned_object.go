@@ -0,0 +1,188 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// ownedObject describes an optional object named after the Function and controlled by it.
+// The object is created when it's enabled, updated when it differs from the built one
+// and deleted when it's disabled. Objects with the same name not controlled by the Function are never modified.
+type ownedObject[T client.Object] struct {
+	// kind is used in logs and condition messages
+	kind string
+	// empty returns an object used to get the object from the cluster
+	empty func() T
+	// enabled returns true if the Function needs the object
+	enabled func(m *fsm.StateMachine) bool
+	// build returns the object expected in the cluster, it's called only when the object is enabled
+	build func(m *fsm.StateMachine) T
+	// changed compares fields set by the controller, labels are always compared, optional
+	changed func(cluster, built T) bool
+	// apply copies fields set by the controller from the built object to the cluster one before the update, optional
+	apply func(cluster, built T)
+	// recreate returns true if immutable fields differ, the object is then deleted and created in the next reconciliation, optional
+	recreate func(cluster, built T) bool
+	// notInstalledMessage is reported when the object kind is not known to the cluster,
+	// when empty the missing kind is treated as any other error
+	notInstalledMessage string
+
+	createdReason serverlessv1alpha2.ConditionReason
+	updatedReason serverlessv1alpha2.ConditionReason
+	deletedReason serverlessv1alpha2.ConditionReason
+	failedReason  serverlessv1alpha2.ConditionReason
+
+	next fsm.StateFn
+}
+
+func reconcileOwnedObject[T client.Object](ctx context.Context, m *fsm.StateMachine, o ownedObject[T]) (fsm.StateFn, *ctrl.Result, error) {
+	clusterObj, exists, installed, errGet := o.get(ctx, m)
+	if errGet != nil {
+		return stopWithError(errGet)
+	}
+
+	if !o.enabled(m) {
+		if !exists || !metav1.IsControlledBy(clusterObj, &m.State.Function) {
+			// nothing to clean up, objects created by the user are kept
+			return nextState(o.next)
+		}
+		result, errDelete := o.delete(ctx, m, clusterObj)
+		return nil, result, errDelete
+	}
+
+	if !installed {
+		o.fail(m, o.notInstalledMessage)
+		return stop()
+	}
+
+	builtObj := o.build(m)
+	if !exists {
+		result, errCreate := o.create(ctx, m, builtObj)
+		return nil, result, errCreate
+	}
+
+	if !metav1.IsControlledBy(clusterObj, &m.State.Function) {
+		o.fail(m, fmt.Sprintf("%s %s already exists and is not managed by the Function", o.kind, clusterObj.GetName()))
+		return stop()
+	}
+
+	if o.recreate != nil && o.recreate(clusterObj, builtObj) {
+		result, errDelete := o.delete(ctx, m, clusterObj)
+		return nil, result, errDelete
+	}
+
+	requeueNeeded, errUpdate := o.updateIfNeeded(ctx, m, clusterObj, builtObj)
+	if errUpdate != nil {
+		return stopWithError(errUpdate)
+	}
+	if requeueNeeded {
+		return requeue()
+	}
+	return nextState(o.next)
+}
+
+// get returns the object named after the Function, information whether it exists
+// and whether its kind is known to the cluster
+func (o ownedObject[T]) get(ctx context.Context, m *fsm.StateMachine) (T, bool, bool, error) {
+	obj := o.empty()
+	f := m.State.Function
+	err := m.Client.Get(ctx, client.ObjectKey{
+		Namespace: f.GetNamespace(),
+		Name:      f.GetName(),
+	}, obj)
+
+	if err == nil {
+		return obj, true, true, nil
+	}
+	if o.notInstalledMessage != "" && meta.IsNoMatchError(err) {
+		return obj, false, false, nil
+	}
+	if !errors.IsNotFound(err) {
+		m.Log.Error(err, fmt.Sprintf("unable to fetch %s for Function", o.kind))
+		return obj, false, true, err
+	}
+	return obj, false, true, nil
+}
+
+func (o ownedObject[T]) create(ctx context.Context, m *fsm.StateMachine, obj T) (*ctrl.Result, error) {
+	m.Log.Info(fmt.Sprintf("creating a new %s", o.kind), o.kind+".Namespace", obj.GetNamespace(), o.kind+".Name", obj.GetName())
+
+	// Set the ownerRef for the object, ensuring that the object
+	// will be deleted when the Function CR is deleted.
+	if err := controllerutil.SetControllerReference(&m.State.Function, obj, m.Scheme); err != nil {
+		m.Log.Error(err, fmt.Sprintf("failed to set controller reference for new %s", o.kind), o.kind+".Namespace", obj.GetNamespace(), o.kind+".Name", obj.GetName())
+		o.fail(m, fmt.Sprintf("%s %s create failed: %s", o.kind, obj.GetName(), err.Error()))
+		return nil, err
+	}
+
+	if err := m.Client.Create(ctx, obj); err != nil {
+		m.Log.Error(err, fmt.Sprintf("failed to create new %s", o.kind), o.kind+".Namespace", obj.GetNamespace(), o.kind+".Name", obj.GetName())
+		o.fail(m, fmt.Sprintf("%s %s create failed: %s", o.kind, obj.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		o.createdReason,
+		fmt.Sprintf("%s %s created", o.kind, obj.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+func (o ownedObject[T]) updateIfNeeded(ctx context.Context, m *fsm.StateMachine, clusterObj, builtObj T) (requeueNeeded bool, err error) {
+	changed := o.changed != nil && o.changed(clusterObj, builtObj)
+	if !changed && mapsEqual(clusterObj.GetLabels(), builtObj.GetLabels()) {
+		return false, nil
+	}
+
+	if o.apply != nil {
+		o.apply(clusterObj, builtObj)
+	}
+	clusterObj.SetLabels(builtObj.GetLabels())
+
+	if err := m.Client.Update(ctx, clusterObj); err != nil {
+		m.Log.Error(err, fmt.Sprintf("Failed to update %s", o.kind), o.kind+".Namespace", clusterObj.GetNamespace(), o.kind+".Name", clusterObj.GetName())
+		o.fail(m, fmt.Sprintf("%s %s update failed: %s", o.kind, clusterObj.GetName(), err.Error()))
+		return false, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		o.updatedReason,
+		fmt.Sprintf("%s %s updated", o.kind, clusterObj.GetName()))
+	// Requeue the request to ensure the object is updated
+	return true, nil
+}
+
+func (o ownedObject[T]) delete(ctx context.Context, m *fsm.StateMachine, obj T) (*ctrl.Result, error) {
+	m.Log.Info(fmt.Sprintf("deleting %s", o.kind), o.kind+".Namespace", obj.GetNamespace(), o.kind+".Name", obj.GetName())
+	if err := m.Client.Delete(ctx, obj); client.IgnoreNotFound(err) != nil {
+		m.Log.Error(err, fmt.Sprintf("Failed to delete %s", o.kind), o.kind+".Namespace", obj.GetNamespace(), o.kind+".Name", obj.GetName())
+		o.fail(m, fmt.Sprintf("%s %s delete failed: %s", o.kind, obj.GetName(), err.Error()))
+		return nil, err
+	}
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionUnknown,
+		o.deletedReason,
+		fmt.Sprintf("%s %s deleted", o.kind, obj.GetName()))
+
+	return &ctrl.Result{Requeue: true}, nil
+}
+
+func (o ownedObject[T]) fail(m *fsm.StateMachine, msg string) {
+	m.State.Function.UpdateCondition(
+		serverlessv1alpha2.ConditionRunning,
+		metav1.ConditionFalse,
+		o.failedReason,
+		msg)
+}
@@ -0,0 +1,460 @@
+package state
+
+import (
+	"context"
+	"testing"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/fsm"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func Test_reconcileOwnedObject(t *testing.T) {
+	fixFunction := func() serverlessv1alpha2.Function {
+		return serverlessv1alpha2.Function{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "modest-mcnulty-name",
+				Namespace: "musing-meitner-ns",
+				UID:       "modest-mcnulty-uid"},
+			Spec: serverlessv1alpha2.FunctionSpec{
+				Labels: map[string]string{"mystifying": "morse"}}}
+	}
+	// the ConfigMap keeps the Function's labels and data, it's enabled when the Function has any labels
+	fixOwnedConfigMap := func() ownedObject[*corev1.ConfigMap] {
+		return ownedObject[*corev1.ConfigMap]{
+			kind:  "ConfigMap",
+			empty: func() *corev1.ConfigMap { return &corev1.ConfigMap{} },
+			enabled: func(m *fsm.StateMachine) bool {
+				return len(m.State.Function.Spec.Labels) != 0
+			},
+			build: func(m *fsm.StateMachine) *corev1.ConfigMap {
+				return &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      m.State.Function.GetName(),
+						Namespace: m.State.Function.GetNamespace(),
+						Labels:    m.State.Function.Spec.Labels},
+					Data: map[string]string{"nice": "nobel"}}
+			},
+			changed: func(cluster, built *corev1.ConfigMap) bool {
+				return !mapsEqual(cluster.Data, built.Data)
+			},
+			apply: func(cluster, built *corev1.ConfigMap) {
+				cluster.Data = built.Data
+			},
+			createdReason: "ConfigMapCreated",
+			updatedReason: "ConfigMapUpdated",
+			deletedReason: "ConfigMapDeleted",
+			failedReason:  "ConfigMapFailed",
+			next:          sFnDeploymentStatus,
+		}
+	}
+	fixConfigMap := func(t *testing.T, scheme *runtime.Scheme, f *serverlessv1alpha2.Function, owned bool) *corev1.ConfigMap {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      f.GetName(),
+				Namespace: f.GetNamespace(),
+				Labels:    map[string]string{"mystifying": "morse"}},
+			Data: map[string]string{"nice": "nobel"}}
+		if owned {
+			require.NoError(t, controllerutil.SetControllerReference(f, cm, scheme))
+		}
+		return cm
+	}
+	fixScheme := func(t *testing.T) *runtime.Scheme {
+		scheme := runtime.NewScheme()
+		require.NoError(t, serverlessv1alpha2.AddToScheme(scheme))
+		require.NoError(t, corev1.AddToScheme(scheme))
+		return scheme
+	}
+	t.Run("when object is disabled and does not exist should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		f.Spec.Labels = nil
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnDeploymentStatus, next)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when object does not exist should create it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: fixFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{Requeue: true}, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			"ConfigMapCreated",
+			"ConfigMap modest-mcnulty-name created")
+		createdCM := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKey{
+			Name:      "modest-mcnulty-name",
+			Namespace: "musing-meitner-ns"}, createdCM))
+		require.Equal(t, map[string]string{"nice": "nobel"}, createdCM.Data)
+		require.True(t, metav1.IsControlledBy(createdCM, &m.State.Function))
+	})
+	t.Run("when object exists and we do not need changes should go to the next state", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		updateWasCalled := false
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(fixConfigMap(t, scheme, &f, true)).WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updateWasCalled = true
+				return nil
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnDeploymentStatus, next)
+		require.False(t, updateWasCalled)
+		require.Empty(t, m.State.Function.Status.Conditions)
+	})
+	t.Run("when object exists and we need changes should update it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		cm := fixConfigMap(t, scheme, &f, true)
+		cm.Data = map[string]string{"nice": "neumann"}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{Requeue: true}, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			"ConfigMapUpdated",
+			"ConfigMap modest-mcnulty-name updated")
+		updatedCM := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cm), updatedCM))
+		require.Equal(t, map[string]string{"nice": "nobel"}, updatedCM.Data)
+	})
+	t.Run("when only labels differ should update object and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		cm := fixConfigMap(t, scheme, &f, true)
+		f.Spec.Labels = map[string]string{"mystifying": "minsky"}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+		owned := fixOwnedConfigMap()
+		owned.changed = nil
+		owned.apply = nil
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, owned)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{Requeue: true}, result)
+		require.Nil(t, next)
+		updatedCM := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cm), updatedCM))
+		require.Equal(t, map[string]string{"mystifying": "minsky"}, updatedCM.GetLabels())
+	})
+	t.Run("when immutable fields differ should delete object and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		cm := fixConfigMap(t, scheme, &f, true)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+		owned := fixOwnedConfigMap()
+		owned.recreate = func(cluster, built *corev1.ConfigMap) bool { return true }
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, owned)
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{Requeue: true}, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			"ConfigMapDeleted",
+			"ConfigMap modest-mcnulty-name deleted")
+		err = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+		require.True(t, k8serrors.IsNotFound(err))
+	})
+	t.Run("when object exists and is not managed by the function should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		cm := fixConfigMap(t, scheme, &f, false)
+		cm.Data = map[string]string{"nice": "neumann"}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			"ConfigMapFailed",
+			"ConfigMap modest-mcnulty-name already exists and is not managed by the Function")
+		clusterCM := &corev1.ConfigMap{}
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cm), clusterCM))
+		require.Equal(t, map[string]string{"nice": "neumann"}, clusterCM.Data)
+	})
+	t.Run("when object has been disabled should delete it and requeue", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		cm := fixConfigMap(t, scheme, &f, true)
+		f.Spec.Labels = nil
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.Nil(t, err)
+		require.Equal(t, &ctrl.Result{Requeue: true}, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionUnknown,
+			"ConfigMapDeleted",
+			"ConfigMap modest-mcnulty-name deleted")
+		err = k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{})
+		require.True(t, k8serrors.IsNotFound(err))
+	})
+	t.Run("when object has been disabled and is not managed by the function should not delete it", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		cm := fixConfigMap(t, scheme, &f, false)
+		f.Spec.Labels = nil
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		requireEqualFunc(t, sFnDeploymentStatus, next)
+		require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(cm), &corev1.ConfigMap{}))
+	})
+	t.Run("when kind is not installed and object is enabled should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return &meta.NoKindMatchError{}
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: fixFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+		owned := fixOwnedConfigMap()
+		owned.notInstalledMessage = "ConfigMaps are not installed"
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, owned)
+
+		// Assert
+		require.Nil(t, err)
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			"ConfigMapFailed",
+			"ConfigMaps are not installed")
+	})
+	t.Run("when cannot get object from kubernetes should stop processing", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, client client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return errors.New("mystic-merkle-error")
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: fixFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.ErrorContains(t, err, "mystic-merkle-error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+	})
+	t.Run("when cannot create object should set condition and return error", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				return errors.New("magical-mirzakhani-error")
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: fixFunction()},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.ErrorContains(t, err, "magical-mirzakhani-error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			"ConfigMapFailed",
+			"ConfigMap modest-mcnulty-name create failed: magical-mirzakhani-error")
+	})
+	t.Run("when cannot update object should set condition and return error", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		cm := fixConfigMap(t, scheme, &f, true)
+		cm.Data = nil
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				return errors.New("marvelous-mayer-error")
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.ErrorContains(t, err, "marvelous-mayer-error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			"ConfigMapFailed",
+			"ConfigMap modest-mcnulty-name update failed: marvelous-mayer-error")
+	})
+	t.Run("when cannot delete object should set condition and return error", func(t *testing.T) {
+		// Arrange
+		scheme := fixScheme(t)
+		f := fixFunction()
+		cm := fixConfigMap(t, scheme, &f, true)
+		f.Spec.Labels = nil
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cm).WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				return errors.New("mindful-moser-error")
+			},
+		}).Build()
+		m := fsm.StateMachine{
+			State:  fsm.SystemState{Function: f},
+			Log:    zap.NewNop().Sugar(),
+			Client: k8sClient,
+			Scheme: scheme}
+
+		// Act
+		next, result, err := reconcileOwnedObject(context.Background(), &m, fixOwnedConfigMap())
+
+		// Assert
+		require.ErrorContains(t, err, "mindful-moser-error")
+		require.Nil(t, result)
+		require.Nil(t, next)
+		requireContainsCondition(t, m.State.Function.Status,
+			serverlessv1alpha2.ConditionRunning,
+			metav1.ConditionFalse,
+			"ConfigMapFailed",
+			"ConfigMap modest-mcnulty-name delete failed: mindful-moser-error")
+	})
+}
@@ -1,5 +1,11 @@
 package state
 
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
 func mapsEqual(a, b map[string]string) bool {
 	if len(a) != len(b) {
 		return false
@@ -11,3 +17,47 @@ func mapsEqual(a, b map[string]string) bool {
 	}
 	return true
 }
+
+// specChanged compares only the spec fields built by the controller, so fields defaulted by the API server don't cause endless updates.
+// optionalKeys are top-level spec fields which the controller doesn't always set, they have to be removed from the cluster object when they are not built
+func specChanged(clusterObj, builtObj *unstructured.Unstructured, optionalKeys ...string) bool {
+	clusterSpec, _, _ := unstructured.NestedMap(clusterObj.Object, "spec")
+	builtSpec, _, _ := unstructured.NestedMap(builtObj.Object, "spec")
+	for _, key := range optionalKeys {
+		_, built := builtSpec[key]
+		if _, exists := clusterSpec[key]; exists && !built {
+			return true
+		}
+	}
+	return !containsBuiltValue(clusterSpec, builtSpec)
+}
+
+// containsBuiltValue returns true if every value from built is present in cluster, values present only in cluster are ignored
+func containsBuiltValue(cluster, built interface{}) bool {
+	switch builtValue := built.(type) {
+	case map[string]interface{}:
+		clusterValue, ok := cluster.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range builtValue {
+			if !containsBuiltValue(clusterValue[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		clusterValue, ok := cluster.([]interface{})
+		if !ok || len(clusterValue) != len(builtValue) {
+			return false
+		}
+		for i := range builtValue {
+			if !containsBuiltValue(clusterValue[i], builtValue[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return reflect.DeepEqual(cluster, built)
+	}
+}
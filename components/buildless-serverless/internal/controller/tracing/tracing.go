@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+
+	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	serviceName = "serverless-controller"
+	tracerName  = "github.com/kyma-project/serverless/components/buildless-serverless/internal/controller"
+)
+
+// ShutdownFn flushes spans which are not exported yet and stops the exporter
+type ShutdownFn func(context.Context) error
+
+// NewTracer returns the tracer which exports spans to the OTLP/HTTP collector under the endpoint URL,
+// spans are not recorded when the endpoint is empty
+func NewTracer(ctx context.Context, endpoint string) (trace.Tracer, ShutdownFn, error) {
+	if endpoint == "" {
+		return NoopTracer(), func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "while creating traces exporter")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", serviceName),
+		)),
+	)
+	return provider.Tracer(tracerName), provider.Shutdown, nil
+}
+
+// NoopTracer returns the tracer which doesn't record spans
+func NoopTracer() trace.Tracer {
+	return noop.NewTracerProvider().Tracer(tracerName)
+}
+
+// FunctionAttributes describes the reconciled function in spans
+func FunctionAttributes(f *serverlessv1alpha2.Function) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("function.namespace", f.GetNamespace()),
+		attribute.String("function.name", f.GetName()),
+		attribute.String("function.uid", string(f.GetUID())),
+		attribute.Int64("function.generation", f.GetGeneration()),
+		attribute.String("function.runtime", string(f.Spec.Runtime)),
+		attribute.Bool("function.git_sources", f.HasGitSources()),
+	}
+}
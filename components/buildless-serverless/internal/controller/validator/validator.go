@@ -1,23 +1,43 @@
 package validator
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	serverlessv1alpha2 "github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
 	"github.com/kyma-project/serverless/components/common/fips"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/api/validation"
+	apipath "k8s.io/apimachinery/pkg/api/validation/path"
 	v1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	utilvalidation "k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
+const (
+	minTimeoutSeconds         = 1
+	maxTimeoutSeconds         = 3600
+	minTokenExpirationSeconds = 600
+)
+
+var (
+	minRequestBodySize = resource.MustParse("1Mi")
+	maxRequestBodySize = resource.MustParse("100Mi")
+	exposeMethods      = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	scheduleMacros     = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+	cronFieldRegex     = regexp.MustCompile(`^[0-9A-Za-z*?/,\-]+$`)
+)
+
 type validator struct {
 	instance  *serverlessv1alpha2.Function
 	fnConfig  config.FunctionConfig
@@ -38,11 +58,25 @@ func (v *validator) Validate() []string {
 		v.validateInlineDeps,
 		v.validateRuntime,
 		v.validateSecretMounts,
+		v.validateConfigMapMounts,
+		v.validateServiceAccountTokenMounts,
+		v.validateVolumes,
+		v.validateContainers,
+		v.validateMountPaths,
 		v.validateFunctionLabels,
 		v.validateFunctionAnnotations,
 		v.validateGitRepoURL,
 		v.validateFips,
 		v.validateFunctionResources,
+		v.validateNodeSelector,
+		v.validatePriorityClassName,
+		v.validateRuntimeClassName,
+		v.validateServiceAccount,
+		v.validateService,
+		v.validateExpose,
+		v.validateSchedules,
+		v.validateEvents,
+		v.validateRequestLimits,
 	}
 
 	r := []string{}
@@ -60,7 +94,21 @@ func (v *validator) validateEnvs() []string {
 			return enrichErrors(vr, "spec.env", env.Name)
 		}
 	}
-	return []string{}
+
+	// envs required by runtimes are rejected by the CRD, overriding other system envs is reported by sFnConfigurationReady
+	result := []string{}
+	for _, envFrom := range v.instance.Spec.EnvFrom {
+		if (envFrom.ConfigMapRef == nil) == (envFrom.SecretRef == nil) {
+			result = append(result, "invalid spec.envFrom: use exactly one of configMapRef or secretRef")
+		}
+		if envFrom.Prefix == "" {
+			continue
+		}
+		if vr := utilvalidation.IsEnvVarName(envFrom.Prefix); len(vr) != 0 {
+			result = append(result, enrichErrors(vr, "spec.envFrom.prefix", envFrom.Prefix)...)
+		}
+	}
+	return result
 }
 
 func (v *validator) validateInlineDeps() []string {
@@ -94,6 +142,12 @@ func (v *validator) validateSecretMounts() []string {
 	for _, secretMount := range secretMounts {
 		allErrs = append(allErrs,
 			utilvalidation.IsDNS1123Subdomain(secretMount.SecretName)...)
+		if strings.HasPrefix(secretMount.SecretName, resources.GeneratedVolumeNamePrefix) {
+			// secret volumes are named after the Secret
+			allErrs = append(allErrs, fmt.Sprintf("secretName %s must not start with %s reserved for generated volumes",
+				secretMount.SecretName, resources.GeneratedVolumeNamePrefix))
+		}
+		allErrs = append(allErrs, validateKeyProjections(secretMount.Items)...)
 	}
 	if !secretNamesAreUnique(secretMounts) {
 		allErrs = append(allErrs, "secretNames should be unique")
@@ -106,6 +160,170 @@ func (v *validator) validateSecretMounts() []string {
 	}
 }
 
+func (v *validator) validateConfigMapMounts() []string {
+	var allErrs []string
+	for _, configMapMount := range v.instance.Spec.ConfigMapMounts {
+		allErrs = append(allErrs,
+			utilvalidation.IsDNS1123Subdomain(configMapMount.ConfigMapName)...)
+		allErrs = append(allErrs, validateKeyProjections(configMapMount.Items)...)
+		if mode := configMapMount.DefaultMode; mode != nil && (*mode < 0 || *mode > 0777) {
+			allErrs = append(allErrs, fmt.Sprintf("defaultMode %o must be between 0 and 0777", *mode))
+		}
+	}
+	if len(allErrs) == 0 {
+		return []string{}
+	}
+	return []string{
+		fmt.Sprintf("invalid spec.configMapMounts: %s", allErrs),
+	}
+}
+
+func (v *validator) validateServiceAccountTokenMounts() []string {
+	var allErrs []string
+	for _, tokenMount := range v.instance.Spec.ServiceAccountTokenMounts {
+		if seconds := tokenMount.ExpirationSeconds; seconds != nil && *seconds < minTokenExpirationSeconds {
+			allErrs = append(allErrs, fmt.Sprintf("expirationSeconds %d must be at least %d", *seconds, minTokenExpirationSeconds))
+		}
+		if tokenMount.Path != "" {
+			allErrs = append(allErrs, validateRelativePath(tokenMount.Path)...)
+		}
+	}
+	if len(allErrs) == 0 {
+		return []string{}
+	}
+	return []string{
+		fmt.Sprintf("invalid spec.serviceAccountTokenMounts: %s", allErrs),
+	}
+}
+
+func (v *validator) validateVolumes() []string {
+	spec := v.instance.Spec
+	var allErrs []string
+	volumeNames := map[string]bool{}
+	for _, volume := range spec.Volumes {
+		allErrs = append(allErrs, utilvalidation.IsDNS1123Label(volume.Name)...)
+		if volumeNames[volume.Name] {
+			allErrs = append(allErrs, fmt.Sprintf("volume name %s should be unique", volume.Name))
+		}
+		volumeNames[volume.Name] = true
+		if strings.HasPrefix(volume.Name, resources.GeneratedVolumeNamePrefix) {
+			allErrs = append(allErrs, fmt.Sprintf("volume name %s must not start with %s reserved for generated volumes",
+				volume.Name, resources.GeneratedVolumeNamePrefix))
+		}
+
+		if volume.EmptyDir != nil && volume.EmptyDir.SizeLimit != nil && volume.EmptyDir.SizeLimit.Sign() <= 0 {
+			allErrs = append(allErrs, fmt.Sprintf("sizeLimit %s of volume %s must be greater than 0",
+				volume.EmptyDir.SizeLimit.String(), volume.Name))
+		}
+		if volume.PersistentVolumeClaim != nil {
+			allErrs = append(allErrs, utilvalidation.IsDNS1123Subdomain(volume.PersistentVolumeClaim.ClaimName)...)
+		}
+	}
+	if len(allErrs) == 0 {
+		return []string{}
+	}
+	return []string{
+		fmt.Sprintf("invalid spec.volumes: %s", allErrs),
+	}
+}
+
+// validateContainers checks sidecars and init containers defined by the user
+func (v *validator) validateContainers() []string {
+	spec := v.instance.Spec
+	volumeNames := map[string]bool{}
+	for _, volume := range spec.Volumes {
+		volumeNames[volume.Name] = true
+	}
+	// names are unique across all containers of the Function's Pod
+	containerNames := map[string]bool{
+		resources.FunctionContainerName:    true,
+		resources.GitInitContainerName:     true,
+		resources.InstallInitContainerName: true,
+	}
+
+	result := []string{}
+	for _, userContainers := range []struct {
+		path       string
+		containers []corev1.Container
+	}{
+		{"spec.sidecars", spec.Sidecars},
+		{"spec.initContainers", spec.InitContainers},
+	} {
+		var allErrs []string
+		for _, container := range userContainers.containers {
+			allErrs = append(allErrs, utilvalidation.IsDNS1123Label(container.Name)...)
+			if containerNames[container.Name] {
+				allErrs = append(allErrs, fmt.Sprintf("container name %s is already used", container.Name))
+			}
+			containerNames[container.Name] = true
+
+			if container.Image == "" {
+				allErrs = append(allErrs, fmt.Sprintf("image of container %s must be set", container.Name))
+			}
+			for _, port := range container.Ports {
+				if port.ContainerPort == resources.FunctionPort {
+					allErrs = append(allErrs, fmt.Sprintf("port %d of container %s is used by the Function", port.ContainerPort, container.Name))
+				}
+			}
+			for _, volumeMount := range container.VolumeMounts {
+				if !volumeNames[volumeMount.Name] {
+					allErrs = append(allErrs, fmt.Sprintf("volume %s mounted in container %s is not defined in spec.volumes", volumeMount.Name, container.Name))
+				}
+			}
+		}
+		if len(allErrs) != 0 {
+			result = append(result, fmt.Sprintf("invalid %s: %s", userContainers.path, allErrs))
+		}
+	}
+	return result
+}
+
+// validateMountPaths checks that the ConfigMap, token and volume mounts don't shadow the mounts required
+// to run the Function and that every user defined mount uses a distinct path
+func (v *validator) validateMountPaths() []string {
+	type userMount struct {
+		fieldPath string
+		mountPath string
+	}
+	spec := v.instance.Spec
+	userMounts := []userMount{}
+	for _, secretMount := range spec.SecretMounts {
+		userMounts = append(userMounts, userMount{"spec.secretMounts", secretMount.MountPath})
+	}
+	for _, configMapMount := range spec.ConfigMapMounts {
+		userMounts = append(userMounts, userMount{"spec.configMapMounts", configMapMount.MountPath})
+	}
+	for _, tokenMount := range spec.ServiceAccountTokenMounts {
+		userMounts = append(userMounts, userMount{"spec.serviceAccountTokenMounts", tokenMount.MountPath})
+	}
+	for _, volume := range spec.Volumes {
+		userMounts = append(userMounts, userMount{"spec.volumes", volume.MountPath})
+	}
+
+	reservedMounts := resources.ReservedVolumeMounts(v.instance)
+	usedPaths := map[string]bool{}
+	result := []string{}
+	for _, m := range userMounts {
+		cleanPath := path.Clean(m.mountPath)
+		if usedPaths[cleanPath] {
+			result = append(result, fmt.Sprintf("invalid %s: mountPath %s is used by another mount", m.fieldPath, m.mountPath))
+		}
+		usedPaths[cleanPath] = true
+
+		if m.fieldPath == "spec.secretMounts" {
+			// secret mounts are not checked against reserved mounts to keep existing Functions valid
+			continue
+		}
+		for _, reserved := range reservedMounts {
+			if reserved.MountPath != "" && pathsOverlap(cleanPath, reserved.MountPath) {
+				result = append(result, fmt.Sprintf("invalid %s: mountPath %s collides with the reserved %s mount (%s)",
+					m.fieldPath, m.mountPath, reserved.Name, reserved.MountPath))
+			}
+		}
+	}
+	return result
+}
+
 func (v *validator) validateFunctionLabels() []string {
 	labels := v.instance.Spec.Labels
 	path := "spec.labels"
@@ -183,6 +401,271 @@ func (v *validator) validateFunctionResources() []string {
 	return []string{}
 }
 
+func (v *validator) validateNodeSelector() []string {
+	nodeSelector := v.instance.Spec.NodeSelector
+	errs := v1validation.ValidateLabels(nodeSelector, field.NewPath("spec.nodeSelector"))
+	return errorListToStrings(errs)
+}
+
+func (v *validator) validatePriorityClassName() []string {
+	priorityClassName := v.instance.Spec.PriorityClassName
+	if priorityClassName == "" {
+		return []string{}
+	}
+	return enrichErrors(utilvalidation.IsDNS1123Subdomain(priorityClassName), "spec.priorityClassName", priorityClassName)
+}
+
+func (v *validator) validateRuntimeClassName() []string {
+	runtimeClassName := v.instance.Spec.RuntimeClassName
+	if runtimeClassName == nil {
+		return []string{}
+	}
+	return enrichErrors(utilvalidation.IsDNS1123Subdomain(*runtimeClassName), "spec.runtimeClassName", *runtimeClassName)
+}
+
+func (v *validator) validateServiceAccount() []string {
+	spec := v.instance.Spec
+	result := []string{}
+	if spec.ServiceAccountName != "" {
+		result = append(result, enrichErrors(utilvalidation.IsDNS1123Subdomain(spec.ServiceAccountName), "spec.serviceAccountName", spec.ServiceAccountName)...)
+	}
+	if spec.ServiceAccount == nil || spec.ServiceAccount.RoleRef == nil {
+		return result
+	}
+	roleRef := spec.ServiceAccount.RoleRef
+	for _, msg := range apipath.IsValidPathSegmentName(roleRef.Name) {
+		result = append(result, fmt.Sprintf("invalid spec.serviceAccount.roleRef.name: %s", msg))
+	}
+	if roleRef.Kind == "ClusterRole" && !slices.Contains(v.fnConfig.BindableClusterRoles, roleRef.Name) {
+		result = append(result, fmt.Sprintf("invalid spec.serviceAccount.roleRef: ClusterRole %s can't be bound, allowed ClusterRoles: %s",
+			roleRef.Name, v.fnConfig.BindableClusterRoles))
+	}
+	if roleRef.Kind == "Role" && !slices.Contains(v.fnConfig.BindableRoles, roleRef.Name) {
+		result = append(result, fmt.Sprintf("invalid spec.serviceAccount.roleRef: Role %s can't be bound, allowed Roles: %s",
+			roleRef.Name, v.fnConfig.BindableRoles))
+	}
+	return result
+}
+
+func (v *validator) validateService() []string {
+	svcConfig := v.instance.Spec.Service
+	result := []string{}
+	if svcConfig == nil {
+		return result
+	}
+	if svcConfig.Headless && svcConfig.Type != "" && svcConfig.Type != corev1.ServiceTypeClusterIP {
+		result = append(result, fmt.Sprintf("invalid spec.service.headless: Service of the %s type can't be headless", svcConfig.Type))
+	}
+
+	portNames := map[string]bool{}
+	ports := map[string]bool{fmt.Sprintf("%d/%s", resources.FunctionServicePort(v.instance), corev1.ProtocolTCP): true}
+	for _, port := range svcConfig.Ports {
+		result = append(result, enrichErrors(utilvalidation.IsDNS1123Label(port.Name), "spec.service.ports.name", port.Name)...)
+		if port.Name == resources.ServiceHTTPPortName || portNames[port.Name] {
+			result = append(result, fmt.Sprintf("invalid spec.service.ports: port name %s should be unique", port.Name))
+		}
+		portNames[port.Name] = true
+
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		portKey := fmt.Sprintf("%d/%s", port.Port, protocol)
+		if ports[portKey] {
+			result = append(result, fmt.Sprintf("invalid spec.service.ports: port %s should be unique", portKey))
+		}
+		ports[portKey] = true
+	}
+
+	errs := field.ErrorList{}
+	errs = append(errs, v1validation.ValidateLabels(svcConfig.Labels, field.NewPath("spec.service.labels"))...)
+	errs = append(errs, validation.ValidateAnnotations(svcConfig.Annotations, field.NewPath("spec.service.annotations"))...)
+	for _, err := range errs {
+		result = append(result, err.Error())
+	}
+	return result
+}
+
+func (v *validator) validateExpose() []string {
+	expose := v.instance.Spec.Expose
+	result := []string{}
+	if expose == nil {
+		return result
+	}
+
+	result = append(result, enrichErrors(utilvalidation.IsDNS1123Subdomain(expose.Host), "spec.expose.host", expose.Host)...)
+	if !strings.Contains(expose.Host, ".") && v.fnConfig.ExposeDomain == "" {
+		result = append(result, fmt.Sprintf("invalid spec.expose.host: %s should be a fully qualified domain name, because no default domain is configured", expose.Host))
+	}
+	if expose.Path != "" && !strings.HasPrefix(expose.Path, "/") {
+		result = append(result, fmt.Sprintf("invalid spec.expose.path: %s should start with /", expose.Path))
+	}
+	for _, method := range expose.Methods {
+		if !slices.Contains(exposeMethods, method) {
+			result = append(result, fmt.Sprintf("invalid spec.expose.methods: %s should be one of %s", method, exposeMethods))
+		}
+	}
+	if expose.Gateway != "" {
+		namespace, name, found := strings.Cut(expose.Gateway, "/")
+		if !found || len(utilvalidation.IsDNS1123Label(namespace)) > 0 || len(utilvalidation.IsDNS1123Subdomain(name)) > 0 {
+			result = append(result, fmt.Sprintf("invalid spec.expose.gateway: %s should be in the {NAMESPACE}/{NAME} format", expose.Gateway))
+		}
+	}
+	return result
+}
+
+func (v *validator) validateSchedules() []string {
+	result := []string{}
+	for _, schedule := range v.instance.Spec.Schedules {
+		result = append(result, enrichErrors(utilvalidation.IsDNS1123Label(schedule.Name), "spec.schedules.name", schedule.Name)...)
+		cronJobName := resources.ScheduleCronJobName(v.instance, schedule.Name)
+		if len(cronJobName) > resources.MaxScheduleCronJobNameLength {
+			result = append(result, fmt.Sprintf("invalid spec.schedules.name: %s is too long, the %s CronJob name should be at most %d characters",
+				schedule.Name, cronJobName, resources.MaxScheduleCronJobNameLength))
+		}
+		if err := validateCronSchedule(schedule.Schedule); err != nil {
+			result = append(result, fmt.Sprintf("invalid spec.schedules.schedule: %s %s", schedule.Schedule, err.Error()))
+		}
+		if schedule.TimeZone != nil {
+			if _, err := time.LoadLocation(*schedule.TimeZone); err != nil || *schedule.TimeZone == "" {
+				result = append(result, fmt.Sprintf("invalid spec.schedules.timeZone: %s is not a known time zone", *schedule.TimeZone))
+			}
+		}
+		if schedule.Payload != "" && !json.Valid([]byte(schedule.Payload)) {
+			result = append(result, fmt.Sprintf("invalid spec.schedules.payload: payload of the %s schedule should be a valid JSON", schedule.Name))
+		}
+	}
+	return result
+}
+
+// validateCronSchedule checks if the schedule uses one of the predefined macros or consists of five cron fields
+func validateCronSchedule(schedule string) error {
+	if strings.HasPrefix(schedule, "TZ=") || strings.HasPrefix(schedule, "CRON_TZ=") {
+		return errors.New("should not specify the time zone, use spec.schedules.timeZone instead")
+	}
+	if strings.HasPrefix(schedule, "@") {
+		if !slices.Contains(scheduleMacros, schedule) {
+			return fmt.Errorf("should be one of %s or five cron fields", scheduleMacros)
+		}
+		return nil
+	}
+	fields := strings.Fields(schedule)
+	if len(fields) != 5 {
+		return fmt.Errorf("should have five cron fields, got %d", len(fields))
+	}
+	for _, f := range fields {
+		if !cronFieldRegex.MatchString(f) {
+			return fmt.Errorf("contains the invalid cron field %s", f)
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateEvents() []string {
+	result := []string{}
+	for _, event := range v.instance.Spec.Events {
+		result = append(result, enrichErrors(utilvalidation.IsDNS1123Label(event.Name), "spec.events.name", event.Name)...)
+		subscriptionName := resources.SubscriptionName(v.instance, event.Name)
+		if len(subscriptionName) > utilvalidation.DNS1123LabelMaxLength {
+			result = append(result, fmt.Sprintf("invalid spec.events.name: %s is too long, the %s Subscription name should be at most %d characters",
+				event.Name, subscriptionName, utilvalidation.DNS1123LabelMaxLength))
+		}
+		for i, eventType := range event.Types {
+			if strings.TrimSpace(eventType) == "" {
+				result = append(result, fmt.Sprintf("invalid spec.events.types: types of the %s events should not be empty", event.Name))
+			} else if slices.Contains(event.Types[:i], eventType) {
+				result = append(result, fmt.Sprintf("invalid spec.events.types: %s is duplicated in the %s events", eventType, event.Name))
+			}
+		}
+	}
+	return result
+}
+
+func (v *validator) validateRequestLimits() []string {
+	spec := v.instance.Spec
+	result := []string{}
+	if spec.TimeoutSeconds != nil {
+		timeout := *spec.TimeoutSeconds
+		if timeout < minTimeoutSeconds || timeout > maxTimeoutSeconds {
+			result = append(result, fmt.Sprintf("invalid spec.timeoutSeconds: %d should be between %d and %d",
+				timeout, minTimeoutSeconds, maxTimeoutSeconds))
+		}
+		if envIsSet(spec.Env, resources.FunctionTimeoutEnvName) {
+			result = append(result, fmt.Sprintf("invalid spec.env: %s can't be used together with spec.timeoutSeconds",
+				resources.FunctionTimeoutEnvName))
+		}
+	}
+	if spec.MaxRequestBodySize != nil {
+		size := *spec.MaxRequestBodySize
+		if size.Cmp(minRequestBodySize) == -1 || size.Cmp(maxRequestBodySize) == 1 {
+			result = append(result, fmt.Sprintf("invalid spec.maxRequestBodySize: %s should be between %s and %s",
+				size.String(), minRequestBodySize.String(), maxRequestBodySize.String()))
+		} else if size.Value()%minRequestBodySize.Value() != 0 {
+			result = append(result, fmt.Sprintf("invalid spec.maxRequestBodySize: %s should be a multiple of %s",
+				size.String(), minRequestBodySize.String()))
+		}
+		if envIsSet(spec.Env, resources.FunctionRequestBodyLimitEnvName) {
+			result = append(result, fmt.Sprintf("invalid spec.env: %s can't be used together with spec.maxRequestBodySize",
+				resources.FunctionRequestBodyLimitEnvName))
+		}
+	}
+	return result
+}
+
+func validateKeyProjections(items []corev1.KeyToPath) []string {
+	result := []string{}
+	for _, item := range items {
+		result = append(result, utilvalidation.IsConfigMapKey(item.Key)...)
+		result = append(result, validateRelativePath(item.Path)...)
+		if item.Mode != nil && (*item.Mode < 0 || *item.Mode > 0777) {
+			result = append(result, fmt.Sprintf("mode %o of key %s must be between 0 and 0777", *item.Mode, item.Key))
+		}
+	}
+	return result
+}
+
+func validateRelativePath(p string) []string {
+	if p == "" {
+		return []string{"path must not be empty"}
+	}
+	if path.IsAbs(p) {
+		return []string{fmt.Sprintf("path %s must be relative", p)}
+	}
+	for _, segment := range strings.Split(p, "/") {
+		if segment == ".." {
+			return []string{fmt.Sprintf("path %s must not contain '..'", p)}
+		}
+	}
+	return []string{}
+}
+
+// pathsOverlap returns true when both paths are equal or one of them is nested in the other
+func pathsOverlap(a, b string) bool {
+	a, b = path.Clean(a), path.Clean(b)
+	return a == b ||
+		strings.HasPrefix(a, strings.TrimSuffix(b, "/")+"/") ||
+		strings.HasPrefix(b, strings.TrimSuffix(a, "/")+"/")
+}
+
+func envIsSet(envs []corev1.EnvVar, name string) bool {
+	for _, env := range envs {
+		if env.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func errorListToStrings(errs field.ErrorList) []string {
+	result := []string{}
+	for _, err := range errs {
+		if err != nil {
+			result = append(result, err.Error())
+		}
+	}
+	return result
+}
+
 func validateDependencies(runtime serverlessv1alpha2.Runtime, dependencies string) error {
 	if runtime.IsRuntimeNodejs() {
 		return validateNodeJSDependencies(dependencies)
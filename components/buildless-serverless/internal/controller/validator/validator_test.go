@@ -11,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 func mockFipsChecker(enabled bool) fips.FipsChecker {
@@ -108,6 +109,67 @@ func Test_functionValidator_validateEnvs(t *testing.T) {
 	}
 }
 
+func Test_functionValidator_validateEnvOverrides(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want []string
+	}{
+		{
+			name: "when overridable envs are set then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Env: []corev1.EnvVar{
+					{Name: "TRACE_COLLECTOR_ENDPOINT", Value: "http://collector:4318"},
+					{Name: "PUBLISHER_PROXY_ADDRESS", Value: "http://publisher"},
+					{Name: "PYTHONUNBUFFERED", Value: "FALSE"},
+					{Name: "FUNC_TIMEOUT", Value: "30"},
+				},
+				EnvFrom: []corev1.EnvFromSource{
+					{Prefix: "CONFIG_", ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "hungry-bardeen"}}},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when system envs are overridden then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				Env: []corev1.EnvVar{
+					{Name: "FUNC_NAME", Value: "brave-ptolemy"},
+					{Name: "HANDLER_PATH", Value: "./brave-ptolemy.js"},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when env from is invalid then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.NodeJs22,
+				EnvFrom: []corev1.EnvFromSource{
+					{Prefix: "1CONFIG_", ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "hungry-bardeen"}}},
+					{},
+				},
+			},
+			want: []string{
+				"spec.envFrom.prefix: 1CONFIG_. Err: a valid environment variable name must consist of alphabetic characters, digits, '_', '-', or '.', and must not start with a digit (e.g. 'my.env-name',  or 'MY_ENV.NAME',  or 'MyEnvName1', regex used for validation is '[-._a-zA-Z][-._a-zA-Z0-9]*')",
+				"invalid spec.envFrom: use exactly one of configMapRef or secretRef",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &serverlessv1alpha2.Function{
+				Spec: tt.spec,
+			}
+
+			v := New(f, config.FunctionConfig{}, mockFipsChecker(false))
+			r := v.validateEnvs()
+			require.ElementsMatch(t, tt.want, r)
+		})
+	}
+}
+
 func Test_functionValidator_validateInlineDeps(t *testing.T) {
 	tests := []struct {
 		name string
@@ -259,6 +321,15 @@ func Test_validator_validateSecretMounts(t *testing.T) {
 				"invalid spec.secretMounts: [secretNames should be unique]",
 			},
 		},
+		{
+			name: "when secret name uses the generated volume prefix then return error",
+			secretMounts: []serverlessv1alpha2.SecretMount{
+				{SecretName: "fn-configmap-0"},
+			},
+			want: []string{
+				"invalid spec.secretMounts: [secretName fn-configmap-0 must not start with fn- reserved for generated volumes]",
+			},
+		},
 		{
 			name: "when secret names are valid and unique then no errors",
 			secretMounts: []serverlessv1alpha2.SecretMount{
@@ -671,3 +742,865 @@ func Test_validator_validateFunctionResources(t *testing.T) {
 		})
 	}
 }
+
+func Test_validator_validateNodeSelector(t *testing.T) {
+	tests := []struct {
+		name         string
+		nodeSelector map[string]string
+		want         []string
+	}{
+		{
+			name:         "when no node selector then no errors",
+			nodeSelector: nil,
+			want:         []string{},
+		},
+		{
+			name: "when valid node selector then no errors",
+			nodeSelector: map[string]string{
+				"cloud.google.com/gke-spot": "true",
+			},
+			want: []string{},
+		},
+		{
+			name: "when invalid node selector key then return error",
+			nodeSelector: map[string]string{
+				"pool/spot/invalid": "true",
+			},
+			want: []string{
+				"spec.nodeSelector: Invalid value: \"pool/spot/invalid\": a valid label key must consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyName',  or 'my.name',  or '123-abc', regex used for validation is '([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]') with an optional DNS subdomain prefix and '/' (e.g. 'example.com/MyName')",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						NodeSelector: tt.nodeSelector,
+					},
+				},
+			}
+			got := v.validateNodeSelector()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateClassNames(t *testing.T) {
+	t.Run("when class names are valid then no errors", func(t *testing.T) {
+		v := &validator{
+			instance: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					PriorityClassName: "high-priority",
+					RuntimeClassName:  ptr.To("gvisor"),
+				},
+			},
+		}
+
+		require.Empty(t, v.validatePriorityClassName())
+		require.Empty(t, v.validateRuntimeClassName())
+	})
+	t.Run("when class names are invalid then return errors", func(t *testing.T) {
+		v := &validator{
+			instance: &serverlessv1alpha2.Function{
+				Spec: serverlessv1alpha2.FunctionSpec{
+					PriorityClassName: "High_Priority",
+					RuntimeClassName:  ptr.To("G_Visor"),
+				},
+			},
+		}
+
+		require.Equal(t, []string{
+			"spec.priorityClassName: High_Priority. Err: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+		}, v.validatePriorityClassName())
+		require.Equal(t, []string{
+			"spec.runtimeClassName: G_Visor. Err: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+		}, v.validateRuntimeClassName())
+	})
+}
+
+func Test_validator_validateServiceAccount(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want []string
+	}{
+		{
+			name: "when service account is not configured then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{},
+			want: []string{},
+		},
+		{
+			name: "when service account settings are valid then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{
+					RoleRef: &serverlessv1alpha2.ServiceAccountRoleRef{Kind: "ClusterRole", Name: "view"},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when role is bound then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{
+					RoleRef: &serverlessv1alpha2.ServiceAccountRoleRef{Kind: "Role", Name: "admin"},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when service account name is invalid then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccountName: "Sleepy_Shannon",
+			},
+			want: []string{
+				"spec.serviceAccountName: Sleepy_Shannon. Err: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')",
+			},
+		},
+		{
+			name: "when cluster role is not allowed then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{
+					RoleRef: &serverlessv1alpha2.ServiceAccountRoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+				},
+			},
+			want: []string{
+				"invalid spec.serviceAccount.roleRef: ClusterRole cluster-admin can't be bound, allowed ClusterRoles: [view]",
+			},
+		},
+		{
+			name: "when role is not allowed then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{
+					RoleRef: &serverlessv1alpha2.ServiceAccountRoleRef{Kind: "Role", Name: "secret-reader"},
+				},
+			},
+			want: []string{
+				"invalid spec.serviceAccount.roleRef: Role secret-reader can't be bound, allowed Roles: [admin]",
+			},
+		},
+		{
+			name: "when role name is invalid then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				ServiceAccount: &serverlessv1alpha2.FunctionServiceAccount{
+					RoleRef: &serverlessv1alpha2.ServiceAccountRoleRef{Kind: "Role", Name: "silly/shirley"},
+				},
+			},
+			want: []string{
+				"invalid spec.serviceAccount.roleRef.name: may not contain '/'",
+				"invalid spec.serviceAccount.roleRef: Role silly/shirley can't be bound, allowed Roles: [admin]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: tt.spec,
+				},
+				fnConfig: config.FunctionConfig{
+					BindableClusterRoles: []string{"view"},
+					BindableRoles:        []string{"admin"},
+				},
+			}
+			got := v.validateServiceAccount()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateService(t *testing.T) {
+	tests := []struct {
+		name      string
+		svcConfig *serverlessv1alpha2.FunctionService
+		want      []string
+	}{
+		{
+			name:      "when service is not configured then no errors",
+			svcConfig: nil,
+			want:      []string{},
+		},
+		{
+			name: "when service settings are valid then no errors",
+			svcConfig: &serverlessv1alpha2.FunctionService{
+				Type: corev1.ServiceTypeLoadBalancer,
+				Ports: []serverlessv1alpha2.FunctionServicePort{
+					{Name: "metrics", Port: 9090},
+					{Name: "dns", Port: 80, Protocol: corev1.ProtocolUDP},
+				},
+				Annotations: map[string]string{"service.beta.kubernetes.io/aws-load-balancer-internal": "true"},
+				Labels:      map[string]string{"app": "frosty-fermat"},
+			},
+			want: []string{},
+		},
+		{
+			name: "when headless service is not ClusterIP then return error",
+			svcConfig: &serverlessv1alpha2.FunctionService{
+				Type:     corev1.ServiceTypeNodePort,
+				Headless: true,
+			},
+			want: []string{
+				"invalid spec.service.headless: Service of the NodePort type can't be headless",
+			},
+		},
+		{
+			name: "when ports are duplicated then return error",
+			svcConfig: &serverlessv1alpha2.FunctionService{
+				Ports: []serverlessv1alpha2.FunctionServicePort{
+					{Name: "http", Port: 8081},
+					{Name: "web", Port: 80},
+					{Name: "web", Port: 8082},
+				},
+			},
+			want: []string{
+				"invalid spec.service.ports: port name http should be unique",
+				"invalid spec.service.ports: port 80/TCP should be unique",
+				"invalid spec.service.ports: port name web should be unique",
+			},
+		},
+		{
+			name: "when port of headless service is duplicated then return error",
+			svcConfig: &serverlessv1alpha2.FunctionService{
+				Headless: true,
+				Ports: []serverlessv1alpha2.FunctionServicePort{
+					{Name: "web", Port: 80},
+					{Name: "admin", Port: 8080},
+				},
+			},
+			want: []string{
+				"invalid spec.service.ports: port 8080/TCP should be unique",
+			},
+		},
+		{
+			name: "when port name is invalid then return error",
+			svcConfig: &serverlessv1alpha2.FunctionService{
+				Ports: []serverlessv1alpha2.FunctionServicePort{
+					{Name: "Jolly_Jang", Port: 8081},
+				},
+			},
+			want: []string{
+				"spec.service.ports.name: Jolly_Jang. Err: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')",
+			},
+		},
+		{
+			name: "when labels and annotations are invalid then return error",
+			svcConfig: &serverlessv1alpha2.FunctionService{
+				Annotations: map[string]string{"/wrong": "value"},
+				Labels:      map[string]string{"label": "-wrong"},
+			},
+			want: []string{
+				"spec.service.labels: Invalid value: \"-wrong\": a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')",
+				"spec.service.annotations: Invalid value: \"/wrong\": prefix part must be non-empty",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Service: tt.svcConfig,
+					},
+				},
+			}
+			got := v.validateService()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateExpose(t *testing.T) {
+	tests := []struct {
+		name   string
+		expose *serverlessv1alpha2.FunctionExpose
+		domain string
+		want   []string
+	}{
+		{
+			name:   "when expose is not configured then no errors",
+			expose: nil,
+			want:   []string{},
+		},
+		{
+			name: "when expose settings are valid then no errors",
+			expose: &serverlessv1alpha2.FunctionExpose{
+				Host:    "peaceful-pike",
+				Path:    "/api",
+				Methods: []string{"GET", "POST"},
+				Gateway: "kyma-system/kyma-gateway",
+			},
+			domain: "example.com",
+			want:   []string{},
+		},
+		{
+			name: "when host is short and no domain is configured then return error",
+			expose: &serverlessv1alpha2.FunctionExpose{
+				Host: "peaceful-pike",
+			},
+			want: []string{
+				"invalid spec.expose.host: peaceful-pike should be a fully qualified domain name, because no default domain is configured",
+			},
+		},
+		{
+			name: "when expose settings are invalid then return errors",
+			expose: &serverlessv1alpha2.FunctionExpose{
+				Host:    "peaceful-pike.example.com",
+				Path:    "api",
+				Methods: []string{"FETCH"},
+				Gateway: "kyma-gateway",
+			},
+			want: []string{
+				"invalid spec.expose.path: api should start with /",
+				"invalid spec.expose.methods: FETCH should be one of [GET HEAD POST PUT PATCH DELETE OPTIONS]",
+				"invalid spec.expose.gateway: kyma-gateway should be in the {NAMESPACE}/{NAME} format",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Expose: tt.expose,
+					},
+				},
+				fnConfig: config.FunctionConfig{
+					ExposeDomain: tt.domain,
+				},
+			}
+			got := v.validateExpose()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateSchedules(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedules []serverlessv1alpha2.FunctionSchedule
+		want      []string
+	}{
+		{
+			name:      "when schedules are not configured then no errors",
+			schedules: nil,
+			want:      []string{},
+		},
+		{
+			name: "when schedules are valid then no errors",
+			schedules: []serverlessv1alpha2.FunctionSchedule{
+				{
+					Name:     "nightly",
+					Schedule: "0 2 * * *",
+					TimeZone: ptr.To("Europe/Warsaw"),
+					Payload:  `{"report":"daily"}`,
+				},
+				{
+					Name:     "hourly",
+					Schedule: "@hourly",
+				},
+				{
+					Name:     "workdays",
+					Schedule: "*/15 8-16 * * MON-FRI",
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when schedules are invalid then return errors",
+			schedules: []serverlessv1alpha2.FunctionSchedule{
+				{
+					Name:     "Nightly",
+					Schedule: "0 2 * *",
+				},
+				{
+					Name:     "tz",
+					Schedule: "CRON_TZ=UTC 0 2 * * *",
+					TimeZone: ptr.To("Mars/Olympus"),
+					Payload:  "report",
+				},
+				{
+					Name:     "macro",
+					Schedule: "@fortnightly",
+				},
+				{
+					Name:     "extremely-long-schedule-name-exceeding-the-limit",
+					Schedule: "0 2 * * $",
+				},
+			},
+			want: []string{
+				"spec.schedules.name: Nightly. Err: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')",
+				"invalid spec.schedules.schedule: 0 2 * * should have five cron fields, got 4",
+				"invalid spec.schedules.schedule: CRON_TZ=UTC 0 2 * * * should not specify the time zone, use spec.schedules.timeZone instead",
+				"invalid spec.schedules.timeZone: Mars/Olympus is not a known time zone",
+				"invalid spec.schedules.payload: payload of the tz schedule should be a valid JSON",
+				"invalid spec.schedules.schedule: @fortnightly should be one of [@yearly @annually @monthly @weekly @daily @midnight @hourly] or five cron fields",
+				"invalid spec.schedules.name: extremely-long-schedule-name-exceeding-the-limit is too long, the pensive-poitras-extremely-long-schedule-name-exceeding-the-limit CronJob name should be at most 52 characters",
+				"invalid spec.schedules.schedule: 0 2 * * $ contains the invalid cron field $",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pensive-poitras",
+					},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Schedules: tt.schedules,
+					},
+				},
+			}
+			got := v.validateSchedules()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateEvents(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []serverlessv1alpha2.FunctionEventSubscription
+		want   []string
+	}{
+		{
+			name:   "when events are not configured then no errors",
+			events: nil,
+			want:   []string{},
+		},
+		{
+			name: "when events are valid then no errors",
+			events: []serverlessv1alpha2.FunctionEventSubscription{
+				{
+					Name:   "orders",
+					Source: "commerce",
+					Types:  []string{"order.created.v1", "order.updated.v1"},
+				},
+				{
+					Name:         "payments",
+					Source:       "payment-gateway",
+					Types:        []string{"sap.kyma.custom.payment.received.v1"},
+					TypeMatching: serverlessv1alpha2.EventTypeMatchingExact,
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when events are invalid then return errors",
+			events: []serverlessv1alpha2.FunctionEventSubscription{
+				{
+					Name:   "Orders",
+					Source: "commerce",
+					Types:  []string{"order.created.v1", " ", "order.created.v1"},
+				},
+				{
+					Name:   "very-long-subscription-name-exceeding-the-limit-of-dns-label",
+					Source: "commerce",
+					Types:  []string{"order.deleted.v1"},
+				},
+			},
+			want: []string{
+				"spec.events.name: Orders. Err: a lowercase RFC 1123 label must consist of lower case alphanumeric characters or '-', and must start and end with an alphanumeric character (e.g. 'my-name',  or '123-abc', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?')",
+				"invalid spec.events.types: types of the Orders events should not be empty",
+				"invalid spec.events.types: order.created.v1 is duplicated in the Orders events",
+				"invalid spec.events.name: very-long-subscription-name-exceeding-the-limit-of-dns-label is too long, the pensive-poitras-very-long-subscription-name-exceeding-the-limit-of-dns-label Subscription name should be at most 63 characters",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "pensive-poitras",
+					},
+					Spec: serverlessv1alpha2.FunctionSpec{
+						Events: tt.events,
+					},
+				},
+			}
+			got := v.validateEvents()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateRequestLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want []string
+	}{
+		{
+			name: "when no request limits then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{},
+			want: []string{},
+		},
+		{
+			name: "when request limits are in bounds then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				TimeoutSeconds:     ptr.To[int32](600),
+				MaxRequestBodySize: ptr.To(resource.MustParse("10Mi")),
+			},
+			want: []string{},
+		},
+		{
+			name: "when request limits are out of bounds then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				TimeoutSeconds:     ptr.To[int32](0),
+				MaxRequestBodySize: ptr.To(resource.MustParse("1Gi")),
+			},
+			want: []string{
+				"invalid spec.timeoutSeconds: 0 should be between 1 and 3600",
+				"invalid spec.maxRequestBodySize: 1Gi should be between 1Mi and 100Mi",
+			},
+		},
+		{
+			name: "when request body size is not a multiple of 1Mi then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				MaxRequestBodySize: ptr.To(resource.MustParse("1500Ki")),
+			},
+			want: []string{
+				"invalid spec.maxRequestBodySize: 1500Ki should be a multiple of 1Mi",
+			},
+		},
+		{
+			name: "when request limits are also set as envs then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				TimeoutSeconds:     ptr.To[int32](60),
+				MaxRequestBodySize: ptr.To(resource.MustParse("2Mi")),
+				Env: []corev1.EnvVar{
+					{Name: "FUNC_TIMEOUT", Value: "30"},
+					{Name: "REQ_MB_LIMIT", Value: "3"},
+				},
+			},
+			want: []string{
+				"invalid spec.env: FUNC_TIMEOUT can't be used together with spec.timeoutSeconds",
+				"invalid spec.env: REQ_MB_LIMIT can't be used together with spec.maxRequestBodySize",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: tt.spec,
+				},
+			}
+			got := v.validateRequestLimits()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateConfigMapMounts(t *testing.T) {
+	tests := []struct {
+		name            string
+		configMapMounts []serverlessv1alpha2.ConfigMapMount
+		want            []string
+	}{
+		{
+			name:            "when no config map mounts then no errors",
+			configMapMounts: []serverlessv1alpha2.ConfigMapMount{},
+			want:            []string{},
+		},
+		{
+			name: "when config map mounts are valid then no errors",
+			configMapMounts: []serverlessv1alpha2.ConfigMapMount{
+				{
+					ConfigMapName: "festive-hopper",
+					MountPath:     "/config",
+					Items:         []corev1.KeyToPath{{Key: "app.yaml", Path: "nested/app.yaml", Mode: ptr.To[int32](0400)}},
+					DefaultMode:   ptr.To[int32](0440),
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when config map mount is invalid then return error",
+			configMapMounts: []serverlessv1alpha2.ConfigMapMount{
+				{
+					ConfigMapName: "festive-hopper",
+					MountPath:     "/config",
+					Items:         []corev1.KeyToPath{{Key: "app.yaml", Path: "../app.yaml", Mode: ptr.To[int32](01000)}},
+					DefaultMode:   ptr.To[int32](-1),
+				},
+			},
+			want: []string{
+				"invalid spec.configMapMounts: [path ../app.yaml must not contain '..' mode 1000 of key app.yaml must be between 0 and 0777 defaultMode -1 must be between 0 and 0777]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						ConfigMapMounts: tt.configMapMounts,
+					},
+				},
+			}
+			got := v.validateConfigMapMounts()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateServiceAccountTokenMounts(t *testing.T) {
+	tests := []struct {
+		name        string
+		tokenMounts []serverlessv1alpha2.ServiceAccountTokenMount
+		want        []string
+	}{
+		{
+			name: "when token mounts are valid then no errors",
+			tokenMounts: []serverlessv1alpha2.ServiceAccountTokenMount{
+				{MountPath: "/var/run/secrets/tokens", Audience: "vault", ExpirationSeconds: ptr.To[int64](600), Path: "vault-token"},
+			},
+			want: []string{},
+		},
+		{
+			name: "when token mount is invalid then return error",
+			tokenMounts: []serverlessv1alpha2.ServiceAccountTokenMount{
+				{MountPath: "/var/run/secrets/tokens", ExpirationSeconds: ptr.To[int64](60), Path: "/token"},
+			},
+			want: []string{
+				"invalid spec.serviceAccountTokenMounts: [expirationSeconds 60 must be at least 600 path /token must be relative]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: serverlessv1alpha2.FunctionSpec{
+						ServiceAccountTokenMounts: tt.tokenMounts,
+					},
+				},
+			}
+			got := v.validateServiceAccountTokenMounts()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateMountPaths(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want []string
+	}{
+		{
+			name: "when mount paths are distinct then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime:                   serverlessv1alpha2.NodeJs22,
+				SecretMounts:              []serverlessv1alpha2.SecretMount{{SecretName: "wizardly-mayer", MountPath: "/secret"}},
+				ConfigMapMounts:           []serverlessv1alpha2.ConfigMapMount{{ConfigMapName: "wizardly-mayer", MountPath: "/config"}},
+				ServiceAccountTokenMounts: []serverlessv1alpha2.ServiceAccountTokenMount{{MountPath: "/token"}},
+			},
+			want: []string{},
+		},
+		{
+			name: "when mount paths are duplicated then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime:                   serverlessv1alpha2.NodeJs22,
+				SecretMounts:              []serverlessv1alpha2.SecretMount{{SecretName: "wizardly-mayer", MountPath: "/data"}},
+				ConfigMapMounts:           []serverlessv1alpha2.ConfigMapMount{{ConfigMapName: "wizardly-mayer", MountPath: "/data/"}},
+				ServiceAccountTokenMounts: []serverlessv1alpha2.ServiceAccountTokenMount{{MountPath: "/data"}},
+			},
+			want: []string{
+				"invalid spec.configMapMounts: mountPath /data/ is used by another mount",
+				"invalid spec.serviceAccountTokenMounts: mountPath /data is used by another mount",
+			},
+		},
+		{
+			name: "when mount paths collide with reserved mounts then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime: serverlessv1alpha2.Python312,
+				Source: serverlessv1alpha2.Source{
+					GitRepository: &serverlessv1alpha2.GitRepositorySource{URL: "https://github.com/kyma-project/serverless.git"},
+				},
+				ConfigMapMounts: []serverlessv1alpha2.ConfigMapMount{
+					{ConfigMapName: "jolly-goldberg", MountPath: "/tmp"},
+					{ConfigMapName: "jolly-goldberg", MountPath: "/kubeless/config"},
+				},
+				ServiceAccountTokenMounts: []serverlessv1alpha2.ServiceAccountTokenMount{{MountPath: "/"}},
+				Volumes: []serverlessv1alpha2.FunctionVolume{
+					{Name: "scratch", MountPath: "/git-repository/scratch", EmptyDir: &serverlessv1alpha2.EmptyDirVolume{}},
+				},
+			},
+			want: []string{
+				"invalid spec.volumes: mountPath /git-repository/scratch collides with the reserved git-repository mount (/git-repository)",
+				"invalid spec.configMapMounts: mountPath /tmp collides with the reserved tmp mount (/tmp)",
+				"invalid spec.configMapMounts: mountPath /kubeless/config collides with the reserved sources mount (/kubeless)",
+				"invalid spec.serviceAccountTokenMounts: mountPath / collides with the reserved sources mount (/kubeless)",
+				"invalid spec.serviceAccountTokenMounts: mountPath / collides with the reserved tmp mount (/tmp)",
+				"invalid spec.serviceAccountTokenMounts: mountPath / collides with the reserved git-repository mount (/git-repository)",
+			},
+		},
+		{
+			name: "when secret mount uses reserved path then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Runtime:      serverlessv1alpha2.NodeJs22,
+				SecretMounts: []serverlessv1alpha2.SecretMount{{SecretName: "wizardly-mayer", MountPath: "/tmp/secret"}},
+			},
+			want: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: tt.spec,
+				},
+			}
+			got := v.validateMountPaths()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateVolumes(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want []string
+	}{
+		{
+			name: "when volumes are valid then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Volumes: []serverlessv1alpha2.FunctionVolume{
+					{
+						Name:      "scratch",
+						MountPath: "/scratch",
+						EmptyDir: &serverlessv1alpha2.EmptyDirVolume{
+							SizeLimit: ptr.To(resource.MustParse("2Gi")),
+							InMemory:  true,
+						},
+					},
+					{
+						Name:      "data",
+						MountPath: "/data",
+						PersistentVolumeClaim: &serverlessv1alpha2.PersistentVolumeClaimVolume{
+							ClaimName: "eloquent-mclean",
+						},
+					},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when volumes are invalid then return error",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Volumes: []serverlessv1alpha2.FunctionVolume{
+					{
+						Name:      "data",
+						MountPath: "/data",
+						EmptyDir: &serverlessv1alpha2.EmptyDirVolume{
+							SizeLimit: ptr.To(resource.MustParse("0")),
+						},
+					},
+					{
+						Name:      "data",
+						MountPath: "/other-data",
+						PersistentVolumeClaim: &serverlessv1alpha2.PersistentVolumeClaimVolume{
+							ClaimName: "eloquent-mclean",
+						},
+					},
+					{
+						Name:      "fn-cache",
+						MountPath: "/cache",
+						EmptyDir:  &serverlessv1alpha2.EmptyDirVolume{},
+					},
+				},
+			},
+			want: []string{
+				"invalid spec.volumes: [sizeLimit 0 of volume data must be greater than 0 volume name data should be unique volume name fn-cache must not start with fn- reserved for generated volumes]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: tt.spec,
+				},
+			}
+			got := v.validateVolumes()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func Test_validator_validateContainers(t *testing.T) {
+	tests := []struct {
+		name string
+		spec serverlessv1alpha2.FunctionSpec
+		want []string
+	}{
+		{
+			name: "when containers are valid then no errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Volumes: []serverlessv1alpha2.FunctionVolume{
+					{Name: "cache", MountPath: "/cache", EmptyDir: &serverlessv1alpha2.EmptyDirVolume{}},
+				},
+				Sidecars: []corev1.Container{
+					{
+						Name:         "redis",
+						Image:        "redis:7",
+						Ports:        []corev1.ContainerPort{{ContainerPort: 6379}},
+						VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/data"}},
+					},
+				},
+				InitContainers: []corev1.Container{
+					{Name: "warmup", Image: "busybox:1.36", VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/data"}}},
+				},
+			},
+			want: []string{},
+		},
+		{
+			name: "when containers are invalid then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				Sidecars: []corev1.Container{
+					{
+						Name:         "function",
+						Image:        "redis:7",
+						Ports:        []corev1.ContainerPort{{ContainerPort: 8080}},
+						VolumeMounts: []corev1.VolumeMount{{Name: "sources", MountPath: "/data"}},
+					},
+					{Name: "shipper"},
+				},
+				InitContainers: []corev1.Container{
+					{Name: "shipper", Image: "busybox:1.36"},
+				},
+			},
+			want: []string{
+				"invalid spec.sidecars: [container name function is already used port 8080 of container function is used by the Function volume sources mounted in container function is not defined in spec.volumes image of container shipper must be set]",
+				"invalid spec.initContainers: [container name shipper is already used]",
+			},
+		},
+		{
+			name: "when containers use names of containers set by serverless then return errors",
+			spec: serverlessv1alpha2.FunctionSpec{
+				InitContainers: []corev1.Container{
+					{Name: "init", Image: "busybox:1.36"},
+					{Name: "install", Image: "busybox:1.36"},
+				},
+			},
+			want: []string{
+				"invalid spec.initContainers: [container name init is already used container name install is already used]",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &validator{
+				instance: &serverlessv1alpha2.Function{
+					Spec: tt.spec,
+				},
+			}
+			got := v.validateContainers()
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
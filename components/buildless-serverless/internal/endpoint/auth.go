@@ -0,0 +1,106 @@
+package endpoint
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/pkg/errors"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+const (
+	invokeVerb        = "create"
+	invokeResource    = "functions"
+	invokeSubresource = "invoke"
+
+	logsVerb        = "get"
+	logsResource    = "pods"
+	logsSubresource = "log"
+)
+
+var (
+	errUnauthenticated = errors.New("missing or invalid bearer token")
+)
+
+// authenticate returns the user identified by the request's bearer token
+func (s *Server) authenticate(r *http.Request) (*authenticationv1.UserInfo, error) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		return nil, errUnauthenticated
+	}
+
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token: token,
+		},
+	}
+	if err := s.k8s.Create(r.Context(), review); err != nil {
+		return nil, errors.Wrap(err, "failed to review token")
+	}
+	if !review.Status.Authenticated {
+		return nil, errUnauthenticated
+	}
+	return &review.Status.User, nil
+}
+
+// authorizeInvoke checks if the user is allowed to invoke the Function,
+// which requires the create verb on the functions/invoke subresource
+func (s *Server) authorizeInvoke(r *http.Request, user *authenticationv1.UserInfo, ns, name string) error {
+	allowed, err := s.authorize(r, user, authorizationv1.ResourceAttributes{
+		Namespace:   ns,
+		Name:        name,
+		Verb:        invokeVerb,
+		Group:       v1alpha2.GroupVersion.Group,
+		Resource:    invokeResource,
+		Subresource: invokeSubresource,
+	})
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.Errorf("user '%s' is not allowed to invoke function '%s/%s'", user.Username, ns, name)
+	}
+	return nil
+}
+
+// authorizeLogs checks if the user is allowed to read logs of the Function's Pods,
+// which requires the get verb on the pods/log subresource in the Function's namespace
+func (s *Server) authorizeLogs(r *http.Request, user *authenticationv1.UserInfo, ns, name string) error {
+	allowed, err := s.authorize(r, user, authorizationv1.ResourceAttributes{
+		Namespace:   ns,
+		Verb:        logsVerb,
+		Resource:    logsResource,
+		Subresource: logsSubresource,
+	})
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return errors.Errorf("user '%s' is not allowed to read logs of function '%s/%s'", user.Username, ns, name)
+	}
+	return nil
+}
+
+// authorize reviews the user's access to the resource
+func (s *Server) authorize(r *http.Request, user *authenticationv1.UserInfo, attrs authorizationv1.ResourceAttributes) (bool, error) {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attrs,
+			User:               user.Username,
+			Groups:             user.Groups,
+			UID:                user.UID,
+			Extra:              extra,
+		},
+	}
+	if err := s.k8s.Create(r.Context(), review); err != nil {
+		return false, errors.Wrap(err, "failed to review access")
+	}
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,98 @@
+package endpoint
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint/types"
+)
+
+const (
+	// invocationResultTTL is the time the result of an async invocation can be polled for after it finished
+	invocationResultTTL = 10 * time.Minute
+	// maxInvocations limits the number of stored async invocations, including the running ones
+	maxInvocations = 100
+)
+
+type invocation struct {
+	owner    string
+	result   types.InvocationResponse
+	finished time.Time
+}
+
+// invocationStore keeps async invocations in memory until their results expire,
+// so async invocations are available only when the controller runs a single replica
+type invocationStore struct {
+	mu          sync.Mutex
+	invocations map[string]*invocation
+	now         func() time.Time
+}
+
+func newInvocationStore() *invocationStore {
+	return &invocationStore{
+		invocations: map[string]*invocation{},
+		now:         time.Now,
+	}
+}
+
+// add registers a new pending invocation of the owner, it returns false when the store is full
+func (s *invocationStore) add(owner, ns, name string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneExpired()
+	if len(s.invocations) >= maxInvocations {
+		return "", false
+	}
+
+	id := uuid.NewString()
+	s.invocations[id] = &invocation{
+		owner: owner,
+		result: types.InvocationResponse{
+			ID:        id,
+			Namespace: ns,
+			Name:      name,
+			Status:    types.InvocationStatusPending,
+		},
+	}
+	return id, true
+}
+
+// finish records the result of the invocation
+func (s *invocationStore) finish(id string, result types.InvocationResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inv, ok := s.invocations[id]
+	if !ok {
+		return
+	}
+	result.ID = id
+	result.Namespace = inv.result.Namespace
+	result.Name = inv.result.Name
+	inv.result = result
+	inv.finished = s.now()
+}
+
+// get returns the invocation if it exists and belongs to the owner
+func (s *invocationStore) get(owner, id string) (types.InvocationResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneExpired()
+	inv, ok := s.invocations[id]
+	if !ok || inv.owner != owner {
+		return types.InvocationResponse{}, false
+	}
+	return inv.result, true
+}
+
+func (s *invocationStore) pruneExpired() {
+	now := s.now()
+	for id, inv := range s.invocations {
+		if !inv.finished.IsZero() && now.Sub(inv.finished) > invocationResultTTL {
+			delete(s.invocations, id)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package endpoint
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/controller/resources"
+	"github.com/kyma-project/serverless/components/buildless-serverless/internal/endpoint/types"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	invocationsPath = "/internal/function/invocations/"
+	// invocationBodyLimit limits the size of the request body sent to the Function and the stored async response
+	invocationBodyLimit = 10 * 1024 * 1024
+	// defaultInvocationTimeout matches the default timeout of the runtimes
+	defaultInvocationTimeout = 180 * time.Second
+	// invocationTimeoutMargin gives the Function time to respond with its own timeout error
+	invocationTimeoutMargin = 5 * time.Second
+)
+
+// handleInvokeRequest proxies the request to the Function's Service.
+// With the async parameter the invocation is queued and its result can be polled with handleInvocationRequest.
+func (s *Server) handleInvokeRequest(w http.ResponseWriter, r *http.Request) {
+	ns := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+	path := r.URL.Query().Get("path")
+	async := r.URL.Query().Get("async") == "true"
+
+	s.log.Infof("handling invoke request for function '%s/%s'", ns, name)
+
+	if err := validateFunctionParams(ns, name, ""); err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	if path != "" && !strings.HasPrefix(path, "/") {
+		s.writeErrorResponse(w, http.StatusBadRequest, errors.Errorf("invalid parameter \"path\": %s should start with /", path))
+		return
+	}
+	if async && s.functionConfig.ControllerReplicas > 1 {
+		// results are kept in memory of the replica which started the invocation, polling may reach another replica
+		s.writeErrorResponse(w, http.StatusBadRequest, errors.New("async invocations are not supported when the controller runs multiple replicas"))
+		return
+	}
+
+	user, err := s.authenticate(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := s.authorizeInvoke(r, user, ns, name); err != nil {
+		s.writeErrorResponse(w, http.StatusForbidden, err)
+		return
+	}
+
+	function := v1alpha2.Function{}
+	err = s.k8s.Get(r.Context(), client.ObjectKey{Namespace: ns, Name: name}, &function)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusNotFound, errors.Wrapf(err, "failed to get function '%s/%s'", ns, name))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, invocationBodyLimit))
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusRequestEntityTooLarge, errors.Wrap(err, "failed to read request body"))
+		return
+	}
+
+	if !async {
+		s.invokeSync(w, r, &function, path, body)
+		return
+	}
+
+	id, ok := s.invocations.add(user.Username, ns, name)
+	if !ok {
+		s.writeErrorResponse(w, http.StatusTooManyRequests, errors.New("too many pending invocations, try again later"))
+		return
+	}
+	go s.invokeAsync(id, &function, r.Method, path, r.Header.Clone(), body)
+
+	w.Header().Set("Location", invocationsPath+id)
+	s.writeInvocationResponse(w, http.StatusAccepted, types.InvocationResponse{
+		ID:        id,
+		Namespace: ns,
+		Name:      name,
+		Status:    types.InvocationStatusPending,
+	})
+}
+
+// handleInvocationRequest returns the state of the async invocation started by the same user
+func (s *Server) handleInvocationRequest(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	user, err := s.authenticate(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	result, ok := s.invocations.get(user.Username, id)
+	if !ok {
+		s.writeErrorResponse(w, http.StatusNotFound, errors.Errorf("invocation '%s' not found", id))
+		return
+	}
+	s.writeInvocationResponse(w, http.StatusOK, result)
+}
+
+func (s *Server) invokeSync(w http.ResponseWriter, r *http.Request, function *v1alpha2.Function, path string, body []byte) {
+	ctx, cancel := context.WithTimeout(r.Context(), invocationTimeout(function))
+	defer cancel()
+
+	resp, err := s.callFunction(ctx, function, r.Method, path, r.Header, body)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadGateway, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	copyInvocationHeaders(w.Header(), resp.Header)
+	w.WriteHeader(resp.StatusCode)
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		s.log.Errorf("failed to write response of function '%s/%s': %v", function.Namespace, function.Name, err)
+	}
+}
+
+func (s *Server) invokeAsync(id string, function *v1alpha2.Function, method, path string, header http.Header, body []byte) {
+	ctx, cancel := context.WithTimeout(s.ctx, invocationTimeout(function))
+	defer cancel()
+
+	resp, err := s.callFunction(ctx, function, method, path, header, body)
+	if err != nil {
+		s.invocations.finish(id, types.InvocationResponse{
+			Status: types.InvocationStatusFailed,
+			Error:  err.Error(),
+		})
+		return
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, invocationBodyLimit))
+	if err != nil {
+		s.invocations.finish(id, types.InvocationResponse{
+			Status:     types.InvocationStatusFailed,
+			StatusCode: resp.StatusCode,
+			Error:      errors.Wrap(err, "failed to read function response").Error(),
+		})
+		return
+	}
+	s.invocations.finish(id, types.InvocationResponse{
+		Status:      types.InvocationStatusSucceeded,
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Body:        string(respBody),
+	})
+}
+
+func (s *Server) callFunction(ctx context.Context, function *v1alpha2.Function, method, path string, header http.Header, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, functionURL(function, path), bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build function request")
+	}
+	copyInvocationHeaders(req.Header, header)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to invoke function '%s/%s'", function.Namespace, function.Name)
+	}
+	return resp, nil
+}
+
+func functionURL(function *v1alpha2.Function, path string) string {
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local:%d%s", function.Name, function.Namespace, resources.FunctionServicePort(function), path)
+}
+
+func invocationTimeout(function *v1alpha2.Function) time.Duration {
+	if function.Spec.TimeoutSeconds != nil {
+		return time.Duration(*function.Spec.TimeoutSeconds)*time.Second + invocationTimeoutMargin
+	}
+	return defaultInvocationTimeout + invocationTimeoutMargin
+}
+
+// copyInvocationHeaders copies the content type and CloudEvents headers,
+// the caller's credentials are never passed to the Function
+func copyInvocationHeaders(dst, src http.Header) {
+	for key, values := range src {
+		canonicalKey := http.CanonicalHeaderKey(key)
+		if canonicalKey == "Content-Type" || strings.HasPrefix(canonicalKey, "Ce-") {
+			dst[canonicalKey] = values
+		}
+	}
+}
@@ -0,0 +1,206 @@
+package endpoint
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// logLinesBuffer limits the number of lines read from the Pods but not yet written to the client
+	logLinesBuffer = 100
+	// maxLogLineSize limits the size of a single log line, longer lines are split
+	maxLogLineSize = 64 * 1024
+)
+
+type logOptions struct {
+	container    string
+	follow       bool
+	sinceSeconds *int64
+	tailLines    *int64
+}
+
+type logLine struct {
+	prefix string
+	text   string
+}
+
+// handleLogsRequest streams logs of all containers of the Function's current Pods, including the git init container.
+// Every line is prefixed with the Pod and container name.
+func (s *Server) handleLogsRequest(w http.ResponseWriter, r *http.Request) {
+	ns := r.URL.Query().Get("namespace")
+	name := r.URL.Query().Get("name")
+
+	s.log.Infof("handling logs request for function '%s/%s'", ns, name)
+
+	if err := validateFunctionParams(ns, name, ""); err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+	opts, err := parseLogOptions(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := s.authenticate(r)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusUnauthorized, err)
+		return
+	}
+	if err := s.authorizeLogs(r, user, ns, name); err != nil {
+		s.writeErrorResponse(w, http.StatusForbidden, err)
+		return
+	}
+
+	function := v1alpha2.Function{}
+	err = s.k8s.Get(r.Context(), client.ObjectKey{Namespace: ns, Name: name}, &function)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusNotFound, errors.Wrapf(err, "failed to get function '%s/%s'", ns, name))
+		return
+	}
+
+	// pods are listed directly from the API server to not start a cluster-wide Pod informer in the manager's cache
+	pods, err := s.pods.Pods(ns).List(r.Context(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(function.SelectorLabels()).String(),
+	})
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrapf(err, "failed to list pods of function '%s/%s'", ns, name))
+		return
+	}
+	if len(pods.Items) == 0 {
+		s.writeErrorResponse(w, http.StatusNotFound, errors.Errorf("no pods found for function '%s/%s'", ns, name))
+		return
+	}
+
+	s.streamLogs(w, r.Context(), pods.Items, opts)
+}
+
+func (s *Server) streamLogs(w http.ResponseWriter, ctx context.Context, pods []corev1.Pod, opts logOptions) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan logLine, logLinesBuffer)
+	wg := sync.WaitGroup{}
+	for i := range pods {
+		for _, container := range podContainers(&pods[i], opts.container) {
+			wg.Add(1)
+			go func(pod *corev1.Pod, container string) {
+				defer wg.Done()
+				s.streamContainerLogs(ctx, pod, container, opts, lines)
+			}(&pods[i], container)
+		}
+	}
+	go func() {
+		wg.Wait()
+		close(lines)
+	}()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	for line := range lines {
+		if _, err := fmt.Fprintf(w, "[%s] %s\n", line.prefix, line.text); err != nil {
+			s.log.Debugf("failed to write logs, client disconnected: %v", err)
+			return
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) streamContainerLogs(ctx context.Context, pod *corev1.Pod, container string, opts logOptions, lines chan<- logLine) {
+	prefix := fmt.Sprintf("%s/%s", pod.Name, container)
+	send := func(text string) bool {
+		select {
+		case lines <- logLine{prefix: prefix, text: text}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	stream, err := s.pods.Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:    container,
+		Follow:       opts.follow,
+		SinceSeconds: opts.sinceSeconds,
+		TailLines:    opts.tailLines,
+	}).Stream(ctx)
+	if err != nil {
+		send(fmt.Sprintf("failed to stream logs: %v", err))
+		return
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLogLineSize)
+	for scanner.Scan() {
+		if !send(scanner.Text()) {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		send(fmt.Sprintf("failed to read logs: %v", err))
+	}
+}
+
+// podContainers returns names of the Pod's init containers followed by its containers,
+// optionally limited to the one with the given name
+func podContainers(pod *corev1.Pod, only string) []string {
+	names := []string{}
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, container := range containers {
+			if only == "" || container.Name == only {
+				names = append(names, container.Name)
+			}
+		}
+	}
+	return names
+}
+
+func parseLogOptions(r *http.Request) (logOptions, error) {
+	query := r.URL.Query()
+	opts := logOptions{
+		container: query.Get("container"),
+	}
+
+	if follow := query.Get("follow"); follow != "" {
+		value, err := strconv.ParseBool(follow)
+		if err != nil {
+			return opts, errors.Errorf("invalid parameter \"follow\": %s", follow)
+		}
+		opts.follow = value
+	}
+
+	if since := query.Get("since"); since != "" {
+		duration, err := time.ParseDuration(since)
+		if err != nil || duration <= 0 {
+			return opts, errors.Errorf("invalid parameter \"since\": %s should be a positive duration, for example 5m", since)
+		}
+		// the API server accepts whole seconds only, round up to not skip the beginning of the period
+		seconds := int64((duration + time.Second - 1) / time.Second)
+		opts.sinceSeconds = &seconds
+	}
+
+	if tail := query.Get("tail"); tail != "" {
+		lines, err := strconv.ParseInt(tail, 10, 64)
+		if err != nil || lines < 0 {
+			return opts, errors.Errorf("invalid parameter \"tail\": %s should be a non-negative number", tail)
+		}
+		opts.tailLines = &lines
+	}
+
+	return opts, nil
+}
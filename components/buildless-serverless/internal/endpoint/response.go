@@ -44,3 +44,17 @@ func (s *Server) writeFilesListResponse(w http.ResponseWriter, data []types.File
 	w.Header().Set("Content-Type", "application/json")
 	fmt.Fprint(w, buf.String())
 }
+
+func (s *Server) writeInvocationResponse(w http.ResponseWriter, status int, data types.InvocationResponse) {
+	buf := bytes.NewBuffer([]byte{})
+	err := json.NewEncoder(buf).Encode(data)
+	if err != nil {
+		s.writeErrorResponse(w, http.StatusInternalServerError, errors.Wrap(err, "failed to encode response"))
+		return
+	}
+
+	s.log.Debugf("writing invocation response with status: %s", data.Status)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	fmt.Fprint(w, buf.String())
+}
@@ -24,10 +24,26 @@ func BuildResources(functionConfig *config.FunctionConfig, f *v1alpha2.Function,
 		return nil, errors.Wrapf(err, "failed to build deployment")
 	}
 
-	return []types.FileResponse{
+	files := []types.FileResponse{
 		{Name: "k8s/service.yaml", Data: base64.StdEncoding.EncodeToString(svc)},
 		{Name: "k8s/deployment.yaml", Data: base64.StdEncoding.EncodeToString(deployment)},
-	}, nil
+	}
+
+	if !resources.DedicatedServiceAccountEnabled(f) {
+		return files, nil
+	}
+
+	serviceAccount, roleBinding, err := buildServiceAccountFileData(f, appName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to build service account")
+	}
+
+	files = append(files, types.FileResponse{Name: "k8s/service-account.yaml", Data: base64.StdEncoding.EncodeToString(serviceAccount)})
+	if roleBinding != nil {
+		files = append(files, types.FileResponse{Name: "k8s/role-binding.yaml", Data: base64.StdEncoding.EncodeToString(roleBinding)})
+	}
+
+	return files, nil
 }
 
 func buildServiceFileData(function *v1alpha2.Function, appName string) ([]byte, error) {
@@ -64,6 +80,12 @@ func buildDeploymentFileData(functionConfig *config.FunctionConfig, function *v1
 		deployName = fmt.Sprintf("%s-ejected", function.Name)
 	}
 
+	serviceAccountName := resources.ServiceAccountName(function)
+	if resources.DedicatedServiceAccountEnabled(function) {
+		// the ejected service account is named after the deployment
+		serviceAccountName = deployName
+	}
+
 	deploy := resources.NewDeployment(
 		function,
 		functionConfig,
@@ -77,9 +99,11 @@ func buildDeploymentFileData(functionConfig *config.FunctionConfig, function *v1
 		resources.DeployAppendSelectorLabels(map[string]string{
 			"app.kubernetes.io/instance": deployName,
 		}),
-		resources.DeploySetCmd([]string{}), // clear the command to use the default one from the image
+		resources.DeploySetCmd([]string{}),        // clear the command to use the default one from the image
+		resources.DeploySkipDependenciesInstall(), // dependencies are installed in the ejected image
 		resources.DeploySetImage("image:tag"),
 		resources.DeployUseGeneralEnvs(),
+		resources.DeploySetServiceAccountName(serviceAccountName),
 	).Deployment
 
 	data, err := convertK8SObjectToYaml(deploy)
@@ -90,6 +114,35 @@ func buildDeploymentFileData(functionConfig *config.FunctionConfig, function *v1
 	return data, nil
 }
 
+func buildServiceAccountFileData(function *v1alpha2.Function, appName string) ([]byte, []byte, error) {
+	saName := appName
+	if saName == "" {
+		saName = fmt.Sprintf("%s-ejected", function.Name)
+	}
+
+	sa := resources.NewServiceAccount(
+		function,
+		resources.ServiceAccountSetName(saName),
+		resources.ServiceAccountTrimClusterInfoLabels(),
+	)
+
+	saData, err := convertK8SObjectToYaml(sa.ServiceAccount)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal service account to YAML")
+	}
+
+	if sa.RoleBinding == nil {
+		return saData, nil, nil
+	}
+
+	rbData, err := convertK8SObjectToYaml(sa.RoleBinding)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal role binding to YAML")
+	}
+
+	return saData, rbData, nil
+}
+
 // k8s object are designed to be converted to JSON instead of YAML
 // this function does double convertion (from obj to json and from json to yaml)
 func convertK8SObjectToYaml(obj interface{}) ([]byte, error) {
@@ -8,7 +8,9 @@ import (
 	"github.com/kyma-project/serverless/components/buildless-serverless/api/v1alpha2"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 )
 
 func TestBuildResources(t *testing.T) {
@@ -55,6 +57,112 @@ func TestBuildResources(t *testing.T) {
 		require.Nil(t, files)
 	})
 
+	t.Run("build resources for function with volumes", func(t *testing.T) {
+		files, err := BuildResources(&config.FunctionConfig{}, &v1alpha2.Function{
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: "nodejs24",
+				Source: v1alpha2.Source{
+					Inline: &v1alpha2.InlineSource{
+						Source:       "console.log('Hello World')",
+						Dependencies: "{}",
+					},
+				},
+				Volumes: []v1alpha2.FunctionVolume{
+					{
+						Name:      "scratch",
+						MountPath: "/scratch",
+						EmptyDir: &v1alpha2.EmptyDirVolume{
+							SizeLimit: ptr.To(resource.MustParse("2Gi")),
+						},
+					},
+					{
+						Name:      "data",
+						MountPath: "/data",
+						PersistentVolumeClaim: &v1alpha2.PersistentVolumeClaimVolume{
+							ClaimName: "test-claim",
+						},
+					},
+				},
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+			},
+		}, "", false)
+
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		require.Equal(t, "k8s/deployment.yaml", files[1].Name)
+		deployment, err := base64.StdEncoding.DecodeString(files[1].Data)
+		require.NoError(t, err)
+		require.Contains(t, string(deployment), `        - mountPath: /scratch
+          name: fn-volume-scratch
+        - mountPath: /data
+          name: fn-volume-data
+`)
+		require.Contains(t, string(deployment), `      - emptyDir:
+          sizeLimit: 2Gi
+        name: fn-volume-scratch
+      - name: fn-volume-data
+        persistentVolumeClaim:
+          claimName: test-claim
+`)
+	})
+
+	t.Run("build resources for function with dedicated service account", func(t *testing.T) {
+		files, err := BuildResources(&config.FunctionConfig{}, &v1alpha2.Function{
+			Spec: v1alpha2.FunctionSpec{
+				Runtime: "nodejs24",
+				Source: v1alpha2.Source{
+					Inline: &v1alpha2.InlineSource{
+						Source:       "console.log('Hello World')",
+						Dependencies: "{}",
+					},
+				},
+				ServiceAccount: &v1alpha2.FunctionServiceAccount{
+					RoleRef: &v1alpha2.ServiceAccountRoleRef{
+						Kind: "Role",
+						Name: "test-role",
+					},
+				},
+				AutomountServiceAccountToken: ptr.To(false),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-function",
+				Namespace: "test-namespace",
+			},
+		}, "", false)
+
+		require.NoError(t, err)
+		require.Len(t, files, 4)
+		deployment, err := base64.StdEncoding.DecodeString(files[1].Data)
+		require.NoError(t, err)
+		require.Contains(t, string(deployment), "      automountServiceAccountToken: false\n")
+		require.Contains(t, string(deployment), "      serviceAccountName: test-function-ejected\n")
+		require.Equal(t, "k8s/service-account.yaml", files[2].Name)
+		requireEqualBase64Objects(t, `apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: test-function-ejected
+  namespace: test-namespace
+`, files[2].Data)
+		require.Equal(t, "k8s/role-binding.yaml", files[3].Name)
+		requireEqualBase64Objects(t, `apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: test-function-ejected
+  namespace: test-namespace
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: test-role
+subjects:
+- kind: ServiceAccount
+  name: test-function-ejected
+  namespace: test-namespace
+`, files[3].Data)
+	})
+
 	t.Run("build resources for function with specified app name", func(t *testing.T) {
 		files, err := BuildResources(&config.FunctionConfig{}, &v1alpha2.Function{
 			Spec: v1alpha2.FunctionSpec{
@@ -7,6 +7,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/kyma-project/serverless/components/buildless-serverless/internal/config"
 	"go.uber.org/zap"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -14,22 +15,31 @@ type Server struct {
 	ctx                   context.Context
 	mux                   *mux.Router
 	k8s                   client.Client
+	pods                  corev1client.PodsGetter
 	log                   *zap.SugaredLogger
 	functionConfig        config.FunctionConfig
 	isKymaFipsModeEnabled bool
+	httpClient            *http.Client
+	invocations           *invocationStore
 }
 
-func NewInternalServer(ctx context.Context, log *zap.SugaredLogger, k8s client.Client, functionConfig config.FunctionConfig, isKymaFipsModeEnabled bool) *Server {
+func NewInternalServer(ctx context.Context, log *zap.SugaredLogger, k8s client.Client, pods corev1client.PodsGetter, functionConfig config.FunctionConfig, isKymaFipsModeEnabled bool) *Server {
 	server := &Server{
 		ctx:                   ctx,
 		mux:                   mux.NewRouter(),
 		k8s:                   k8s,
+		pods:                  pods,
 		log:                   log,
 		functionConfig:        functionConfig,
 		isKymaFipsModeEnabled: isKymaFipsModeEnabled,
+		httpClient:            &http.Client{},
+		invocations:           newInvocationStore(),
 	}
 
 	server.mux.HandleFunc("/internal/function/eject/", server.handleFunctionRequest)
+	server.mux.HandleFunc("/internal/function/invoke/", server.handleInvokeRequest).Methods(http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete)
+	server.mux.HandleFunc(invocationsPath+"{id}", server.handleInvocationRequest).Methods(http.MethodGet)
+	server.mux.HandleFunc("/internal/function/logs/", server.handleLogsRequest).Methods(http.MethodGet)
 
 	return server
 }
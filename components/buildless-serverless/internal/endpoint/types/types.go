@@ -13,3 +13,24 @@ type FilesListResponse struct {
 	OutputMessage string         `json:"outputMessage"`
 	Files         []FileResponse `json:"files"`
 }
+
+type InvocationStatus string
+
+const (
+	InvocationStatusPending   InvocationStatus = "Pending"
+	InvocationStatusSucceeded InvocationStatus = "Succeeded"
+	InvocationStatusFailed    InvocationStatus = "Failed"
+)
+
+type InvocationResponse struct {
+	ID        string           `json:"id"`
+	Namespace string           `json:"namespace"`
+	Name      string           `json:"name"`
+	Status    InvocationStatus `json:"status"`
+	// StatusCode is the HTTP status code returned by the Function
+	StatusCode  int    `json:"statusCode,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
+	// Error describes why the Function could not be invoked
+	Error string `json:"error,omitempty"`
+}
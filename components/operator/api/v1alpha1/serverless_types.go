@@ -60,6 +60,28 @@ type ServerlessSpec struct {
 	LogFormat string `json:"logFormat,omitempty"`
 	// Deprecated: No longer has any effect. Network policies are always enabled.
 	EnableNetworkPolicies bool `json:"enableNetworkPolicies,omitempty"`
+	// Sets the maximum number of Functions reconciled concurrently. The default value is `1`
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*$`
+	FunctionMaxConcurrentReconciles string `json:"functionMaxConcurrentReconciles,omitempty"`
+	// Sets the initial delay of requeueing a failing Function, doubled with every failure. The default value is `250ms`
+	FunctionRateLimiterBaseDelay string `json:"functionRateLimiterBaseDelay,omitempty"`
+	// Sets the maximum delay of requeueing a failing Function. The default value is `5m`
+	FunctionRateLimiterMaxDelay string `json:"functionRateLimiterMaxDelay,omitempty"`
+	// Sets how many Functions per second can be requeued. The default value is `10`
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*$`
+	FunctionRateLimiterFrequency string `json:"functionRateLimiterFrequency,omitempty"`
+	// Sets how many Functions can be requeued at once above the frequency. The default value is `100`
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*$`
+	FunctionRateLimiterBurst string `json:"functionRateLimiterBurst,omitempty"`
+	// Sets the number of Function controller replicas. With more than one replica, Functions are split into shards reconciled by different replicas. The default value is `1`
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*$`
+	FunctionControllerReplicas string `json:"functionControllerReplicas,omitempty"`
+	// Sets the number of shards Functions are split into. The default value is the number of Function controller replicas
+	// +kubebuilder:validation:Pattern=`^[1-9][0-9]*$`
+	FunctionShards string `json:"functionShards,omitempty"`
+	// Sets whether the shard of the Function is chosen by its namespace or by its `serverless.kyma-project.io/shard` label. The default value is `namespace`
+	// +kubebuilder:validation:Enum=namespace;label
+	FunctionShardingKey string `json:"functionShardingKey,omitempty"`
 }
 
 type State string
@@ -126,6 +148,15 @@ type ServerlessStatus struct {
 	LogLevel                string `json:"logLevel,omitempty"`
 	LogFormat               string `json:"logFormat,omitempty"`
 
+	MaxConcurrentReconciles string `json:"functionMaxConcurrentReconciles,omitempty"`
+	RateLimiterBaseDelay    string `json:"functionRateLimiterBaseDelay,omitempty"`
+	RateLimiterMaxDelay     string `json:"functionRateLimiterMaxDelay,omitempty"`
+	RateLimiterFrequency    string `json:"functionRateLimiterFrequency,omitempty"`
+	RateLimiterBurst        string `json:"functionRateLimiterBurst,omitempty"`
+	ControllerReplicas      string `json:"functionControllerReplicas,omitempty"`
+	Shards                  string `json:"functionShards,omitempty"`
+	ShardingKey             string `json:"functionShardingKey,omitempty"`
+
 	// Deprecated: No longer has any effect.
 	DockerRegistry string `json:"dockerRegistry,omitempty"`
 
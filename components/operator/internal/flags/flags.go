@@ -37,6 +37,44 @@ func (b *Builder) WithControllerConfiguration(requeueDuration, healthzLivenessTi
 	return b
 }
 
+// WithReconciliationConfiguration sets how many Functions are reconciled concurrently and how fast they are requeued
+func (b *Builder) WithReconciliationConfiguration(maxConcurrentReconciles, rateLimiterBaseDelay, rateLimiterMaxDelay, rateLimiterFrequency, rateLimiterBurst string) *Builder {
+	optionalFlags := []struct {
+		key   string
+		value string
+	}{
+		{"maxConcurrentReconciles", maxConcurrentReconciles},
+		{"rateLimiter.baseDelay", rateLimiterBaseDelay},
+		{"rateLimiter.maxDelay", rateLimiterMaxDelay},
+		{"rateLimiter.frequency", rateLimiterFrequency},
+		{"rateLimiter.burst", rateLimiterBurst},
+	}
+
+	for _, flag := range optionalFlags {
+		if flag.value != "" {
+			fullPath := fmt.Sprintf("containers.manager.configuration.data.%s", flag.key)
+			b.With(fullPath, flag.value)
+		}
+	}
+
+	return b
+}
+
+// WithShardingConfiguration sets the number of controller replicas and how Functions are split between them
+func (b *Builder) WithShardingConfiguration(replicas, shards, shardingKey string) *Builder {
+	if replicas != "" {
+		b.With("containers.manager.replicas", replicas)
+	}
+	if shards != "" {
+		b.With("containers.manager.configuration.data.sharding.shards", shards)
+	}
+	if shardingKey != "" {
+		b.With("containers.manager.configuration.data.sharding.key", shardingKey)
+	}
+
+	return b
+}
+
 func (b *Builder) WithOptionalDependencies(publisherURL, traceCollectorURL string) *Builder {
 	b.With("containers.manager.configuration.data.functionTraceCollectorEndpoint", traceCollectorURL)
 	b.With("containers.manager.configuration.data.functionPublisherProxyAddress", publisherURL)
@@ -43,3 +43,81 @@ func TestWithFipsModeEnabled(t *testing.T) {
 		require.Equal(t, expected, flagsMap)
 	})
 }
+
+func TestWithReconciliationConfiguration(t *testing.T) {
+	t.Run("set all values", func(t *testing.T) {
+		fb := NewBuilder()
+		fb.WithReconciliationConfiguration("4", "500ms", "10m", "20", "200")
+
+		flagsMap, err := fb.Build()
+		require.NoError(t, err)
+
+		expected := map[string]interface{}{
+			"containers": map[string]interface{}{
+				"manager": map[string]interface{}{
+					"configuration": map[string]interface{}{
+						"data": map[string]interface{}{
+							"maxConcurrentReconciles": "4",
+							"rateLimiter": map[string]interface{}{
+								"baseDelay": "500ms",
+								"maxDelay":  "10m",
+								"frequency": "20",
+								"burst":     "200",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		require.Equal(t, expected, flagsMap)
+	})
+
+	t.Run("skip empty values", func(t *testing.T) {
+		fb := NewBuilder()
+		fb.WithReconciliationConfiguration("", "", "", "", "")
+
+		flagsMap, err := fb.Build()
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]interface{}{}, flagsMap)
+	})
+}
+
+func TestWithShardingConfiguration(t *testing.T) {
+	t.Run("set all values", func(t *testing.T) {
+		fb := NewBuilder()
+		fb.WithShardingConfiguration("3", "6", "label")
+
+		flagsMap, err := fb.Build()
+		require.NoError(t, err)
+
+		expected := map[string]interface{}{
+			"containers": map[string]interface{}{
+				"manager": map[string]interface{}{
+					"replicas": "3",
+					"configuration": map[string]interface{}{
+						"data": map[string]interface{}{
+							"sharding": map[string]interface{}{
+								"shards": "6",
+								"key":    "label",
+							},
+						},
+					},
+				},
+			},
+		}
+
+		require.Equal(t, expected, flagsMap)
+	})
+
+	t.Run("skip empty values", func(t *testing.T) {
+		fb := NewBuilder()
+		fb.WithShardingConfiguration("", "", "")
+
+		flagsMap, err := fb.Build()
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]interface{}{}, flagsMap)
+	})
+}
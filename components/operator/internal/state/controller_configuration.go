@@ -53,6 +53,14 @@ func updateControllerConfigurationStatus(ctx context.Context, r *reconciler, ins
 		{spec.DefaultRuntimePodPreset, &instance.Status.DefaultRuntimePodPreset, "Default runtime pod preset", defaultRuntimePreset},
 		{spec.LogLevel, &instance.Status.LogLevel, "Log level", defaultLogLevel},
 		{spec.LogFormat, &instance.Status.LogFormat, "Log format", defaultLogFormat},
+		{spec.FunctionMaxConcurrentReconciles, &instance.Status.MaxConcurrentReconciles, "Function max concurrent reconciles", ""},
+		{spec.FunctionRateLimiterBaseDelay, &instance.Status.RateLimiterBaseDelay, "Function rate limiter base delay", ""},
+		{spec.FunctionRateLimiterMaxDelay, &instance.Status.RateLimiterMaxDelay, "Function rate limiter max delay", ""},
+		{spec.FunctionRateLimiterFrequency, &instance.Status.RateLimiterFrequency, "Function rate limiter frequency", ""},
+		{spec.FunctionRateLimiterBurst, &instance.Status.RateLimiterBurst, "Function rate limiter burst", ""},
+		{spec.FunctionControllerReplicas, &instance.Status.ControllerReplicas, "Function controller replicas", ""},
+		{spec.FunctionShards, &instance.Status.Shards, "Function shards", ""},
+		{spec.FunctionShardingKey, &instance.Status.ShardingKey, "Function sharding key", ""},
 	}
 
 	updateStatusFields(r.k8s, instance, fields)
@@ -65,6 +73,18 @@ func configureControllerConfigurationFlags(s *systemState) {
 			s.instance.Status.RequeueDuration,
 			s.instance.Status.HealthzLivenessTimeout,
 		).
+		WithReconciliationConfiguration(
+			s.instance.Status.MaxConcurrentReconciles,
+			s.instance.Status.RateLimiterBaseDelay,
+			s.instance.Status.RateLimiterMaxDelay,
+			s.instance.Status.RateLimiterFrequency,
+			s.instance.Status.RateLimiterBurst,
+		).
+		WithShardingConfiguration(
+			s.instance.Status.ControllerReplicas,
+			s.instance.Status.Shards,
+			s.instance.Status.ShardingKey,
+		).
 		WithDefaultPresetFlags(
 			s.instance.Status.DefaultRuntimePodPreset,
 		).
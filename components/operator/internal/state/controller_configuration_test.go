@@ -15,11 +15,19 @@ import (
 )
 
 const (
-	requeueDurationTest        = "test-requeue-duration"
-	healthzLivenessTimeoutTest = "test-healthz-liveness-timeout"
-	runtimePodPresetTest       = "test-default-runtime-pod-preset"
-	logLevelTest               = "test-log-level"
-	logFormatTest              = "test-log-format"
+	requeueDurationTest         = "test-requeue-duration"
+	healthzLivenessTimeoutTest  = "test-healthz-liveness-timeout"
+	runtimePodPresetTest        = "test-default-runtime-pod-preset"
+	logLevelTest                = "test-log-level"
+	logFormatTest               = "test-log-format"
+	maxConcurrentReconcilesTest = "test-max-concurrent-reconciles"
+	rateLimiterBaseDelayTest    = "test-rate-limiter-base-delay"
+	rateLimiterMaxDelayTest     = "test-rate-limiter-max-delay"
+	rateLimiterFrequencyTest    = "test-rate-limiter-frequency"
+	rateLimiterBurstTest        = "test-rate-limiter-burst"
+	controllerReplicasTest      = "test-controller-replicas"
+	shardsTest                  = "test-shards"
+	shardingKeyTest             = "test-sharding-key"
 )
 
 func Test_sFnControllerConfiguration(t *testing.T) {
@@ -155,18 +163,26 @@ func Test_sFnControllerConfiguration(t *testing.T) {
 		s := &systemState{
 			instance: v1alpha1.Serverless{
 				Spec: v1alpha1.ServerlessSpec{
-					FunctionRequeueDuration: requeueDurationTest,
-					HealthzLivenessTimeout:  healthzLivenessTimeoutTest,
-					DefaultRuntimePodPreset: runtimePodPresetTest,
-					LogLevel:                logLevelTest,
-					LogFormat:               logFormatTest,
+					FunctionRequeueDuration:         requeueDurationTest,
+					HealthzLivenessTimeout:          healthzLivenessTimeoutTest,
+					DefaultRuntimePodPreset:         runtimePodPresetTest,
+					LogLevel:                        logLevelTest,
+					LogFormat:                       logFormatTest,
+					FunctionMaxConcurrentReconciles: maxConcurrentReconcilesTest,
+					FunctionRateLimiterBaseDelay:    rateLimiterBaseDelayTest,
+					FunctionRateLimiterMaxDelay:     rateLimiterMaxDelayTest,
+					FunctionRateLimiterFrequency:    rateLimiterFrequencyTest,
+					FunctionRateLimiterBurst:        rateLimiterBurstTest,
+					FunctionControllerReplicas:      controllerReplicasTest,
+					FunctionShards:                  shardsTest,
+					FunctionShardingKey:             shardingKeyTest,
 				},
 			},
 			flagsBuilder: flags.NewBuilder(),
 		}
 
 		c := fake.NewClientBuilder().Build()
-		eventRecorder := record.NewFakeRecorder(10)
+		eventRecorder := record.NewFakeRecorder(13)
 		r := &reconciler{log: zap.NewNop().Sugar(), k8s: k8s{client: c, EventRecorder: eventRecorder}}
 		next, result, err := sFnControllerConfiguration(context.TODO(), r, s)
 		require.Nil(t, err)
@@ -179,6 +195,14 @@ func Test_sFnControllerConfiguration(t *testing.T) {
 		require.Equal(t, runtimePodPresetTest, status.DefaultRuntimePodPreset)
 		require.Equal(t, logLevelTest, status.LogLevel)
 		require.Equal(t, logFormatTest, status.LogFormat)
+		require.Equal(t, maxConcurrentReconcilesTest, status.MaxConcurrentReconciles)
+		require.Equal(t, rateLimiterBaseDelayTest, status.RateLimiterBaseDelay)
+		require.Equal(t, rateLimiterMaxDelayTest, status.RateLimiterMaxDelay)
+		require.Equal(t, rateLimiterFrequencyTest, status.RateLimiterFrequency)
+		require.Equal(t, rateLimiterBurstTest, status.RateLimiterBurst)
+		require.Equal(t, controllerReplicasTest, status.ControllerReplicas)
+		require.Equal(t, shardsTest, status.Shards)
+		require.Equal(t, shardingKeyTest, status.ShardingKey)
 
 		require.Equal(t, v1alpha1.StateProcessing, status.State)
 		requireContainsCondition(t, status,
@@ -194,6 +218,14 @@ func Test_sFnControllerConfiguration(t *testing.T) {
 			"Normal Configuration Default runtime pod preset set from '' to 'test-default-runtime-pod-preset'",
 			"Normal Configuration Log level set from '' to 'test-log-level'",
 			"Normal Configuration Log format set from '' to 'test-log-format'",
+			"Normal Configuration Function max concurrent reconciles set from '' to 'test-max-concurrent-reconciles'",
+			"Normal Configuration Function rate limiter base delay set from '' to 'test-rate-limiter-base-delay'",
+			"Normal Configuration Function rate limiter max delay set from '' to 'test-rate-limiter-max-delay'",
+			"Normal Configuration Function rate limiter frequency set from '' to 'test-rate-limiter-frequency'",
+			"Normal Configuration Function rate limiter burst set from '' to 'test-rate-limiter-burst'",
+			"Normal Configuration Function controller replicas set from '' to 'test-controller-replicas'",
+			"Normal Configuration Function shards set from '' to 'test-shards'",
+			"Normal Configuration Function sharding key set from '' to 'test-sharding-key'",
 		}
 
 		for _, expectedEvent := range expectedEvents {
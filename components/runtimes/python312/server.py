@@ -34,6 +34,8 @@ func_port = 8080
 timeout = float(os.getenv('FUNC_TIMEOUT', 180))
 memfile_max = int(os.getenv('FUNC_MEMFILE_MAX', 100 * 1024 * 1024))
 bottle.BaseRequest.MEMFILE_MAX = memfile_max
+# maximum request body size in MB, no limit when not set
+body_size_limit = int(os.getenv('REQ_MB_LIMIT', 0)) * 1024 * 1024
 
 app = application = bottle.app()
 
@@ -43,7 +45,7 @@ function_context = {
     'timeout': timeout,
     'runtime': os.getenv('FUNC_RUNTIME'),
     'memory-limit': os.getenv('FUNC_MEMORY_LIMIT'),
-    'body-size-limit': memfile_max,
+    'body-size-limit': body_size_limit or memfile_max,
 }
 
 if __name__ == "__main__":
@@ -59,6 +61,19 @@ def func_with_context(e, function_context):
             return e
 
 
+def body_too_large(req):
+    if not body_size_limit:
+        return False
+    if req.content_length >= 0:
+        return req.content_length > body_size_limit
+    # chunked request, the size is known after reading the body
+    body = req.body
+    body.seek(0, os.SEEK_END)
+    size = body.tell()
+    body.seek(0)
+    return size > body_size_limit
+
+
 @app.get('/favicon.ico')
 def favicon():
     return bottle.HTTPResponse(status=204)
@@ -82,6 +97,9 @@ def exception_handler(err):
 @app.route('/<:re:.*>', method=['GET', 'POST', 'PATCH', 'DELETE'])
 def handler():
     req = bottle.request
+    if body_too_large(req):
+        return bottle.HTTPError(413, "Request body exceeds the limit of {} bytes".format(body_size_limit))
+
     event = Event(req, tracer)
 
     method = req.method